@@ -0,0 +1,292 @@
+// Package actionrunner runs the executables of action workflows locally, so they
+// can be tested without deploying them:
+//
+//	runner := actionrunner.New("./testdata/repositories")
+//	logs, err := runner.Run(ctx, file, models.Action{Executable: file.Path, Repository: &repo})
+//
+// Repositories are backed by local directories laid out as <dir>/<repository>/<branch>.
+// Go executables registered with Register run in-process; other Go executables run
+// with "go run" and JavaScript executables with Node.js. Subprocesses run in the
+// directory of the action's path and receive the action configuration through the
+// environment:
+//
+//	IRMIN_WORKFLOW_ID, IRMIN_WORKFLOW_RUN_ID
+//	IRMIN_REPOSITORY, IRMIN_BRANCH, IRMIN_PATH   (only if the action has a repository)
+//	IRMIN_REPOSITORY_DIR                         (local directory of the branch)
+//
+// Every line an executable writes to stdout or stderr becomes a log line, as in
+// WorkflowRunLogs.Logs. SQL executables run on the query engine and cannot be run locally.
+package actionrunner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/services"
+)
+
+// Env is what an in-process executable gets to work with.
+type Env struct {
+	// ID of the workflow being run
+	WorkflowID string
+	// ID of the run
+	RunID string
+	// Repository branch the action works on, or nil if the action has no repository
+	Repository *RepositoryView
+	// logs collects the log lines of the run
+	logs *logWriter
+}
+
+// Logf adds a line to the logs of the run.
+func (e *Env) Logf(format string, args ...interface{}) {
+	e.logs.Write([]byte(fmt.Sprintf(format, args...) + "\n"))
+}
+
+// Func is a Go executable run in-process.
+type Func func(ctx context.Context, env *Env) error
+
+// Runner runs action executables against local repositories.
+type Runner struct {
+	// Dir is the local directory holding the repositories, as <dir>/<repository>/<branch>
+	Dir string
+	// WorkflowID is reported to executables as the ID of the workflow. Defaults to "local"
+	WorkflowID string
+	// GoCommand is the go command used for Go executables that are not registered. Defaults to "go"
+	GoCommand string
+	// NodeCommand is the Node.js command used for JavaScript executables. Defaults to "node"
+	NodeCommand string
+	// Environ holds extra environment variables for subprocesses, as "KEY=value"
+	Environ []string
+
+	funcs map[string]Func
+}
+
+// New creates a new Runner for repositories in the given local directory.
+func New(dir string) *Runner {
+	return &Runner{
+		Dir:   dir,
+		funcs: make(map[string]Func),
+	}
+}
+
+// Register runs fn in-process whenever the Go executable at the given editor path is run.
+func (r *Runner) Register(executable string, fn Func) {
+	if r.funcs == nil {
+		r.funcs = make(map[string]Func)
+	}
+	r.funcs[path.Clean("/"+executable)] = fn
+}
+
+// LoadFile loads a local file as an editor file, so it can be run before it is uploaded.
+// The editor path of the file is its base name at the root of the editor files.
+func LoadFile(name string) (*models.EditorItemsFile, error) {
+	contents, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	base := filepath.Base(name)
+	return &models.EditorItemsFile{
+		Name:     base,
+		Path:     "/" + base,
+		Type:     models.IrminFileType(strings.TrimPrefix(filepath.Ext(base), ".")),
+		Contents: string(contents),
+	}, nil
+}
+
+// RunWorkflow runs the executable of an action workflow. See Run.
+func (r *Runner) RunWorkflow(ctx context.Context, file *models.EditorItemsFile, workflow models.Workflow) (*models.WorkflowRunLogs, error) {
+	action, ok := workflow.AsAction()
+	if !ok {
+		return nil, fmt.Errorf("workflow %s is not an action workflow", workflow.ID)
+	}
+	logs, err := r.run(ctx, file, *action, workflow.ID)
+	if logs != nil {
+		logs.Workflow = workflow
+	}
+	return logs, err
+}
+
+// Run runs an editor file as the executable of an action. The logs of the run are returned
+// even if it fails, in which case the error is a *services.WorkflowRunError, as returned by
+// WorkflowService.TriggerAndWait. Other errors mean the executable could not be started.
+func (r *Runner) Run(ctx context.Context, file *models.EditorItemsFile, action models.Action) (*models.WorkflowRunLogs, error) {
+	return r.run(ctx, file, action, "")
+}
+
+func (r *Runner) run(ctx context.Context, file *models.EditorItemsFile, action models.Action, workflowID string) (*models.WorkflowRunLogs, error) {
+	if workflowID == "" {
+		workflowID = r.WorkflowID
+	}
+	if workflowID == "" {
+		workflowID = "local"
+	}
+	env := &Env{
+		WorkflowID: workflowID,
+		RunID:      fmt.Sprintf("local-%d", time.Now().UnixNano()),
+		logs:       &logWriter{},
+	}
+	if action.Repository != nil {
+		branch := action.Repository.DefaultBranch
+		if action.Branch != nil && *action.Branch != "" {
+			branch = *action.Branch
+		}
+		if branch == "" {
+			branch = "main"
+		}
+		objectPath := "/"
+		if action.Path != nil {
+			objectPath = path.Clean("/" + *action.Path)
+		}
+		env.Repository = &RepositoryView{
+			Repository: action.Repository.Slug,
+			Branch:     branch,
+			Path:       objectPath,
+			Dir:        filepath.Join(r.Dir, action.Repository.Slug, branch),
+		}
+		if err := os.MkdirAll(env.Repository.Dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	execute, err := r.executor(file)
+	if err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	runErr := execute(ctx, env)
+	finished := time.Now().UTC().Format(time.RFC3339)
+
+	run := models.WorkflowRun{
+		ID:         env.RunID,
+		WorkflowID: workflowID,
+		Status:     models.WorkflowStatusComplete,
+		StartedAt:  started.UTC().Format(time.RFC3339),
+		FinishedAt: &finished,
+	}
+	if runErr != nil {
+		run.Status = models.WorkflowStatusError
+		env.Logf("%v", runErr)
+	}
+	logs := &models.WorkflowRunLogs{
+		WorkflowRun: run,
+		Workflow: models.Workflow{
+			WorkflowBase: models.WorkflowBase{ID: workflowID, Workflowable: action},
+			Type:         models.WorkflowableTypeAction,
+		},
+		Logs: env.logs.lines(),
+	}
+	if runErr != nil {
+		return logs, &services.WorkflowRunError{Run: run, Logs: logs.Logs}
+	}
+	return logs, nil
+}
+
+// executor returns how an editor file is run
+func (r *Runner) executor(file *models.EditorItemsFile) (Func, error) {
+	switch file.Type {
+	case models.IrminFileTypeGo:
+		if fn, ok := r.funcs[path.Clean("/"+file.Path)]; ok {
+			return fn, nil
+		}
+		command := r.GoCommand
+		if command == "" {
+			command = "go"
+		}
+		return r.subprocess(file, command, "run"), nil
+	case models.IrminFileTypeJS:
+		command := r.NodeCommand
+		if command == "" {
+			command = "node"
+		}
+		return r.subprocess(file, command), nil
+	case models.IrminFileTypeSQL:
+		return nil, fmt.Errorf("SQL executable %s runs on the query engine and cannot be run locally", file.Path)
+	default:
+		return nil, fmt.Errorf("unsupported executable type %q of %s", file.Type, file.Path)
+	}
+}
+
+// subprocess returns a Func running the contents of an editor file with a command
+func (r *Runner) subprocess(file *models.EditorItemsFile, command string, args ...string) Func {
+	return func(ctx context.Context, env *Env) error {
+		tmp, err := os.MkdirTemp("", "irmin-action-*")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp)
+
+		name := file.Name
+		if name == "" {
+			name = path.Base(file.Path)
+		}
+		source := filepath.Join(tmp, filepath.Base(name))
+		if err := os.WriteFile(source, []byte(file.Contents), 0o644); err != nil {
+			return err
+		}
+
+		cmd := exec.CommandContext(ctx, command, append(args, source)...)
+		cmd.Env = append(os.Environ(), r.Environ...)
+		cmd.Env = append(cmd.Env,
+			"IRMIN_WORKFLOW_ID="+env.WorkflowID,
+			"IRMIN_WORKFLOW_RUN_ID="+env.RunID,
+		)
+		cmd.Dir = tmp
+		if env.Repository != nil {
+			cmd.Dir = env.Repository.WorkDir()
+			cmd.Env = append(cmd.Env,
+				"IRMIN_REPOSITORY="+env.Repository.Repository,
+				"IRMIN_BRANCH="+env.Repository.Branch,
+				"IRMIN_PATH="+env.Repository.Path,
+				"IRMIN_REPOSITORY_DIR="+env.Repository.Dir,
+			)
+		}
+		// A single writer keeps stdout and stderr lines in the order they were written
+		cmd.Stdout = env.logs
+		cmd.Stderr = env.logs
+		return cmd.Run()
+	}
+}
+
+// logWriter splits written output into log lines
+type logWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	entries []string
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf.Write(p)
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			// Keep the incomplete line until the rest is written
+			w.buf.Reset()
+			w.buf.WriteString(line)
+			return len(p), nil
+		}
+		w.entries = append(w.entries, strings.TrimRight(line, "\r\n"))
+	}
+}
+
+// lines returns the log lines, including a last line without a line break
+func (w *logWriter) lines() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	lines := append([]string{}, w.entries...)
+	if w.buf.Len() > 0 {
+		lines = append(lines, w.buf.String())
+	}
+	return lines
+}
@@ -0,0 +1,141 @@
+package actionrunner
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"slices"
+	"testing"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/services"
+)
+
+// countExecutable logs the number of lines of cities.csv in the directory it runs in
+const countExecutable = `package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+func main() {
+	data, err := os.ReadFile("cities.csv")
+	if err != nil {
+		fmt.Println("cities.csv not found")
+		return
+	}
+	fmt.Println("lines:", strings.Count(string(data), "\n"))
+}
+`
+
+func testAction(objectPath string) models.Action {
+	branch := "main"
+	return models.Action{
+		Repository: &models.Repository{Slug: "test-repository"},
+		Branch:     &branch,
+		Path:       &objectPath,
+	}
+}
+
+func TestRunInProcess(t *testing.T) {
+	runner := New(t.TempDir())
+	runner.Register("/prepare.go", func(ctx context.Context, env *Env) error {
+		env.Logf("Writing cities to %s", env.Repository.Path)
+		return env.Repository.WriteFile(env.Repository.Path+"/cities.csv", []byte("name\nOslo\nBergen\n"))
+	})
+	runner.Register("/fail.go", func(ctx context.Context, env *Env) error {
+		if _, err := env.Repository.ReadFile("/missing.csv"); err != nil {
+			return errors.New("missing.csv not found")
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name      string
+		file      string
+		status    models.WorkflowStatus
+		logs      []string
+		wantError bool
+	}{
+		{
+			name:   "success",
+			file:   "/prepare.go",
+			status: models.WorkflowStatusComplete,
+			logs:   []string{"Writing cities to /lakes"},
+		},
+		{
+			name:      "failure",
+			file:      "/fail.go",
+			status:    models.WorkflowStatusError,
+			logs:      []string{"missing.csv not found"},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := &models.EditorItemsFile{Path: tt.file, Type: models.IrminFileTypeGo}
+			logs, err := runner.Run(context.Background(), file, testAction("/lakes"))
+			var runErr *services.WorkflowRunError
+			if tt.wantError != errors.As(err, &runErr) {
+				t.Fatalf("got error %v, expected a run error %t", err, tt.wantError)
+			}
+			if logs.WorkflowRun.Status != tt.status {
+				t.Errorf("got status %s, expected %s", logs.WorkflowRun.Status, tt.status)
+			}
+			if !slices.Equal(logs.Logs, tt.logs) {
+				t.Errorf("got logs %q, expected %q", logs.Logs, tt.logs)
+			}
+		})
+	}
+}
+
+func TestRunSubprocess(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+	dir := t.TempDir()
+	runner := New(dir)
+	runner.Register("/prepare.go", func(ctx context.Context, env *Env) error {
+		return env.Repository.WriteFile("/lakes/cities.csv", []byte("name\nOslo\nBergen\n"))
+	})
+	prepare := &models.EditorItemsFile{Path: "/prepare.go", Type: models.IrminFileTypeGo}
+	if _, err := runner.Run(context.Background(), prepare, testAction("/")); err != nil {
+		t.Fatalf("preparing repository: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "directory", path: "/lakes", expected: "lines: 3"},
+		{name: "object", path: "/lakes/cities.csv", expected: "lines: 3"},
+		// The action's path does not exist yet, so the process runs in its nearest existing parent
+		{name: "missing path", path: "/lakes/2025/report.csv", expected: "lines: 3"},
+		{name: "missing below root", path: "/reports", expected: "cities.csv not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count := &models.EditorItemsFile{Path: "/count.go", Type: models.IrminFileTypeGo, Contents: countExecutable}
+			logs, err := runner.Run(context.Background(), count, testAction(tt.path))
+			if err != nil {
+				t.Fatalf("running executable: %v", err)
+			}
+			if got := logs.Logs[len(logs.Logs)-1]; got != tt.expected {
+				t.Errorf("got last log line %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRunRejectsSQLExecutables(t *testing.T) {
+	runner := New(t.TempDir())
+	file := &models.EditorItemsFile{Path: "/report.sql", Type: models.IrminFileTypeSQL}
+	if _, err := runner.Run(context.Background(), file, testAction("/")); err == nil {
+		t.Error("expected SQL executables to be rejected")
+	}
+}
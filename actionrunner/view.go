@@ -0,0 +1,74 @@
+package actionrunner
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// RepositoryView is the local stand-in for the repository branch an action works on.
+// Object paths are slash separated and relative to the root of the branch.
+type RepositoryView struct {
+	// Slug of the repository
+	Repository string
+	// Branch of the repository
+	Branch string
+	// Path the action works on, e.g. "/" or "/lakes/2024.csv"
+	Path string
+	// Local directory holding the contents of the branch
+	Dir string
+}
+
+// FS returns the contents of the branch as a read-only file system.
+func (v *RepositoryView) FS() fs.FS {
+	return os.DirFS(v.Dir)
+}
+
+// ReadFile reads an object of the branch.
+func (v *RepositoryView) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(v.localPath(name))
+}
+
+// WriteFile writes an object of the branch, creating its directories as needed.
+func (v *RepositoryView) WriteFile(name string, data []byte) error {
+	local := v.localPath(name)
+	if err := os.MkdirAll(filepath.Dir(local), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(local, data, 0o644)
+}
+
+// Remove removes an object or directory of the branch.
+func (v *RepositoryView) Remove(name string) error {
+	return os.RemoveAll(v.localPath(name))
+}
+
+// WorkDir returns the local directory of the path the action works on. For paths of objects,
+// the directory containing the object is returned. If the path does not exist yet, the
+// nearest existing directory above it is returned, so the result can always be used as
+// the working directory of a process.
+func (v *RepositoryView) WorkDir() string {
+	root := filepath.Clean(v.Dir)
+	local := v.localPath(v.Path)
+	if info, err := os.Stat(local); err == nil && !info.IsDir() {
+		return filepath.Dir(local)
+	}
+	for local != root {
+		if info, err := os.Stat(local); err == nil && info.IsDir() {
+			return local
+		}
+		parent := filepath.Dir(local)
+		if parent == local {
+			break
+		}
+		local = parent
+	}
+	return root
+}
+
+// localPath maps an object path to a local path. Cleaning the path as an absolute
+// path removes any ".." elements, so the result cannot point outside the branch.
+func (v *RepositoryView) localPath(name string) string {
+	return filepath.Join(v.Dir, filepath.FromSlash(path.Clean("/"+name)))
+}
@@ -0,0 +1,40 @@
+package actionrunner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepositoryViewWorkDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "lakes", "2024"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "lakes", "2024", "oslo.csv"), []byte("name\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "root", path: "/", expected: dir},
+		{name: "directory", path: "/lakes/2024", expected: filepath.Join(dir, "lakes", "2024")},
+		{name: "object", path: "/lakes/2024/oslo.csv", expected: filepath.Join(dir, "lakes", "2024")},
+		{name: "missing object", path: "/lakes/2024/bergen.csv", expected: filepath.Join(dir, "lakes", "2024")},
+		{name: "missing directories", path: "/lakes/2025/01", expected: filepath.Join(dir, "lakes")},
+		{name: "missing below root", path: "/reports", expected: dir},
+		{name: "outside the branch", path: "/../../etc", expected: dir},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := &RepositoryView{Path: tt.path, Dir: dir}
+			if got := view.WorkDir(); got != tt.expected {
+				t.Errorf("got %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -2,13 +2,16 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -25,6 +28,9 @@ type Client struct {
 
 	// HTTPClient is a customisable HTTP client. You can set timeouts, proxies, etc.
 	HTTPClient *http.Client
+
+	// ctx is attached to every request sent by the client. Set it with WithContext.
+	ctx context.Context
 }
 
 // NewClient creates a new Irmin API client with default settings.
@@ -39,6 +45,22 @@ func NewClient(baseURL, token, locale string) *Client {
 	}
 }
 
+// WithContext returns a shallow copy of the client whose requests are bound to ctx.
+// Services created from the returned client stop their requests when ctx is cancelled.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	clone := *c
+	clone.ctx = ctx
+	return &clone
+}
+
+// Context returns the context attached to the client, or context.Background if none is set.
+func (c *Client) Context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
 // RequestOptions allows you to specify how you'd like to send data in the request.
 type RequestOptions struct {
 	Method      string
@@ -60,7 +82,7 @@ type FormFile struct {
 
 // Request is the main method that sends requests to the Irmin API and returns raw response data.
 func (c *Client) Request(opts RequestOptions) ([]byte, error) {
-	url := fmt.Sprintf("%s%s", c.BaseURL, opts.Endpoint)
+	requestURL := fmt.Sprintf("%s%s", c.BaseURL, opts.Endpoint)
 
 	var bodyReader io.Reader
 	headers := make(map[string]string)
@@ -136,17 +158,12 @@ func (c *Client) Request(opts RequestOptions) ([]byte, error) {
 		headers["Content-Type"] = writer.FormDataContentType()
 
 	case "application/x-www-form-urlencoded":
-		// Encode form fields as URL-encoded data
-		var buf bytes.Buffer
-		firstField := true
+		// Encode form fields as URL-encoded data, escaping reserved characters such as & and =
+		form := url.Values{}
 		for key, val := range opts.FormFields {
-			if !firstField {
-				buf.WriteByte('&')
-			}
-			buf.WriteString(fmt.Sprintf("%s=%s", key, val))
-			firstField = false
+			form.Set(key, val)
 		}
-		bodyReader = bytes.NewReader(buf.Bytes())
+		bodyReader = strings.NewReader(form.Encode())
 		headers["Content-Type"] = "application/x-www-form-urlencoded"
 
 	default:
@@ -165,7 +182,7 @@ func (c *Client) Request(opts RequestOptions) ([]byte, error) {
 	}
 
 	// Build the HTTP request
-	req, err := http.NewRequest(opts.Method, url, bodyReader)
+	req, err := http.NewRequestWithContext(c.Context(), opts.Method, requestURL, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -183,7 +200,7 @@ func (c *Client) Request(opts RequestOptions) ([]byte, error) {
 	// Perform the request
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request to %s failed: %w", url, err)
+		return nil, fmt.Errorf("request to %s failed: %w", requestURL, err)
 	}
 	defer resp.Body.Close()
 
@@ -195,7 +212,7 @@ func (c *Client) Request(opts RequestOptions) ([]byte, error) {
 
 	// Check for non-2xx status codes and include body in error
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API request failed with status %d. Body: %s", resp.StatusCode, responseBody)
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: responseBody}
 	}
 
 	return responseBody, nil
@@ -1,6 +1,29 @@
 package client
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// StatusError is returned for responses with a non-2xx status code
+type StatusError struct {
+	// HTTP status code of the response
+	StatusCode int
+	// Body of the response
+	Body []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API request failed with status %d. Body: %s", e.StatusCode, e.Body)
+}
+
+// IsNotFound reports whether an error is caused by a response with status 404 Not Found
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
 
 // IrminAPIPaginationMetadata represents the pagination metadata from the Irmin Core API
 type IrminAPIPaginationMetadata struct {
@@ -22,3 +45,25 @@ type IrminAPIResponse struct {
 	Errors   []string        `json:"errors,omitempty"`
 	Data     json.RawMessage `json:"data,omitempty"`
 }
+
+// Pagination decodes the response metadata as pagination metadata.
+// It returns nil if the response carries no pagination metadata.
+func (r *IrminAPIResponse) Pagination() (*IrminAPIPaginationMetadata, error) {
+	if r == nil || r.Metadata == nil || *r.Metadata == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(*r.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	var pagination IrminAPIPaginationMetadata
+	if err := json.Unmarshal(data, &pagination); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pagination metadata: %w", err)
+	}
+	if pagination.LastPage == 0 && pagination.CurrentPage == 0 {
+		return nil, nil
+	}
+	return &pagination, nil
+}
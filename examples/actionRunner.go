@@ -0,0 +1,89 @@
+package examples
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/IrminData/irmin-sdk-go/actionrunner"
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/services"
+)
+
+// exampleGoExecutable counts the lines of the objects in the directory it works on
+const exampleGoExecutable = `package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+func main() {
+	fmt.Println("Counting lines in", os.Getenv("IRMIN_REPOSITORY")+"@"+os.Getenv("IRMIN_BRANCH")+":"+os.Getenv("IRMIN_PATH"))
+	data, err := os.ReadFile("cities.csv")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Println("lines:", strings.Count(string(data), "\n"))
+}
+`
+
+// TestActionRunner tests running action executables against a local repository.
+func TestActionRunner() {
+	dir, err := os.MkdirTemp("", "irmin-repositories-*")
+	if err != nil {
+		fmt.Println("Error creating repository directory:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	runner := actionrunner.New(dir)
+	branch, objectPath := "main", "/lakes"
+	action := models.Action{
+		Repository: &models.Repository{Slug: "test-repository"},
+		Branch:     &branch,
+		Path:       &objectPath,
+	}
+
+	fmt.Println("Testing in-process executables...")
+	runner.Register("/prepare.go", func(ctx context.Context, env *actionrunner.Env) error {
+		env.Logf("Writing cities to %s", env.Repository.Path)
+		return env.Repository.WriteFile(env.Repository.Path+"/cities.csv", []byte("name\nOslo\nBergen\n"))
+	})
+	prepare := &models.EditorItemsFile{Name: "prepare.go", Path: "/prepare.go", Type: models.IrminFileTypeGo}
+	logs, err := runner.Run(ctx, prepare, action)
+	if err != nil {
+		fmt.Println("Error running in-process executable:", err)
+		return
+	}
+	fmt.Println(strings.Join(logs.Logs, "\n"))
+
+	fmt.Println("Testing subprocess executables...")
+	count := &models.EditorItemsFile{Name: "count.go", Path: "/count.go", Type: models.IrminFileTypeGo, Contents: exampleGoExecutable}
+	logs, err = runner.Run(ctx, count, action)
+	if err != nil {
+		fmt.Println("Error running subprocess executable:", err)
+		return
+	}
+	fmt.Println(strings.Join(logs.Logs, "\n"))
+
+	fmt.Println("Testing failing executables...")
+	runner.Register("/fail.go", func(ctx context.Context, env *actionrunner.Env) error {
+		if _, err := env.Repository.ReadFile("/missing.csv"); err != nil {
+			return errors.New("missing.csv not found")
+		}
+		return nil
+	})
+	fail := &models.EditorItemsFile{Name: "fail.go", Path: "/fail.go", Type: models.IrminFileTypeGo}
+	// Failed runs return their logs along with a *services.WorkflowRunError
+	_, err = runner.Run(ctx, fail, action)
+	var runErr *services.WorkflowRunError
+	if errors.As(err, &runErr) {
+		fmt.Println("Run failed:", runErr)
+	}
+}
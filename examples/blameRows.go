@@ -0,0 +1,33 @@
+package examples
+
+import (
+	"fmt"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// TestBlameRows tests attributing the rows of a structured object to the changes of its history.
+func TestBlameRows() {
+	// Newest first: change 0 updates Oslo, change 1 adds Stavanger, change 2 creates the object
+	var versions []*models.QueryResultTable
+	for _, data := range []string{
+		"id,name,population\n1,Oslo,717000\n2,Bergen,286000\n3,Stavanger,146000\n",
+		"id,name,population\n1,Oslo,709000\n2,Bergen,286000\n3,Stavanger,146000\n",
+		"id,name,population\n1,Oslo,709000\n2,Bergen,286000\n",
+		"id,name,population\n",
+	} {
+		table, err := utils.DecodeTable([]byte(data), utils.TableFormatCSV)
+		if err != nil {
+			fmt.Println("Error decoding CSV:", err)
+			return
+		}
+		versions = append(versions, table)
+	}
+
+	fmt.Println("Testing BlameRows...")
+	keys, origins, _ := utils.BlameRows(versions, []string{"id"})
+	for i, key := range keys {
+		fmt.Printf("%s: change %d\n", key, origins[i])
+	}
+}
@@ -0,0 +1,73 @@
+package examples
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// TestCommitGraph tests walking commit histories, merge bases, logs between commits and commit filters.
+func TestCommitGraph() {
+	// main: a <- b <- c, feature: b <- d <- e
+	commits := make(map[string]*models.CommitNode)
+	add := func(hash, previous, author, message, timestamp string) {
+		commit := models.Commit{Hash: hash, Author: author, Message: message, Timestamp: timestamp}
+		if previous != "" {
+			commit.PreviousHash = &previous
+		}
+		node := utils.ParseCommit(commit)
+		commits[hash] = &node
+	}
+	add("a", "", "Ada", "Initial commit", "2025-01-01T10:00:00Z")
+	add("b", "a", "Ada", "Add cities", "2025-01-02 10:00:00")
+	add("c", "b", "Grace", "Update population", "2025-01-03T10:00:00Z")
+	add("d", "b", "Grace", "Add Stavanger", "2025-01-04T10:00:00Z")
+	add("e", "d", "Linus", "Fix typo in cities", "2025-01-05T10:00:00Z")
+	lookup := func(hash string) (*models.CommitNode, error) {
+		commit, ok := commits[hash]
+		if !ok {
+			return nil, fmt.Errorf("commit %s not found", hash)
+		}
+		return commit, nil
+	}
+
+	fmt.Println("Testing MergeBase...")
+	base, err := utils.MergeBase("c", "e", lookup)
+	if err != nil {
+		fmt.Println("Error finding merge base:", err)
+		return
+	}
+	fmt.Println("Merge base of c and e:", base.Hash)
+
+	fmt.Println("Testing CommitsBetween...")
+	between, err := utils.CommitsBetween("c", "e", lookup)
+	if err != nil {
+		fmt.Println("Error listing commits:", err)
+		return
+	}
+	for _, commit := range between {
+		fmt.Printf("%s %s %s\n", commit.Hash, commit.Author, commit.Message)
+	}
+
+	fmt.Println("Testing IsAncestor...")
+	fastForward, err := utils.IsAncestor("c", "e", lookup)
+	if err != nil {
+		fmt.Println("Error checking ancestry:", err)
+		return
+	}
+	fmt.Println("e fast-forwards c:", fastForward)
+
+	fmt.Println("Testing CommitFilter...")
+	filter := models.CommitFilter{Message: regexp.MustCompile(`(?i)cities`)}
+	for commit, err := range utils.CommitAncestors("e", lookup) {
+		if err != nil {
+			fmt.Println("Error walking history:", err)
+			return
+		}
+		if filter.Matches(commit) {
+			fmt.Printf("%s %s\n", commit.Hash, commit.Message)
+		}
+	}
+}
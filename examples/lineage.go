@@ -0,0 +1,45 @@
+package examples
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// TestLineage tests the data lineage graph and its upstream and downstream queries.
+func TestLineage() {
+	var workflows []models.Workflow
+	if err := json.Unmarshal([]byte(exampleGraphWorkflows), &workflows); err != nil {
+		fmt.Println("Error decoding workflows:", err)
+		return
+	}
+
+	fmt.Println("Testing BuildLineageGraph...")
+	graph := utils.BuildLineageGraph(workflows)
+	for _, edge := range graph.Edges {
+		fmt.Printf("%s -> %s (%s)\n", edge.From, edge.To, edge.WorkflowName)
+	}
+
+	// An object within the reports directory is fed by the lakes import through the pipeline
+	fmt.Println("Testing LineageUpstream...")
+	for _, node := range utils.LineageUpstream(graph, "reports", "main", "/summary.csv").Nodes {
+		fmt.Println("Upstream:", node.ID)
+	}
+
+	// Objects imported into lakes end up in reports and leave through the export
+	fmt.Println("Testing LineageDownstream...")
+	for _, node := range utils.LineageDownstream(graph, "lakes", "main", "/Lakes.json").Nodes {
+		fmt.Println("Downstream:", node.ID)
+	}
+
+	data, err := json.MarshalIndent(utils.LineageToOpenLineage(graph, "irmin", time.Now()), "", "  ")
+	if err != nil {
+		fmt.Println("Error encoding OpenLineage events:", err)
+		return
+	}
+	fmt.Println("OpenLineage:")
+	fmt.Println(string(data))
+}
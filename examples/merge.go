@@ -0,0 +1,38 @@
+package examples
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// TestMerge tests three-way merging the rows of structured objects.
+func TestMerge() {
+	var tables []*models.QueryResultTable
+	for _, data := range []string{
+		"id,name,population\n1,Oslo,709000\n2,Bergen,286000\n3,Trondheim,212000\n",
+		"id,name,population\n1,Oslo,717000\n2,Bergen,290000\n",
+		"id,name,population\n1,Oslo,720000\n3,Trondheim,215000\n",
+	} {
+		table, err := utils.DecodeTable([]byte(data), utils.TableFormatCSV)
+		if err != nil {
+			fmt.Println("Error decoding CSV:", err)
+			return
+		}
+		tables = append(tables, table)
+	}
+
+	// Oslo was changed differently, Bergen was modified in base but deleted in compare, and
+	// Trondheim was deleted in base but modified in compare
+	fmt.Println("Testing MergeTables...")
+	merged, conflicts := utils.MergeTables(tables[0], tables[1], tables[2], []string{"id"}, models.TableConflictReport)
+	for _, conflict := range conflicts {
+		fmt.Printf("Conflict %s %q: ancestor %v, base %v, compare %v\n", conflict.Key, conflict.Column, conflict.Ancestor, conflict.Base, conflict.Compare)
+	}
+	fmt.Println("Merged:")
+	if err := utils.WriteCSV(os.Stdout, merged.Columns, merged.Rows); err != nil {
+		fmt.Println("Error writing CSV:", err)
+	}
+}
@@ -0,0 +1,39 @@
+package examples
+
+import (
+	"fmt"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// TestPipelineValidation tests the client-side checks of pipeline definitions.
+func TestPipelineValidation() {
+	// A live pipeline whose action has no executable and whose connection stage has no connection
+	fmt.Println("Testing ValidatePipelineDefinition...")
+	stages := []models.PipelineStage{
+		&models.PipelineStageRepository{
+			Type:             "repository",
+			Repository:       models.Repository{Slug: "lakes"},
+			Branch:           "main",
+			Path:             "/",
+			CommonProperties: models.CommonProperties{Read: true},
+		},
+		&models.PipelineStageAction{
+			Type:             "action",
+			CommonProperties: models.CommonProperties{Write: true, Read: true},
+		},
+		&models.PipelineStageConnection{
+			Type:             "connection",
+			CommonProperties: models.CommonProperties{Write: true},
+		},
+	}
+	schedule := &models.WorkflowSchedule{
+		Triggers: []models.WorkflowTrigger{
+			models.TimeTrigger{Type: "time", RRule: "FREQ=DAILY"},
+		},
+	}
+	for _, problem := range utils.ValidatePipelineDefinition(stages, true, schedule) {
+		fmt.Println("Problem:", problem)
+	}
+}
@@ -0,0 +1,39 @@
+package examples
+
+import (
+	"fmt"
+
+	"github.com/IrminData/irmin-sdk-go/irminsql"
+)
+
+// TestQueryBuilder tests the Irmin SQL query builder.
+func TestQueryBuilder() {
+	// Build a select statement over an object in a repository branch
+	fmt.Println("Testing SelectBuilder...")
+	content, err := irminsql.Select("name", "depth").
+		From(irminsql.Object("test-repository", "main", "/Lakes.json")).
+		Where("depth > :minDepth").
+		Where("name <> :excluded").
+		Set("minDepth", 100).
+		Set("excluded", "Lake Geneva").
+		OrderBy("depth", true).
+		Limit(10).
+		Render()
+	if err != nil {
+		fmt.Println("Error rendering select statement:", err)
+		return
+	}
+	fmt.Println("Rendered query:", content)
+
+	// Bound values are quoted as literals, so hostile input stays inside the string
+	fmt.Println("Testing Query with a hostile value...")
+	content, err = irminsql.NewQuery("SELECT * FROM :src WHERE name = :name").
+		Set("src", irminsql.Object("test-repository", "main", "/Lakes.json")).
+		Set("name", "x' OR '1'='1").
+		Render()
+	if err != nil {
+		fmt.Println("Error rendering query:", err)
+		return
+	}
+	fmt.Println("Rendered query:", content)
+}
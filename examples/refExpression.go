@@ -0,0 +1,25 @@
+package examples
+
+import (
+	"fmt"
+
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// TestRefExpression tests parsing relative ref expressions and recognising commit hashes.
+func TestRefExpression() {
+	fmt.Println("Testing ParseRefExpression...")
+	for _, expression := range []string{"main~3", "v1.2~2^^", "main^2"} {
+		name, generations, err := utils.ParseRefExpression(expression)
+		if err != nil {
+			fmt.Printf("%s: %v\n", expression, err)
+			continue
+		}
+		fmt.Printf("%s: %d generation(s) back from %s\n", expression, generations, name)
+	}
+
+	fmt.Println("Testing IsCommitHash...")
+	for _, ref := range []string{"a1b2c3d", "main"} {
+		fmt.Printf("%s is a commit hash: %t\n", ref, utils.IsCommitHash(ref))
+	}
+}
@@ -1,7 +1,10 @@
 package examples
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/IrminData/irmin-sdk-go/client"
@@ -83,4 +86,18 @@ func TestRepositories(baseURL, apiToken, locale string) {
 	}
 	fmt.Println(res.Message)
 	fmt.Println("Download URL:", *downloadUrl)
+
+	// Download and extract a snapshot of the test repository
+	dest, err := os.MkdirTemp("", "irmin-snapshot-")
+	if err != nil {
+		fmt.Println("Error creating snapshot directory:", err)
+		return
+	}
+	defer os.RemoveAll(dest)
+	snapshot, err := repositoryService.DownloadSnapshot(context.Background(), repository.Slug, "main", "/", filepath.Join(dest, "main"))
+	if err != nil {
+		fmt.Println("Error downloading snapshot:", err)
+		return
+	}
+	fmt.Printf("Snapshot: %d file(s) from a %d byte %s archive\n", len(snapshot.Files), snapshot.Size, snapshot.Format)
 }
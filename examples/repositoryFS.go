@@ -0,0 +1,54 @@
+package examples
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/services"
+)
+
+// TestRepositoryFS tests browsing the content of the test repository through io/fs.
+func TestRepositoryFS(baseURL, apiToken, locale string) {
+	// Initialise the client
+	apiClient := client.NewClient(baseURL, apiToken, locale)
+
+	// Resolve the branch to a commit, so the file system shows a consistent view of it
+	ref, err := services.NewRefResolver(apiClient).ResolveRef(context.Background(), "test-repository", "main")
+	if err != nil {
+		fmt.Println("Error resolving ref:", err)
+		return
+	}
+	fsys := services.NewRepositoryFS(apiClient, "test-repository", ref.Hash, services.RepositoryFSOptions{CacheSize: 1 << 20})
+
+	fmt.Println("Testing fs.WalkDir...")
+	var files []string
+	err = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s (%d bytes, modified %s)\n", p, info.Size(), info.ModTime())
+		files = append(files, p)
+		return nil
+	})
+	if err != nil {
+		fmt.Println("Error walking repository:", err)
+		return
+	}
+
+	if len(files) == 0 {
+		return
+	}
+	fmt.Println("Testing fs.ReadFile...")
+	content, err := fs.ReadFile(fsys, files[0])
+	if err != nil {
+		fmt.Println("Error reading file:", err)
+		return
+	}
+	fmt.Printf("%s: %s\n", files[0], content)
+}
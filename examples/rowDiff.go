@@ -0,0 +1,43 @@
+package examples
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// TestRowDiff tests decoding structured objects and diffing their rows.
+func TestRowDiff() {
+	fmt.Println("Testing DiffTables on CSV...")
+	base, err := utils.DecodeTable([]byte("id,name,population\n1,Oslo,709000\n2,Bergen,286000\n3,Trondheim,212000\n"), utils.TableFormatCSV)
+	if err != nil {
+		fmt.Println("Error decoding CSV:", err)
+		return
+	}
+	compare, err := utils.DecodeTable([]byte("id,name,population\n1,Oslo,717000\n3,Trondheim,212000\n4,Stavanger,146000\n"), utils.TableFormatCSV)
+	if err != nil {
+		fmt.Println("Error decoding CSV:", err)
+		return
+	}
+
+	// Align rows by their id, so a removed row does not shift the rows after it
+	csvDiff := utils.DiffTables(base, compare, []string{"id"})
+	csvDiff.Path, csvDiff.Format = "/cities.csv", string(utils.TableFormatCSV)
+	diff := &models.RowDiff{
+		Repository: "test-repository",
+		BaseRef:    "main",
+		CompareRef: "feature",
+		Objects:    []models.ObjectRowDiff{csvDiff},
+	}
+	fmt.Println("Text:")
+	if err := utils.WriteRowDiffText(os.Stdout, diff); err != nil {
+		fmt.Println("Error writing text:", err)
+		return
+	}
+	fmt.Println("JSON:")
+	if err := utils.WriteRowDiffJSON(os.Stdout, diff); err != nil {
+		fmt.Println("Error writing JSON:", err)
+	}
+}
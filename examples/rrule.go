@@ -0,0 +1,56 @@
+package examples
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/rrule"
+)
+
+// TestRRule tests the recurrence rule builder, validator and occurrence calculator.
+func TestRRule() {
+	// Build a rule for a workflow that runs at 02:00 on weekdays
+	fmt.Println("Testing rrule builder...")
+	rule := rrule.Daily().At(2, 0).Weekdays().String()
+	fmt.Println("Built rule:", rule)
+
+	// Calculate the next occurrences of the rule
+	fmt.Println("Testing NextOccurrences...")
+	next, err := rrule.NextOccurrences(rule, time.Now(), 3)
+	if err != nil {
+		fmt.Println("Error calculating occurrences:", err)
+		return
+	}
+	for _, occurrence := range next {
+		fmt.Println("Next occurrence:", occurrence.Format("Mon 2006-01-02 15:04 MST"))
+	}
+
+	// Parse a rule with a start date and time zone
+	fmt.Println("Testing Parse...")
+	parsed, err := rrule.Parse("DTSTART;TZID=America/New_York:19970905T090000\nRRULE:FREQ=MONTHLY;COUNT=10;BYDAY=1FR")
+	if err != nil {
+		fmt.Println("Error parsing rule:", err)
+		return
+	}
+	fmt.Println("First Fridays:", parsed.After(parsed.DTStart, 3))
+
+	// Invalid rules are rejected with an error naming the problem
+	if _, err := rrule.Parse("FREQ=DAILY;BYHOUR=25"); err != nil {
+		fmt.Println("Invalid rule:", err)
+	}
+
+	// Time triggers that fire closer together than the minimum interval are detected
+	fmt.Println("Testing ValidateSchedule...")
+	minInterval := 3600
+	schedule := models.WorkflowSchedule{
+		Triggers: []models.WorkflowTrigger{
+			&models.TimeTrigger{RRule: rrule.Daily().At(2, 0).String()},
+			&models.TimeTrigger{RRule: rrule.Daily().At(2, 30).String()},
+		},
+		MinInterval: &minInterval,
+	}
+	if err := rrule.ValidateSchedule(schedule, time.Now()); err != nil {
+		fmt.Println("Invalid schedule:", err)
+	}
+}
@@ -0,0 +1,69 @@
+package examples
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// TestSnapshotArchive tests browsing and extracting repository archives.
+func TestSnapshotArchive() {
+	// Build an archive as downloaded with RepositoryService.GetRepositoryDownloadLink
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"README.md":         "# Cities\n",
+		"cities/norway.csv": "id,name\n1,Oslo\n2,Bergen\n",
+		"cities/sweden.csv": "id,name\n1,Stockholm\n",
+	} {
+		f, err := w.Create(name)
+		if err != nil {
+			fmt.Println("Error creating archive:", err)
+			return
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		fmt.Println("Error creating archive:", err)
+		return
+	}
+	data := buf.Bytes()
+
+	fmt.Println("Testing ArchiveFS...")
+	fsys, err := utils.ArchiveFS(data)
+	if err != nil {
+		fmt.Println("Error reading archive:", err)
+		return
+	}
+	fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			fmt.Println("File:", p)
+		}
+		return err
+	})
+	content, err := fs.ReadFile(fsys, "cities/norway.csv")
+	if err != nil {
+		fmt.Println("Error reading file:", err)
+		return
+	}
+	fmt.Printf("cities/norway.csv:\n%s", content)
+
+	fmt.Println("Testing ExtractArchive...")
+	dest, err := os.MkdirTemp("", "irmin-archive-")
+	if err != nil {
+		fmt.Println("Error creating temporary directory:", err)
+		return
+	}
+	defer os.RemoveAll(dest)
+	extracted, err := utils.ExtractArchive(bytes.NewReader(data), int64(len(data)), filepath.Join(dest, "cities"))
+	if err != nil {
+		fmt.Println("Error extracting archive:", err)
+		return
+	}
+	fmt.Println("Extracted:", extracted)
+}
@@ -1,6 +1,7 @@
 package examples
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -128,6 +129,33 @@ func TestVersioningAndObjects(baseURL, apiToken, locale string) {
 	fmt.Println(res.Message)
 	fmt.Println("Created tag:", tag)
 
+	// Preview restoring the main branch to the commit before the merge
+	restore, err := services.NewRestorer(apiClient).Restore(context.Background(), "test-repository", "main", "/", "example-tag~1", services.RestoreOptions{DryRun: true})
+	if err != nil {
+		fmt.Println("Error previewing restore:", err)
+		return
+	}
+	for _, change := range restore.Changes {
+		fmt.Printf("Restoring %s to %s would mark %s as %s\n", restore.Path, restore.FromHash, change.Path, change.Type)
+	}
+
+	// Revert the commit adding Lakes.json on the main branch, then cherry-pick it back
+	applier := services.NewCommitApplier(apiClient)
+	reverted, err := applier.RevertCommit(context.Background(), "test-repository", "example-branch", "main")
+	if err != nil {
+		fmt.Println("Error reverting commit:", err)
+		return
+	}
+	fmt.Println("Reverted commit:", reverted.CommitMessage)
+	picked, err := applier.CherryPick(context.Background(), "test-repository", reverted.CommitHash, "main")
+	if err != nil {
+		fmt.Println("Error cherry-picking commit:", err)
+		return
+	}
+	for _, change := range picked.Changes {
+		fmt.Printf("Cherry-picked %s %s: %s\n", change.Type, change.Path, change.Status)
+	}
+
 	// Get objects on the main branch
 	objects, res, err := objectService.FetchObjects("test-repository", "/", "main")
 	if err != nil {
@@ -0,0 +1,53 @@
+package examples
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// TestWorkflowBundle tests how workflows are converted for a bundle and remapped for another workspace.
+func TestWorkflowBundle() {
+	var workflows []models.Workflow
+	if err := json.Unmarshal([]byte(exampleGraphWorkflows), &workflows); err != nil {
+		fmt.Println("Error decoding workflows:", err)
+		return
+	}
+
+	bundle := models.WorkflowBundle{Version: models.WorkflowBundleVersion}
+	for _, workflow := range workflows {
+		spec, err := utils.WorkflowToSpec(workflow)
+		if err != nil {
+			fmt.Println("Error converting workflow:", err)
+			return
+		}
+		bundle.Workflows = append(bundle.Workflows, models.WorkflowBundleWorkflow{ID: workflow.ID, Spec: spec})
+	}
+
+	fmt.Println("Testing WorkflowSpecReferences...")
+	connections, repositories, executables := utils.WorkflowSpecReferences(bundle.Workflows[1].Spec)
+	fmt.Println("Connections:", strings.Join(connections, ", "))
+	fmt.Println("Repositories:", strings.Join(repositories, ", "))
+	fmt.Println("Executables:", strings.Join(executables, ", "))
+
+	// Remap the references of the import workflow to those of another workspace
+	fmt.Println("Testing RemapWorkflowSpec...")
+	mapping := models.WorkflowBundleMapping{
+		Connections:  map[string]string{"c1": "prod-c1", "c2": "prod-c2"},
+		Repositories: map[string]string{"lakes": "prod-lakes"},
+		Workflows:    map[string]string{"3": "prod-3"},
+	}
+	imported := utils.RemapWorkflowSpec(bundle.Workflows[0].Spec, mapping)
+	fmt.Printf("Import: connection %s, repository %s\n", imported.Import.Connection, imported.Import.Repository)
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		fmt.Println("Error encoding bundle:", err)
+		return
+	}
+	fmt.Println("Bundle:")
+	fmt.Println(string(data))
+}
@@ -0,0 +1,69 @@
+package examples
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// exampleGraphWorkflows form a loop: the import writes to lakes@main, which triggers the
+// pipeline, which writes to reports@main, which triggers the export, whose run triggers the import.
+const exampleGraphWorkflows = `[
+	{
+		"id": "1", "name": "Import lakes", "type": "import",
+		"workflowable": {"connection": {"id": "c1"}, "repository": {"slug": "lakes"}, "branch": "main", "path": "/"},
+		"schedule": {"triggers": [{"type": "workflow-run-event", "event": "post-workflow-run", "workflow": "3"}]}
+	},
+	{
+		"id": "2", "name": "Transform", "type": "pipeline",
+		"workflowable": {"live": false, "stages": [
+			{"type": "repository", "read": true, "write": false, "repository": {"slug": "lakes"}, "branch": "main", "path": "/"},
+			{"type": "action", "read": true, "write": true, "executable": "/transform.js"},
+			{"type": "repository", "read": false, "write": true, "repository": {"slug": "reports"}, "branch": "main", "path": "/"}
+		]},
+		"schedule": {"triggers": [{"type": "repository-event", "event": "post-commit", "repository": "lakes", "ref": "main"}]}
+	},
+	{
+		"id": "3", "name": "Export reports", "type": "export",
+		"workflowable": {"connection": {"id": "c2"}, "repository": {"slug": "reports"}, "branch": "main", "path": "/", "recursive": true},
+		"schedule": {"triggers": [{"type": "repository-event", "event": "post-commit", "repository": "reports"}]}
+	},
+	{
+		"id": "4", "name": "Audit branches", "type": "action",
+		"workflowable": {"executable": "/audit.js"},
+		"schedule": {"triggers": [{"type": "repository-event", "event": "post-create-branch"}]}
+	}
+]`
+
+// TestWorkflowGraph tests the workflow dependency graph and its cycle detection.
+func TestWorkflowGraph() {
+	var workflows []models.Workflow
+	if err := json.Unmarshal([]byte(exampleGraphWorkflows), &workflows); err != nil {
+		fmt.Println("Error decoding workflows:", err)
+		return
+	}
+
+	fmt.Println("Testing BuildWorkflowGraph...")
+	graph := utils.BuildWorkflowGraph(workflows)
+	fmt.Printf("Graph has %d nodes and %d edges\n", len(graph.Nodes), len(graph.Edges))
+
+	for _, cycle := range graph.Cycles {
+		fmt.Println("Cycle:", strings.Join(cycle.Nodes, " -> "))
+	}
+
+	fmt.Println("DOT:")
+	fmt.Print(utils.WorkflowGraphToDOT(graph))
+	fmt.Println("Mermaid:")
+	fmt.Print(utils.WorkflowGraphToMermaid(graph))
+
+	data, err := json.MarshalIndent(graph, "", "  ")
+	if err != nil {
+		fmt.Println("Error encoding graph:", err)
+		return
+	}
+	fmt.Println("JSON:")
+	fmt.Println(string(data))
+}
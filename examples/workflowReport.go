@@ -0,0 +1,84 @@
+package examples
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// exampleReportWorkflow runs daily at 06:00 and may retry a failed run once
+const exampleReportWorkflow = `{
+	"id": "1", "name": "Daily import", "type": "import", "created_at": "2024-01-01T00:00:00Z",
+	"workflowable": {"connection": {"id": "c1"}, "repository": {"slug": "lakes"}, "branch": "main", "path": "/"},
+	"schedule": {
+		"triggers": [{"type": "time", "rrule": "FREQ=DAILY;BYHOUR=6;BYMINUTE=0;BYSECOND=0"}],
+		"max_retries": 1,
+		"max_runtime": 600
+	}
+}`
+
+// exampleReportRuns succeed on the 1st and 4th, fail and fail again on retry on the 2nd and miss the 3rd
+const exampleReportRuns = `[
+	{"id": "r1", "workflow_id": "1", "status": "complete", "started_at": "2024-03-01T06:00:05Z", "finished_at": "2024-03-01T06:05:05Z"},
+	{"id": "r2", "workflow_id": "1", "status": "error", "started_at": "2024-03-02T06:00:03Z", "finished_at": "2024-03-02T06:01:03Z"},
+	{"id": "r3", "workflow_id": "1", "status": "error", "started_at": "2024-03-02T06:02:00Z", "finished_at": "2024-03-02T06:14:00Z"},
+	{"id": "r4", "workflow_id": "1", "status": "complete", "started_at": "2024-03-04T06:00:10Z", "finished_at": "2024-03-04T06:02:10Z"}
+]`
+
+const exampleReportEvents = `[
+	{"id": "e1", "type": "ERROR", "timestamp": "2024-03-02T06:01:03Z", "description": "Import failed"},
+	{"id": "e2", "type": "WARNING", "timestamp": "2024-02-01T06:00:00Z", "description": "Slow connection"}
+]`
+
+// TestWorkflowReport tests the run statistics of a workflow and the report exports.
+func TestWorkflowReport() {
+	var workflow models.Workflow
+	var runs []models.WorkflowRun
+	var events []models.LogEvent
+	for _, decode := range []struct {
+		data   string
+		target interface{}
+	}{
+		{exampleReportWorkflow, &workflow},
+		{exampleReportRuns, &runs},
+		{exampleReportEvents, &events},
+	} {
+		if err := json.Unmarshal([]byte(decode.data), decode.target); err != nil {
+			fmt.Println("Error decoding example data:", err)
+			return
+		}
+	}
+
+	fmt.Println("Testing ComputeWorkflowRunStats...")
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	stats, err := utils.ComputeWorkflowRunStats(workflow, runs, events, start, end, 0)
+	if err != nil {
+		fmt.Println("Error computing statistics:", err)
+		return
+	}
+
+	fmt.Printf("Runs: %d, success rate: %.2f, p50: %.0fs, p95: %.0fs\n", stats.Runs, stats.SuccessRate, stats.DurationP50, stats.DurationP95)
+	fmt.Println("Missed windows:", strings.Join(stats.MissedWindows, ", "))
+
+	report := &models.WorkflowRunReport{
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		WindowStart: start.Format(time.RFC3339),
+		WindowEnd:   end.Format(time.RFC3339),
+		Workflows:   []models.WorkflowRunStats{stats},
+	}
+	fmt.Println("CSV:")
+	if err := utils.WriteWorkflowRunReportCSV(os.Stdout, report); err != nil {
+		fmt.Println("Error writing CSV:", err)
+		return
+	}
+	fmt.Println("JSON:")
+	if err := utils.WriteWorkflowRunReportJSON(os.Stdout, report); err != nil {
+		fmt.Println("Error writing JSON:", err)
+	}
+}
@@ -0,0 +1,88 @@
+package examples
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/services"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+const exampleWorkflowSpec = `
+workflows:
+  - key: example-import
+    name: Spec import
+    description: Import workflow managed by a spec file
+    type: import
+    import:
+      connection: %s
+      repository: test-repository
+      branch: main
+      path: /
+    schedule:
+      max_retries: 3
+      triggers:
+        - type: time
+          rrule: "FREQ=DAILY;BYHOUR=2;BYMINUTE=0"
+  - key: example-action
+    name: Spec action
+    type: action
+    action:
+      executable: /test.js
+      repository: test-repository
+      branch: main
+      path: /
+`
+
+func TestWorkflowSpec(exampleConnectionID, baseURL, apiToken, locale string) {
+	// Initialise the client and reconciler
+	apiClient := client.NewClient(baseURL, apiToken, locale)
+	reconciler := services.NewWorkflowReconciler(apiClient)
+	ctx := context.Background()
+
+	// Parse the spec file
+	file, err := utils.ParseWorkflowSpec([]byte(fmt.Sprintf(exampleWorkflowSpec, exampleConnectionID)))
+	if err != nil {
+		fmt.Println("Error parsing workflow spec:", err)
+		return
+	}
+
+	// Plan and apply the spec
+	plan, err := reconciler.Plan(ctx, file, services.WorkflowPlanOptions{})
+	if err != nil {
+		fmt.Println("Error planning workflows:", err)
+		return
+	}
+	fmt.Print(plan)
+
+	workflows, err := reconciler.Apply(ctx, plan)
+	if err != nil {
+		fmt.Println("Error applying workflow plan:", err)
+		return
+	}
+	fmt.Printf("Applied %d workflows\n", len(workflows))
+
+	// Planning again should not report any changes
+	plan, err = reconciler.Plan(ctx, file, services.WorkflowPlanOptions{})
+	if err != nil {
+		fmt.Println("Error planning workflows:", err)
+		return
+	}
+	fmt.Print(plan)
+
+	// Remove the workflows by applying an empty spec
+	file.Workflows = nil
+	plan, err = reconciler.Plan(ctx, file, services.WorkflowPlanOptions{})
+	if err != nil {
+		fmt.Println("Error planning workflows:", err)
+		return
+	}
+	fmt.Print(plan)
+
+	if _, err := reconciler.Apply(ctx, plan); err != nil {
+		fmt.Println("Error applying workflow plan:", err)
+		return
+	}
+	fmt.Println("Deleted the example spec workflows")
+}
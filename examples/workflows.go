@@ -122,6 +122,9 @@ func TestWorkflows(exampleConnectionID, baseURL, apiToken, locale string) {
 	}
 	for _, workflow := range workflows {
 		fmt.Printf("Workflow: %s (%s)\n", workflow.Name, workflow.Type)
+		if pipeline, ok := workflow.AsPipeline(); ok {
+			fmt.Printf("Pipeline stages: %d\n", len(pipeline.Stages))
+		}
 	}
 
 	// Update the description of the action workflow
@@ -133,13 +136,30 @@ func TestWorkflows(exampleConnectionID, baseURL, apiToken, locale string) {
 	fmt.Println(res.Message)
 
 	// Trigger execution of the action workflow
-	res, err = workflowService.TriggerWorkflowRun(actionWorkflow.ID)
+	run, res, err := workflowService.TriggerWorkflowRun(actionWorkflow.ID)
 	if err != nil {
 		fmt.Println("Error triggering action workflow run:", err)
 		return
 	}
 	fmt.Println(res.Message)
 
+	// Fetch the status of the triggered run
+	run, res, err = workflowService.FetchWorkflowRun(actionWorkflow.ID, run.ID)
+	if err != nil {
+		fmt.Println("Error fetching action workflow run:", err)
+		return
+	}
+	fmt.Println(res.Message)
+	fmt.Printf("Workflow run %s is %s\n", run.ID, run.Status)
+
+	// List all runs of the action workflow
+	runs, _, err := workflowService.ListWorkflowRuns(actionWorkflow.ID)
+	if err != nil {
+		fmt.Println("Error listing action workflow runs:", err)
+		return
+	}
+	fmt.Printf("Action workflow has %d runs\n", len(runs))
+
 	// Delete the created workflows
 	res, err = workflowService.DeleteWorkflow(actionWorkflow.ID)
 	if err != nil {
@@ -0,0 +1,187 @@
+package irminsql
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// bind replaces the placeholders in query with the quoted arguments.
+// It supports "?" and "$N" positional placeholders and ":name" and "@name"
+// named placeholders. Placeholders inside string literals, quoted identifiers
+// and comments are left untouched.
+func bind(query string, args []driver.NamedValue) (string, error) {
+	named := make(map[string]driver.Value)
+	var positional []driver.Value
+	for _, arg := range args {
+		if arg.Name != "" {
+			named[arg.Name] = arg.Value
+		} else {
+			positional = append(positional, arg.Value)
+		}
+	}
+
+	var b strings.Builder
+	src := []rune(query)
+	next := 0
+
+	for i := 0; i < len(src); i++ {
+		r := src[i]
+		switch {
+		case r == '\'' || r == '"' || r == '`':
+			// Copy the quoted section, including doubled quote escapes
+			end := skipQuoted(src, i, r)
+			b.WriteString(string(src[i:end]))
+			i = end - 1
+
+		case r == '-' && i+1 < len(src) && src[i+1] == '-':
+			end := i
+			for end < len(src) && src[end] != '\n' {
+				end++
+			}
+			b.WriteString(string(src[i:end]))
+			i = end - 1
+
+		case r == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := i + 2
+			for end < len(src) && !(src[end-1] == '*' && src[end] == '/' && end > i+2) {
+				end++
+			}
+			if end < len(src) {
+				end++
+			}
+			b.WriteString(string(src[i:end]))
+			i = end - 1
+
+		case r == '?':
+			if next >= len(positional) {
+				return "", fmt.Errorf("irminsql: missing argument for placeholder %d", next+1)
+			}
+			literal, err := quoteLiteral(positional[next])
+			if err != nil {
+				return "", fmt.Errorf("irminsql: argument %d: %w", next+1, err)
+			}
+			b.WriteString(literal)
+			next++
+
+		case r == '$' && i+1 < len(src) && unicode.IsDigit(src[i+1]):
+			end := i + 1
+			for end < len(src) && unicode.IsDigit(src[end]) {
+				end++
+			}
+			ordinal, _ := strconv.Atoi(string(src[i+1 : end]))
+			if ordinal < 1 || ordinal > len(positional) {
+				return "", fmt.Errorf("irminsql: missing argument for placeholder $%d", ordinal)
+			}
+			literal, err := quoteLiteral(positional[ordinal-1])
+			if err != nil {
+				return "", fmt.Errorf("irminsql: argument $%d: %w", ordinal, err)
+			}
+			b.WriteString(literal)
+			if ordinal > next {
+				next = ordinal
+			}
+			i = end - 1
+
+		case r == ':' && i+1 < len(src) && src[i+1] == ':':
+			// Type cast, e.g. value::INTEGER
+			b.WriteString("::")
+			i++
+
+		case (r == ':' || r == '@') && i+1 < len(src) && isNameStart(src[i+1]):
+			end := i + 1
+			for end < len(src) && isNamePart(src[end]) {
+				end++
+			}
+			name := string(src[i+1 : end])
+			value, ok := named[name]
+			if !ok {
+				return "", fmt.Errorf("irminsql: missing argument for placeholder %c%s", r, name)
+			}
+			literal, err := quoteLiteral(value)
+			if err != nil {
+				return "", fmt.Errorf("irminsql: argument %s: %w", name, err)
+			}
+			b.WriteString(literal)
+			i = end - 1
+
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	if next < len(positional) {
+		return "", fmt.Errorf("irminsql: expected %d arguments, got %d", next, len(positional))
+	}
+	return b.String(), nil
+}
+
+// skipQuoted returns the index just past the quoted section starting at start.
+// A doubled quote character inside the section is an escaped quote.
+func skipQuoted(src []rune, start int, quote rune) int {
+	for i := start + 1; i < len(src); i++ {
+		if src[i] != quote {
+			continue
+		}
+		if i+1 < len(src) && src[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(src)
+}
+
+// isNameStart reports whether r can start a placeholder name.
+func isNameStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// isNamePart reports whether r can be part of a placeholder name.
+func isNamePart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// quoteLiteral formats a driver value as an SQL literal.
+// Fragments such as identifiers and object references are rendered as is.
+func quoteLiteral(value driver.Value) (string, error) {
+	switch v := value.(type) {
+	case Fragment:
+		return v.SQL()
+	case nil:
+		return "NULL", nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", fmt.Errorf("cannot bind non-finite number %v", v)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		if v {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case string:
+		return quoteString(v)
+	case []byte:
+		return "X'" + hex.EncodeToString(v) + "'", nil
+	case time.Time:
+		return quoteString(v.Format(time.RFC3339Nano))
+	default:
+		return "", fmt.Errorf("unsupported argument type %T", value)
+	}
+}
+
+// quoteString quotes a string as an SQL string literal.
+func quoteString(s string) (string, error) {
+	if strings.ContainsRune(s, 0) {
+		return "", fmt.Errorf("string contains a NUL character")
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'", nil
+}
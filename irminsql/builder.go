@@ -0,0 +1,275 @@
+package irminsql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Fragment is a piece of SQL that is inserted into a query without being quoted
+// as a literal, such as an identifier or an object reference.
+type Fragment interface {
+	SQL() (string, error)
+}
+
+// Identifier is a column, table or alias name. It is always rendered quoted.
+type Identifier string
+
+// SQL renders the identifier as a quoted identifier.
+func (i Identifier) SQL() (string, error) {
+	return QuoteIdentifier(string(i))
+}
+
+// ObjectRef references a structured object in a repository, optionally at a branch.
+// It is rendered as a dotted sequence of quoted identifiers: "repository"."branch"."path".
+type ObjectRef struct {
+	Repository string
+	Branch     string
+	Path       string
+}
+
+// Object creates a reference to an object at a path in a repository branch.
+func Object(repository, branch, path string) ObjectRef {
+	return ObjectRef{Repository: repository, Branch: branch, Path: path}
+}
+
+// SQL renders the object reference.
+func (o ObjectRef) SQL() (string, error) {
+	if o.Repository == "" {
+		return "", fmt.Errorf("object reference is missing a repository")
+	}
+	if o.Path == "" {
+		return "", fmt.Errorf("object reference is missing a path")
+	}
+
+	parts := []string{o.Repository}
+	if o.Branch != "" {
+		parts = append(parts, o.Branch)
+	}
+	parts = append(parts, strings.TrimPrefix(o.Path, "/"))
+
+	quoted := make([]string, len(parts))
+	for i, part := range parts {
+		q, err := QuoteIdentifier(part)
+		if err != nil {
+			return "", err
+		}
+		quoted[i] = q
+	}
+	return strings.Join(quoted, "."), nil
+}
+
+// Raw is trusted SQL inserted verbatim. Never build it from user input.
+type Raw string
+
+// SQL returns the raw SQL.
+func (r Raw) SQL() (string, error) {
+	return string(r), nil
+}
+
+// QuoteIdentifier quotes a name as an SQL identifier, doubling embedded quotes.
+func QuoteIdentifier(name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("identifier is empty")
+	}
+	if strings.ContainsRune(name, 0) {
+		return "", fmt.Errorf("identifier contains a NUL character")
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`, nil
+}
+
+// QuoteLiteral quotes a Go value as an SQL literal.
+// Strings, numbers, booleans, byte slices, times and nil are supported, as well as Fragments.
+func QuoteLiteral(value interface{}) (string, error) {
+	if fragment, ok := value.(Fragment); ok {
+		return fragment.SQL()
+	}
+	converted, err := driver.DefaultParameterConverter.ConvertValue(value)
+	if err != nil {
+		return "", err
+	}
+	return quoteLiteral(converted)
+}
+
+// Query is an Irmin SQL statement with bound parameters.
+// Parameters are referenced as ":name" or "@name", or positionally as "?" or "$N".
+type Query struct {
+	content string
+	named   map[string]interface{}
+	args    []interface{}
+}
+
+// NewQuery creates a query from an SQL template.
+func NewQuery(content string) *Query {
+	return &Query{content: content, named: make(map[string]interface{})}
+}
+
+// Set binds a named parameter.
+func (q *Query) Set(name string, value interface{}) *Query {
+	q.named[name] = value
+	return q
+}
+
+// Args appends positional parameters.
+func (q *Query) Args(values ...interface{}) *Query {
+	q.args = append(q.args, values...)
+	return q
+}
+
+// Render binds all parameters and returns the final query content,
+// ready to be passed to QueryService.ExecuteScript or CreateQuery, which form-encode it.
+func (q *Query) Render() (string, error) {
+	values := make([]driver.NamedValue, 0, len(q.args)+len(q.named))
+	for i, arg := range q.args {
+		value, err := convertArg(arg)
+		if err != nil {
+			return "", fmt.Errorf("irminsql: argument %d: %w", i+1, err)
+		}
+		values = append(values, driver.NamedValue{Ordinal: i + 1, Value: value})
+	}
+
+	names := make([]string, 0, len(q.named))
+	for name := range q.named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, err := convertArg(q.named[name])
+		if err != nil {
+			return "", fmt.Errorf("irminsql: argument %s: %w", name, err)
+		}
+		values = append(values, driver.NamedValue{Name: name, Value: value})
+	}
+
+	return bind(q.content, values)
+}
+
+// convertArg converts a Go value into a value the binder can quote.
+func convertArg(value interface{}) (driver.Value, error) {
+	if fragment, ok := value.(Fragment); ok {
+		return fragment, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(value)
+}
+
+// SelectBuilder builds SELECT statements.
+type SelectBuilder struct {
+	columns []Fragment
+	source  Fragment
+	where   []string
+	orderBy []ordering
+	limit   int
+	params  map[string]interface{}
+}
+
+// ordering is a single ORDER BY term.
+type ordering struct {
+	column     Identifier
+	descending bool
+}
+
+// Select starts a SELECT statement for the given columns. No columns, or a "*" column, selects every column.
+func Select(columns ...string) *SelectBuilder {
+	b := &SelectBuilder{params: make(map[string]interface{})}
+	for _, column := range columns {
+		if column == "*" {
+			b.columns = append(b.columns, Raw("*"))
+			continue
+		}
+		b.columns = append(b.columns, Identifier(column))
+	}
+	return b
+}
+
+// From sets the source of the statement, usually an ObjectRef.
+func (b *SelectBuilder) From(source Fragment) *SelectBuilder {
+	b.source = source
+	return b
+}
+
+// Where adds a condition. Conditions are combined with AND and may reference named parameters.
+func (b *SelectBuilder) Where(condition string) *SelectBuilder {
+	b.where = append(b.where, condition)
+	return b
+}
+
+// Set binds a named parameter used by a condition.
+func (b *SelectBuilder) Set(name string, value interface{}) *SelectBuilder {
+	b.params[name] = value
+	return b
+}
+
+// OrderBy adds an ordering on a column.
+func (b *SelectBuilder) OrderBy(column string, descending bool) *SelectBuilder {
+	b.orderBy = append(b.orderBy, ordering{column: Identifier(column), descending: descending})
+	return b
+}
+
+// Limit limits the number of returned rows. Zero means no limit.
+func (b *SelectBuilder) Limit(limit int) *SelectBuilder {
+	b.limit = limit
+	return b
+}
+
+// Render returns the final query content.
+func (b *SelectBuilder) Render() (string, error) {
+	if b.source == nil {
+		return "", fmt.Errorf("irminsql: select statement has no source")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("SELECT ")
+	if len(b.columns) == 0 {
+		sb.WriteString("*")
+	}
+	for i, column := range b.columns {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		quoted, err := column.SQL()
+		if err != nil {
+			return "", fmt.Errorf("irminsql: %w", err)
+		}
+		sb.WriteString(quoted)
+	}
+
+	source, err := b.source.SQL()
+	if err != nil {
+		return "", fmt.Errorf("irminsql: %w", err)
+	}
+	sb.WriteString(" FROM ")
+	sb.WriteString(source)
+
+	if len(b.where) > 0 {
+		sb.WriteString(" WHERE (")
+		sb.WriteString(strings.Join(b.where, ") AND ("))
+		sb.WriteString(")")
+	}
+	for i, order := range b.orderBy {
+		if i == 0 {
+			sb.WriteString(" ORDER BY ")
+		} else {
+			sb.WriteString(", ")
+		}
+		quoted, err := order.column.SQL()
+		if err != nil {
+			return "", fmt.Errorf("irminsql: %w", err)
+		}
+		sb.WriteString(quoted)
+		if order.descending {
+			sb.WriteString(" DESC")
+		} else {
+			sb.WriteString(" ASC")
+		}
+	}
+	if b.limit > 0 {
+		sb.WriteString(fmt.Sprintf(" LIMIT %d", b.limit))
+	}
+
+	query := NewQuery(sb.String())
+	for name, value := range b.params {
+		query.Set(name, value)
+	}
+	return query.Render()
+}
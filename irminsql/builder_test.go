@@ -0,0 +1,135 @@
+package irminsql
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSelectRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *SelectBuilder
+		expected string
+	}{
+		{
+			name:     "no columns",
+			builder:  Select().From(Object("repo", "main", "/Lakes.json")),
+			expected: `SELECT * FROM "repo"."main"."Lakes.json"`,
+		},
+		{
+			name:     "star column",
+			builder:  Select("*").From(Object("repo", "main", "/Lakes.json")),
+			expected: `SELECT * FROM "repo"."main"."Lakes.json"`,
+		},
+		{
+			name:     "star with quoted columns",
+			builder:  Select("*", "depth").From(Object("repo", "", "Lakes.json")),
+			expected: `SELECT *, "depth" FROM "repo"."Lakes.json"`,
+		},
+		{
+			name: "conditions, ordering and limit",
+			builder: Select("name", "depth").
+				From(Object("repo", "main", "/Lakes.json")).
+				Where("depth > :minDepth").
+				Where("name <> :excluded").
+				Set("minDepth", 100).
+				Set("excluded", "Lake Geneva").
+				OrderBy("depth", true).
+				Limit(10),
+			expected: `SELECT "name", "depth" FROM "repo"."main"."Lakes.json" WHERE (depth > 100) AND (name <> 'Lake Geneva') ORDER BY "depth" DESC LIMIT 10`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := tt.builder.Render()
+			if err != nil {
+				t.Fatalf("rendering: %v", err)
+			}
+			if rendered != tt.expected {
+				t.Errorf("got %s, expected %s", rendered, tt.expected)
+			}
+		})
+	}
+}
+
+func TestQueryRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    *Query
+		expected string
+	}{
+		{
+			name:     "quote breakout in literal",
+			query:    NewQuery("SELECT * FROM t WHERE name = :name").Set("name", "x' OR '1'='1"),
+			expected: `SELECT * FROM t WHERE name = 'x'' OR ''1''=''1'`,
+		},
+		{
+			name:     "statement stacking in literal",
+			query:    NewQuery("SELECT * FROM t WHERE name = ?").Args("a'; DROP TABLE t; --"),
+			expected: `SELECT * FROM t WHERE name = 'a''; DROP TABLE t; --'`,
+		},
+		{
+			name:     "quote breakout in identifier",
+			query:    NewQuery("SELECT :col FROM t").Set("col", Identifier(`a" FROM secrets --`)),
+			expected: `SELECT "a"" FROM secrets --" FROM t`,
+		},
+		{
+			name:     "quote breakout in object path",
+			query:    NewQuery("SELECT * FROM :src").Set("src", Object("repo", "main", `/x".y`)),
+			expected: `SELECT * FROM "repo"."main"."x"".y"`,
+		},
+		{
+			name:     "placeholder inside value is not expanded",
+			query:    NewQuery("SELECT * FROM t WHERE a = :a AND b = :b").Set("a", ":b").Set("b", "?"),
+			expected: `SELECT * FROM t WHERE a = ':b' AND b = '?'`,
+		},
+		{
+			name:     "placeholders in strings and comments are ignored",
+			query:    NewQuery("SELECT ':a', \"?\" -- :a ?\nFROM t /* :a */ WHERE a = :a").Set("a", int64(1)),
+			expected: "SELECT ':a', \"?\" -- :a ?\nFROM t /* :a */ WHERE a = 1",
+		},
+		{
+			name:     "form field separators in literal",
+			query:    NewQuery("SELECT * FROM t WHERE name = ?").Args("x&content=DROP TABLE t"),
+			expected: `SELECT * FROM t WHERE name = 'x&content=DROP TABLE t'`,
+		},
+		{
+			name:     "typed literals",
+			query:    NewQuery("SELECT ?, ?, ?, ?, ?").Args(nil, true, 2.5, []byte{0xde, 0xad}, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+			expected: `SELECT NULL, TRUE, 2.5, X'dead', '2024-01-02T03:04:05Z'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := tt.query.Render()
+			if err != nil {
+				t.Fatalf("rendering: %v", err)
+			}
+			if rendered != tt.expected {
+				t.Errorf("got %s, expected %s", rendered, tt.expected)
+			}
+		})
+	}
+}
+
+func TestQueryRenderRejectsUnsafeValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *Query
+	}{
+		{name: "NUL byte in literal", query: NewQuery("SELECT :v").Set("v", "nul\x00byte")},
+		{name: "empty identifier", query: NewQuery("SELECT :v").Set("v", Identifier(""))},
+		{name: "missing named argument", query: NewQuery("SELECT :missing")},
+		{name: "extra positional argument", query: NewQuery("SELECT ?").Args(1, 2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rendered, err := tt.query.Render(); err == nil {
+				t.Errorf("expected an error, got %s", rendered)
+			}
+		})
+	}
+}
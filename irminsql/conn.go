@@ -0,0 +1,174 @@
+package irminsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/services"
+)
+
+// scriptType is the query type used for every statement.
+const scriptType = string(models.IrminFileTypeSQL)
+
+// errTransactions is returned by Begin, as Irmin SQL has no transactions.
+var errTransactions = errors.New("irminsql: transactions are not supported")
+
+// conn implements driver.Conn over the Irmin query API.
+type conn struct {
+	client *client.Client
+	closed bool
+}
+
+var (
+	_ driver.Conn               = (*conn)(nil)
+	_ driver.ConnPrepareContext = (*conn)(nil)
+	_ driver.QueryerContext     = (*conn)(nil)
+	_ driver.ExecerContext      = (*conn)(nil)
+	_ driver.NamedValueChecker  = (*conn)(nil)
+	_ driver.Validator          = (*conn)(nil)
+)
+
+// Prepare returns a prepared statement. Arguments are bound when it is executed.
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext returns a prepared statement. Arguments are bound when it is executed.
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if c.closed {
+		return nil, driver.ErrBadConn
+	}
+	return &stmt{conn: c, query: query}, nil
+}
+
+// Close marks the connection as closed. There is no server-side session to release.
+func (c *conn) Close() error {
+	c.closed = true
+	return nil
+}
+
+// Begin is not supported.
+func (c *conn) Begin() (driver.Tx, error) {
+	return nil, errTransactions
+}
+
+// IsValid reports whether the connection can be reused.
+func (c *conn) IsValid() bool {
+	return !c.closed
+}
+
+// CheckNamedValue accepts every value the binder knows how to quote.
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	if _, ok := nv.Value.(Fragment); ok {
+		return nil
+	}
+	value, err := driver.DefaultParameterConverter.ConvertValue(nv.Value)
+	if err != nil {
+		return err
+	}
+	nv.Value = value
+	return nil
+}
+
+// QueryContext binds the arguments, executes the statement and returns its rows.
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	result, err := c.execute(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := result.Table()
+	if err != nil {
+		return nil, fmt.Errorf("irminsql: %w", err)
+	}
+	return newRows(table), nil
+}
+
+// ExecContext binds the arguments and executes the statement, discarding any rows.
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if _, err := c.execute(ctx, query, args); err != nil {
+		return nil, err
+	}
+	return driver.ResultNoRows, nil
+}
+
+// execute binds the arguments and sends the statement to the query API.
+func (c *conn) execute(ctx context.Context, query string, args []driver.NamedValue) (*models.QueryExecutionResult, error) {
+	if c.closed {
+		return nil, driver.ErrBadConn
+	}
+
+	// Statements without arguments are sent unchanged
+	content := query
+	if len(args) > 0 {
+		bound, err := bind(query, args)
+		if err != nil {
+			return nil, err
+		}
+		content = bound
+	}
+
+	queryService := services.NewQueryService(c.client.WithContext(ctx))
+	result, _, err := queryService.ExecuteScript(scriptType, content)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, fmt.Errorf("irminsql: %w", err)
+	}
+	return result, nil
+}
+
+// stmt implements driver.Stmt. The query is sent again on every execution.
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+var (
+	_ driver.StmtQueryContext = (*stmt)(nil)
+	_ driver.StmtExecContext  = (*stmt)(nil)
+)
+
+// Close releases nothing, as statements are not prepared server-side.
+func (s *stmt) Close() error {
+	return nil
+}
+
+// NumInput returns -1 so that database/sql does not check the argument count.
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+// Exec executes the statement with positional arguments.
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+// Query executes the statement with positional arguments.
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+// ExecContext executes the statement.
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+// QueryContext executes the statement and returns its rows.
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+// namedValues converts positional values to named values.
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
@@ -0,0 +1,58 @@
+package irminsql
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExecuteSendsBoundContentAsSingleField(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{
+			name:     "form field injection",
+			value:    "x&content=DROP TABLE t",
+			expected: `SELECT * FROM t WHERE name = 'x&content=DROP TABLE t'`,
+		},
+		{
+			name:     "reserved form characters",
+			value:    "a+b=100% ; c",
+			expected: `SELECT * FROM t WHERE name = 'a+b=100% ; c'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contents []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					t.Errorf("parsing form: %v", err)
+				}
+				contents = r.PostForm["content"]
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"data":{"result":[],"execution_time":1,"logs":[]}}`))
+			}))
+			defer server.Close()
+
+			cfg := &Config{BaseURL: server.URL, Token: "token", Locale: "en"}
+			db := sql.OpenDB(NewConnector(cfg))
+			defer db.Close()
+
+			if _, err := db.ExecContext(context.Background(), "SELECT * FROM t WHERE name = ?", tt.value); err != nil {
+				t.Fatalf("executing statement: %v", err)
+			}
+			if len(contents) != 1 {
+				t.Fatalf("expected a single content field, got %d: %q", len(contents), strings.Join(contents, " | "))
+			}
+			if contents[0] != tt.expected {
+				t.Errorf("got content %q, expected %q", contents[0], tt.expected)
+			}
+		})
+	}
+}
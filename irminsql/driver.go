@@ -0,0 +1,80 @@
+// Package irminsql provides a database/sql driver for Irmin SQL.
+//
+// Statements are sent through the Irmin Core API as "sql" scripts:
+//
+//	db, err := sql.Open("irminsql", "https://<token>@api.irmin.dev?locale=en&workspace=<slug>")
+//
+// Arguments are bound client-side. Both "?" and named (":name" or "@name")
+// placeholders are supported and values are quoted as SQL literals. Statements
+// executed without arguments are sent unchanged.
+package irminsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/services"
+)
+
+// DriverName is the name the driver is registered under.
+const DriverName = "irminsql"
+
+func init() {
+	sql.Register(DriverName, &Driver{})
+}
+
+// Driver implements driver.Driver for Irmin SQL.
+type Driver struct{}
+
+// Open opens a new connection using the given DSN.
+func (d *Driver) Open(dsn string) (driver.Conn, error) {
+	connector, err := d.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return connector.Connect(context.Background())
+}
+
+// OpenConnector parses the DSN once and returns a connector for it.
+func (d *Driver) OpenConnector(dsn string) (driver.Connector, error) {
+	cfg, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return NewConnector(cfg), nil
+}
+
+// connector implements driver.Connector.
+type connector struct {
+	cfg *Config
+}
+
+// NewConnector creates a connector from a configuration, for use with sql.OpenDB.
+func NewConnector(cfg *Config) driver.Connector {
+	return &connector{cfg: cfg}
+}
+
+// Connect creates a new connection and switches to the configured workspace.
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	apiClient := client.NewClient(c.cfg.BaseURL, c.cfg.Token, c.cfg.Locale)
+	if c.cfg.Timeout > 0 {
+		apiClient.HTTPClient.Timeout = c.cfg.Timeout
+	}
+
+	if c.cfg.Workspace != "" {
+		workspaceService := services.NewWorkspaceService(apiClient.WithContext(ctx))
+		if _, err := workspaceService.SwitchWorkspace(c.cfg.Workspace); err != nil {
+			return nil, fmt.Errorf("irminsql: %w", err)
+		}
+	}
+
+	return &conn{client: apiClient}, nil
+}
+
+// Driver returns the underlying driver.
+func (c *connector) Driver() driver.Driver {
+	return &Driver{}
+}
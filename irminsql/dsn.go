@@ -0,0 +1,89 @@
+package irminsql
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Config holds the connection settings parsed from a DSN.
+type Config struct {
+	// BaseURL is the Irmin Core API base, e.g. "https://api.irmin.dev"
+	BaseURL string
+	// Token is the Irmin Core API token
+	Token string
+	// Locale is used to request localised messages from the Irmin Core API
+	Locale string
+	// Workspace is the slug of the workspace to switch to when connecting (optional)
+	Workspace string
+	// Timeout is the HTTP timeout for a single statement (optional)
+	Timeout time.Duration
+}
+
+// ParseDSN parses a DSN of the form
+//
+//	https://<token>@api.irmin.dev?locale=en&workspace=<slug>&timeout=30s
+//
+// The token may also be passed as a "token" query parameter. Any path in the
+// URL is kept as part of the base URL.
+func ParseDSN(dsn string) (*Config, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DSN: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid DSN: unsupported scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid DSN: missing host")
+	}
+
+	query := u.Query()
+	cfg := &Config{
+		Locale:    query.Get("locale"),
+		Workspace: query.Get("workspace"),
+		Token:     query.Get("token"),
+	}
+	if u.User != nil {
+		cfg.Token = u.User.Username()
+	}
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("invalid DSN: missing token")
+	}
+	if cfg.Locale == "" {
+		cfg.Locale = "en"
+	}
+	if timeout := query.Get("timeout"); timeout != "" {
+		cfg.Timeout, err = time.ParseDuration(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DSN: invalid timeout %q: %w", timeout, err)
+		}
+	}
+
+	base := url.URL{Scheme: u.Scheme, Host: u.Host, Path: strings.TrimSuffix(u.Path, "/")}
+	cfg.BaseURL = base.String()
+	return cfg, nil
+}
+
+// FormatDSN formats the configuration as a DSN accepted by ParseDSN.
+func (c *Config) FormatDSN() string {
+	u, err := url.Parse(c.BaseURL)
+	if err != nil {
+		u = &url.URL{}
+	}
+	u.User = url.User(c.Token)
+
+	query := url.Values{}
+	if c.Locale != "" {
+		query.Set("locale", c.Locale)
+	}
+	if c.Workspace != "" {
+		query.Set("workspace", c.Workspace)
+	}
+	if c.Timeout > 0 {
+		query.Set("timeout", c.Timeout.String())
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
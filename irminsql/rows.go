@@ -0,0 +1,180 @@
+package irminsql
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// columnType describes the type inferred for a result column.
+type columnType struct {
+	databaseType string
+	scanType     reflect.Type
+	nullable     bool
+}
+
+var (
+	scanTypeInt64   = reflect.TypeOf(int64(0))
+	scanTypeFloat64 = reflect.TypeOf(float64(0))
+	scanTypeBool    = reflect.TypeOf(false)
+	scanTypeString  = reflect.TypeOf("")
+	scanTypeAny     = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
+// rows implements driver.Rows over a query result table.
+type rows struct {
+	columns []string
+	types   []columnType
+	values  [][]models.JSONValue
+	next    int
+}
+
+var (
+	_ driver.Rows                           = (*rows)(nil)
+	_ driver.RowsColumnTypeScanType         = (*rows)(nil)
+	_ driver.RowsColumnTypeDatabaseTypeName = (*rows)(nil)
+	_ driver.RowsColumnTypeNullable         = (*rows)(nil)
+)
+
+// newRows creates rows from a table and infers the type of every column.
+func newRows(table *models.QueryResultTable) *rows {
+	r := &rows{
+		columns: table.Columns,
+		types:   make([]columnType, len(table.Columns)),
+		values:  table.Rows,
+	}
+	for i := range table.Columns {
+		r.types[i] = inferColumnType(table.Rows, i)
+	}
+	return r
+}
+
+// Columns returns the column names.
+func (r *rows) Columns() []string {
+	return r.columns
+}
+
+// Close discards any remaining rows.
+func (r *rows) Close() error {
+	r.next = len(r.values)
+	return nil
+}
+
+// Next copies the next row into dest.
+func (r *rows) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	row := r.values[r.next]
+	r.next++
+
+	for i := range dest {
+		var value models.JSONValue
+		if i < len(row) {
+			value = row[i]
+		}
+		converted, err := convertValue(value, r.types[i])
+		if err != nil {
+			return err
+		}
+		dest[i] = converted
+	}
+	return nil
+}
+
+// ColumnTypeScanType returns the Go type values of the column can be scanned into.
+func (r *rows) ColumnTypeScanType(index int) reflect.Type {
+	return r.types[index].scanType
+}
+
+// ColumnTypeDatabaseTypeName returns the inferred database type of the column.
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	return r.types[index].databaseType
+}
+
+// ColumnTypeNullable reports whether the column contains null values.
+func (r *rows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	return r.types[index].nullable, true
+}
+
+// inferColumnType infers a column type from every value in the column.
+func inferColumnType(values [][]models.JSONValue, index int) columnType {
+	kind := ""
+	nullable := false
+
+	for _, row := range values {
+		var value models.JSONValue
+		if index < len(row) {
+			value = row[index]
+		}
+
+		valueKind := ""
+		switch v := value.(type) {
+		case nil:
+			nullable = true
+			continue
+		case json.Number:
+			valueKind = "BIGINT"
+			if _, err := v.Int64(); err != nil {
+				valueKind = "DOUBLE"
+			}
+		case bool:
+			valueKind = "BOOLEAN"
+		case string:
+			valueKind = "VARCHAR"
+		default:
+			valueKind = "JSON"
+		}
+
+		switch {
+		case kind == "":
+			kind = valueKind
+		case kind == valueKind:
+		case (kind == "BIGINT" && valueKind == "DOUBLE") || (kind == "DOUBLE" && valueKind == "BIGINT"):
+			kind = "DOUBLE"
+		default:
+			kind = "ANY"
+		}
+	}
+
+	switch kind {
+	case "BIGINT":
+		return columnType{databaseType: kind, scanType: scanTypeInt64, nullable: nullable}
+	case "DOUBLE":
+		return columnType{databaseType: kind, scanType: scanTypeFloat64, nullable: nullable}
+	case "BOOLEAN":
+		return columnType{databaseType: kind, scanType: scanTypeBool, nullable: nullable}
+	case "VARCHAR", "JSON":
+		return columnType{databaseType: kind, scanType: scanTypeString, nullable: nullable}
+	default:
+		// Columns without values or with mixed values
+		return columnType{databaseType: "ANY", scanType: scanTypeAny, nullable: true}
+	}
+}
+
+// convertValue converts a JSON value into a driver value matching the column type.
+func convertValue(value models.JSONValue, typ columnType) (driver.Value, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case json.Number:
+		if typ.databaseType != "DOUBLE" {
+			if n, err := v.Int64(); err == nil {
+				return n, nil
+			}
+		}
+		return v.Float64()
+	case bool, string:
+		return v, nil
+	default:
+		var b strings.Builder
+		if err := json.NewEncoder(&b).Encode(v); err != nil {
+			return nil, err
+		}
+		return strings.TrimSuffix(b.String(), "\n"), nil
+	}
+}
@@ -1,5 +1,11 @@
 package models
 
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
 // Commit represents a repository commit.
 type Commit struct {
 	// Hash of the commit
@@ -13,3 +19,39 @@ type Commit struct {
 	// Previous commit hash, if any (optional)
 	PreviousHash *string `json:"previous_hash,omitempty"`
 }
+
+// CommitNode is a commit of a repository's commit graph with its timestamp parsed.
+type CommitNode struct {
+	Commit
+	// Time of the commit. Zero if the timestamp could not be parsed
+	Time time.Time `json:"time"`
+}
+
+// CommitFilter selects commits by author, message and time. Empty fields match every commit.
+type CommitFilter struct {
+	// Case-insensitive substring of the author
+	Author string
+	// Pattern the message must match
+	Message *regexp.Regexp
+	// Earliest time of the commits, inclusive
+	Since time.Time
+	// Latest time of the commits, exclusive
+	Until time.Time
+}
+
+// Matches reports whether a commit passes the filter. Commits without a time never match a time range.
+func (f CommitFilter) Matches(commit CommitNode) bool {
+	if f.Author != "" && !strings.Contains(strings.ToLower(commit.Author), strings.ToLower(f.Author)) {
+		return false
+	}
+	if f.Message != nil && !f.Message.MatchString(commit.Message) {
+		return false
+	}
+	if !f.Since.IsZero() && (commit.Time.IsZero() || commit.Time.Before(f.Since)) {
+		return false
+	}
+	if !f.Until.IsZero() && (commit.Time.IsZero() || !commit.Time.Before(f.Until)) {
+		return false
+	}
+	return true
+}
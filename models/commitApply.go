@@ -0,0 +1,47 @@
+package models
+
+// AppliedChangeStatus represents what happened to a change of a commit applied to a branch.
+type AppliedChangeStatus string
+
+const (
+	// AppliedChangeApplied means the change was applied as is
+	AppliedChangeApplied AppliedChangeStatus = "applied"
+	// AppliedChangeMerged means the rows of a structured object changed on the branch were merged with the change
+	AppliedChangeMerged AppliedChangeStatus = "merged"
+	// AppliedChangeSkipped means the branch already had the change
+	AppliedChangeSkipped AppliedChangeStatus = "skipped"
+	// AppliedChangeConflict means the branch changed the object differently
+	AppliedChangeConflict AppliedChangeStatus = "conflict"
+)
+
+// AppliedChange is a change of a cherry-picked or reverted commit.
+type AppliedChange struct {
+	// Path of the object after the change
+	Path string `json:"path"`
+	// Path of the object before the change, for moves
+	PreviousPath string `json:"previous_path,omitempty"`
+	// Type of the change as applied: reverting an added object removes it
+	Type ChangeType `json:"type"`
+	// What happened to the change
+	Status AppliedChangeStatus `json:"status"`
+	// Why the change conflicts
+	Reason string `json:"reason,omitempty"`
+}
+
+// CommitApplyResult is the outcome of cherry-picking or reverting a commit on a branch.
+type CommitApplyResult struct {
+	// Name of the repository
+	Repository string `json:"repository"`
+	// Branch the commit was applied to
+	Branch string `json:"branch"`
+	// Full hash of the cherry-picked or reverted commit
+	CommitHash string `json:"commit_hash"`
+	// Whether the commit was reverted rather than cherry-picked
+	Revert bool `json:"revert"`
+	// Changes of the commit, by path
+	Changes []AppliedChange `json:"changes"`
+	// Number of conflicting changes. Nothing is applied if there are any
+	Conflicts int `json:"conflicts"`
+	// Message of the new commit, if committed
+	CommitMessage string `json:"commit_message,omitempty"`
+}
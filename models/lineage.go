@@ -0,0 +1,99 @@
+package models
+
+// LineageNodeKind represents the kinds of nodes in a lineage graph.
+type LineageNodeKind string
+
+const (
+	LineageNodeConnection LineageNodeKind = "connection"
+	LineageNodeRepository LineageNodeKind = "repository"
+	LineageNodeAction     LineageNodeKind = "action"
+)
+
+// LineageNode is a location data is read from or written to, or an action that transforms it.
+type LineageNode struct {
+	// Unique ID of the node, e.g. "connection:<id>:<path>", "repository:<slug>@<branch>:<path>",
+	// "action:<workflow id>:<executable>" or "action:<workflow id>/<stage>:<executable>"
+	ID string `json:"id"`
+	// Kind of the node
+	Kind LineageNodeKind `json:"kind"`
+	// Human readable label
+	Label string `json:"label"`
+	// ID of the connection, for connection nodes
+	ConnectionID string `json:"connection_id,omitempty"`
+	// Name of the connection, for connection nodes
+	ConnectionName string `json:"connection_name,omitempty"`
+	// Repository slug, for repository nodes
+	Repository string `json:"repository,omitempty"`
+	// Branch, for repository nodes
+	Branch string `json:"branch,omitempty"`
+	// Path within the connection or repository
+	Path string `json:"path,omitempty"`
+	// Path of the executable editor file, for action nodes
+	Executable string `json:"executable,omitempty"`
+	// ID of the workflow running the action, for action nodes
+	WorkflowID string `json:"workflow_id,omitempty"`
+	// Index of the pipeline stage running the action, for action nodes of pipelines
+	Stage *int `json:"stage,omitempty"`
+}
+
+// LineageEdge is a flow of data from one node to another, caused by a workflow.
+type LineageEdge struct {
+	// ID of the node data is read from
+	From string `json:"from"`
+	// ID of the node data is written to
+	To string `json:"to"`
+	// ID of the workflow moving the data
+	WorkflowID string `json:"workflow_id"`
+	// Name of the workflow moving the data
+	WorkflowName string `json:"workflow_name"`
+	// Type of the workflow moving the data
+	WorkflowType WorkflowableType `json:"workflow_type"`
+	// Index of the pipeline stage writing the data, for pipeline workflows
+	Stage *int `json:"stage,omitempty"`
+}
+
+// LineageGraph is a directed graph of how data flows between connections, repositories and actions.
+type LineageGraph struct {
+	Nodes []LineageNode `json:"nodes"`
+	Edges []LineageEdge `json:"edges"`
+}
+
+// Node returns the node with the given ID.
+func (g *LineageGraph) Node(id string) (*LineageNode, bool) {
+	for i := range g.Nodes {
+		if g.Nodes[i].ID == id {
+			return &g.Nodes[i], true
+		}
+	}
+	return nil, false
+}
+
+// OpenLineageJobEvent is an OpenLineage job event describing the static lineage of a workflow.
+type OpenLineageJobEvent struct {
+	// Time the lineage was collected, in RFC 3339 format
+	EventTime string `json:"eventTime"`
+	// URI of the producer of the event
+	Producer string `json:"producer"`
+	// URI of the OpenLineage schema of the event
+	SchemaURL string `json:"schemaURL"`
+	// Job the event describes
+	Job OpenLineageJob `json:"job"`
+	// Datasets the job reads
+	Inputs []OpenLineageDataset `json:"inputs"`
+	// Datasets the job writes
+	Outputs []OpenLineageDataset `json:"outputs"`
+}
+
+// OpenLineageJob identifies a job, i.e. a workflow.
+type OpenLineageJob struct {
+	Namespace string                 `json:"namespace"`
+	Name      string                 `json:"name"`
+	Facets    map[string]interface{} `json:"facets,omitempty"`
+}
+
+// OpenLineageDataset identifies a dataset, i.e. a connection or repository path.
+type OpenLineageDataset struct {
+	Namespace string                 `json:"namespace"`
+	Name      string                 `json:"name"`
+	Facets    map[string]interface{} `json:"facets,omitempty"`
+}
@@ -0,0 +1,131 @@
+package models
+
+// MergeResolutionKind represents how a merge conflict is resolved.
+type MergeResolutionKind string
+
+const (
+	// MergeResolutionBase keeps the version of the base ref
+	MergeResolutionBase MergeResolutionKind = "base"
+	// MergeResolutionCompare takes the version of the compare ref
+	MergeResolutionCompare MergeResolutionKind = "compare"
+	// MergeResolutionCustom replaces the object with custom content
+	MergeResolutionCustom MergeResolutionKind = "custom"
+	// MergeResolutionDelete deletes the object
+	MergeResolutionDelete MergeResolutionKind = "delete"
+	// MergeResolutionRows merges the rows of a structured object with a three-way merge
+	MergeResolutionRows MergeResolutionKind = "rows"
+)
+
+// MergeResolution is the resolution of a single merge conflict.
+type MergeResolution struct {
+	// How the conflict is resolved
+	Kind MergeResolutionKind `json:"kind"`
+	// Content of the object, for custom resolutions
+	Content []byte `json:"-"`
+	// Key columns rows are aligned by, for row merges. Rows are aligned by position if empty
+	Key []string `json:"key,omitempty"`
+	// How values changed differently in both refs are merged, for row merges
+	Policy TableConflictPolicy `json:"policy,omitempty"`
+}
+
+// MergeConflict is an object changed in both refs of a merge, as passed to a merge resolver.
+type MergeConflict struct {
+	// Conflicting change
+	Item ChangeItem `json:"item"`
+	// Content of the object in the base ref
+	BaseContent []byte `json:"-"`
+	// Content of the object in the compare ref
+	CompareContent []byte `json:"-"`
+}
+
+// TableConflictPolicy represents how values changed differently in both versions of a structured object are merged.
+type TableConflictPolicy string
+
+const (
+	// TableConflictReport keeps the base value and leaves the conflict unresolved
+	TableConflictReport TableConflictPolicy = "report"
+	// TableConflictBase resolves conflicts with the base value
+	TableConflictBase TableConflictPolicy = "base"
+	// TableConflictCompare resolves conflicts with the compare value
+	TableConflictCompare TableConflictPolicy = "compare"
+)
+
+// TableMergeConflict is a value changed differently in both versions of a structured object.
+type TableMergeConflict struct {
+	// Key of the row, e.g. `id=2`, or its position, e.g. `#3`, when rows are aligned by position
+	Key string `json:"key"`
+	// Column with the conflicting value. Empty if one version deleted the row and the other modified it
+	Column string `json:"column,omitempty"`
+	// Value in the common ancestor
+	Ancestor JSONValue `json:"ancestor"`
+	// Value in the base ref
+	Base JSONValue `json:"base"`
+	// Value in the compare ref
+	Compare JSONValue `json:"compare"`
+}
+
+// ObjectMerge is the outcome of a three-way merge of a structured object.
+type ObjectMerge struct {
+	// Path of the object
+	Path string `json:"path"`
+	// Format the object was decoded from and encoded in
+	Format string `json:"format"`
+	// Ref of the common ancestor. Empty if the versions share no ancestor
+	AncestorRef string `json:"ancestor_ref"`
+	// Ref of the base version
+	BaseRef string `json:"base_ref"`
+	// Ref of the compare version
+	CompareRef string `json:"compare_ref"`
+	// Key columns rows are aligned by
+	KeyColumns []string `json:"key_columns"`
+	// How conflicts were merged
+	Policy TableConflictPolicy `json:"policy"`
+	// Columns of the merged object
+	Columns []string `json:"columns"`
+	// Number of rows of the merged object
+	Rows int `json:"rows"`
+	// Values changed differently in both versions
+	Conflicts []TableMergeConflict `json:"conflicts"`
+	// Values of the merged object that do not conform to its schema
+	Violations []string `json:"violations"`
+	// Branch the merged object was uploaded to, if any
+	UploadedTo string `json:"uploaded_to,omitempty"`
+}
+
+// Resolved reports whether the merge has no unresolved conflicts and conforms to the object's schema.
+func (m *ObjectMerge) Resolved() bool {
+	unresolved := len(m.Conflicts) > 0 && (m.Policy == "" || m.Policy == TableConflictReport)
+	return !unresolved && len(m.Violations) == 0
+}
+
+// MergeObjectResolution records how a conflicting object was resolved.
+type MergeObjectResolution struct {
+	// Path of the object
+	Path string `json:"path"`
+	// How the conflict was resolved
+	Kind MergeResolutionKind `json:"kind"`
+	// Values changed differently in both refs, for row merges
+	Conflicts []TableMergeConflict `json:"conflicts,omitempty"`
+	// Values of the merged object that do not conform to its schema, for row merges
+	Violations []string `json:"violations,omitempty"`
+}
+
+// MergeResult is the outcome of merging a compare ref into a base ref.
+type MergeResult struct {
+	// Name of the repository
+	Repository string `json:"repository"`
+	// Ref merged into
+	BaseRef string `json:"base_ref"`
+	// Ref merged from
+	CompareRef string `json:"compare_ref"`
+	// Whether the refs were merged
+	Merged bool `json:"merged"`
+	// Changes between the refs before the merge
+	Changes []ChangeItem `json:"changes"`
+	// Resolutions of the conflicting objects
+	Resolutions []MergeObjectResolution `json:"resolutions"`
+	// Temporary branch the resolutions were committed to, if there were conflicts
+	ResolutionBranch string `json:"resolution_branch,omitempty"`
+	// Message of the merge response
+	Message string `json:"message,omitempty"`
+}
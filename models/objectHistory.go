@@ -0,0 +1,53 @@
+package models
+
+// ObjectHistoryEntry is a commit that changed an object.
+type ObjectHistoryEntry struct {
+	// Commit that changed the object
+	Commit CommitNode `json:"commit"`
+	// Type of the change: added, changed or moved
+	Type ChangeType `json:"type"`
+	// Path of the object after the commit
+	Path string `json:"path"`
+	// Path of the object before the commit, for moves. Empty if it could not be determined
+	PreviousPath string `json:"previous_path,omitempty"`
+}
+
+// ObjectHistory is the history of an object on a branch, following moves.
+type ObjectHistory struct {
+	// Name of the repository
+	Repository string `json:"repository"`
+	// Branch the history was walked from
+	Branch string `json:"branch"`
+	// Path of the object at the head of the branch
+	Path string `json:"path"`
+	// Commits that changed the object, newest first
+	Entries []ObjectHistoryEntry `json:"entries"`
+}
+
+// RowBlame attributes a row of a structured object to the commit that last modified it.
+type RowBlame struct {
+	// Key of the row, e.g. `id=2`, or its position, e.g. `#3`, when rows are aligned by position
+	Key string `json:"key"`
+	// Index of the row in the object
+	Row int `json:"row"`
+	// Commit that last modified the row. Nil if the row predates the known history of the object
+	Commit *CommitNode `json:"commit"`
+}
+
+// ObjectBlame attributes every row of a structured object to the commit that last modified it.
+type ObjectBlame struct {
+	// Name of the repository
+	Repository string `json:"repository"`
+	// Branch the object was blamed on
+	Branch string `json:"branch"`
+	// Path of the object
+	Path string `json:"path"`
+	// Format the object was decoded from
+	Format string `json:"format"`
+	// Key columns rows are aligned by
+	KeyColumns []string `json:"key_columns"`
+	// Whether rows are aligned by position
+	Positional bool `json:"positional"`
+	// Attribution of every row, in object order
+	Rows []RowBlame `json:"rows"`
+}
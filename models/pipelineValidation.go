@@ -0,0 +1,19 @@
+package models
+
+import "fmt"
+
+// PipelineProblem describes a single problem with a pipeline definition.
+type PipelineProblem struct {
+	// Index of the stage with the problem, or -1 for problems with the pipeline as a whole
+	Stage int `json:"stage"`
+	// Explanation of the problem
+	Message string `json:"message"`
+}
+
+// String renders the problem with its stage number, counting from 1.
+func (p PipelineProblem) String() string {
+	if p.Stage < 0 {
+		return "pipeline: " + p.Message
+	}
+	return fmt.Sprintf("stage %d: %s", p.Stage+1, p.Message)
+}
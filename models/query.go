@@ -1,5 +1,11 @@
 package models
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
 // JSONValue represents a JSON-compatible value.
 type JSONValue interface{}
 
@@ -43,4 +49,176 @@ type QueryExecutionResult struct {
 	ExecutionTime int `json:"execution_time"`
 	// Logs from the query execution
 	Logs []string `json:"logs"`
+
+	// raw holds the undecoded result so it can be decoded again into typed rows
+	raw json.RawMessage
+}
+
+// QueryResultTable is a tabular view of a query result.
+type QueryResultTable struct {
+	// Column names in the order they first appear in the result
+	Columns []string `json:"columns"`
+	// Row values, aligned with Columns. Missing values are nil and numbers are json.Number
+	Rows [][]JSONValue `json:"rows"`
+}
+
+// queryExecutionResultAlias prevents recursion when unmarshalling QueryExecutionResult.
+type queryExecutionResultAlias QueryExecutionResult
+
+// UnmarshalJSON decodes the result and keeps a copy of the raw result data.
+func (r *QueryExecutionResult) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		*queryExecutionResultAlias
+		Result json.RawMessage `json:"result"`
+	}{
+		queryExecutionResultAlias: (*queryExecutionResultAlias)(r),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.raw = aux.Result
+	r.Result = nil
+	if len(aux.Result) > 0 {
+		if err := json.Unmarshal(aux.Result, &r.Result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RawResult returns the result as raw JSON.
+func (r *QueryExecutionResult) RawResult() (json.RawMessage, error) {
+	if len(r.raw) > 0 {
+		return r.raw, nil
+	}
+	return json.Marshal(r.Result)
+}
+
+// Decode decodes the result rows into out, which must be a pointer to a slice.
+// A result holding a single object or value is decoded as a single row.
+func (r *QueryExecutionResult) Decode(out interface{}) error {
+	raw, err := r.RawResult()
+	if err != nil {
+		return fmt.Errorf("failed to encode query result: %w", err)
+	}
+
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		raw = []byte("[]")
+	} else if raw[0] != '[' {
+		raw = append(append([]byte("["), raw...), ']')
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("failed to decode query result: %w", err)
+	}
+	return nil
+}
+
+// DecodeQueryResult decodes the rows of a query result into a slice of T.
+func DecodeQueryResult[T any](r *QueryExecutionResult) ([]T, error) {
+	var rows []T
+	if err := r.Decode(&rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Table returns the result as columns and rows.
+// Rows that are objects are split into columns by key, any other row is
+// placed in a single column named "value".
+func (r *QueryExecutionResult) Table() (*QueryResultTable, error) {
+	var records []json.RawMessage
+	if err := r.Decode(&records); err != nil {
+		return nil, err
+	}
+
+	table := &QueryResultTable{Columns: []string{}, Rows: make([][]JSONValue, 0, len(records))}
+	columnIndex := make(map[string]int)
+	objects := make([]map[string]JSONValue, 0, len(records))
+
+	for i, record := range records {
+		record = bytes.TrimSpace(record)
+		if len(record) == 0 || record[0] != '{' {
+			var value JSONValue
+			if err := decodeJSONNumber(record, &value); err != nil {
+				return nil, fmt.Errorf("failed to decode row %d: %w", i, err)
+			}
+			objects = append(objects, map[string]JSONValue{"value": value})
+			if _, ok := columnIndex["value"]; !ok {
+				columnIndex["value"] = len(table.Columns)
+				table.Columns = append(table.Columns, "value")
+			}
+			continue
+		}
+
+		keys, err := objectKeys(record)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read columns of row %d: %w", i, err)
+		}
+		for _, key := range keys {
+			if _, ok := columnIndex[key]; !ok {
+				columnIndex[key] = len(table.Columns)
+				table.Columns = append(table.Columns, key)
+			}
+		}
+
+		var object map[string]JSONValue
+		if err := decodeJSONNumber(record, &object); err != nil {
+			return nil, fmt.Errorf("failed to decode row %d: %w", i, err)
+		}
+		objects = append(objects, object)
+	}
+
+	for _, object := range objects {
+		row := make([]JSONValue, len(table.Columns))
+		for key, value := range object {
+			row[columnIndex[key]] = value
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table, nil
+}
+
+// TableFromJSON returns JSON rows as columns and rows, in the same way as QueryExecutionResult.Table.
+func TableFromJSON(data []byte) (*QueryResultTable, error) {
+	result := QueryExecutionResult{raw: data}
+	return result.Table()
+}
+
+// decodeJSONNumber decodes data into out, keeping numbers as json.Number.
+func decodeJSONNumber(data []byte, out interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(out)
+}
+
+// objectKeys returns the top-level keys of a JSON object in document order.
+func objectKeys(data []byte) ([]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object key %v", token)
+		}
+		keys = append(keys, key)
+
+		// Skip the value
+		var skip json.RawMessage
+		if err := decoder.Decode(&skip); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
 }
@@ -0,0 +1,20 @@
+package models
+
+// RefKind represents what a ref resolves from.
+type RefKind string
+
+const (
+	RefKindBranch RefKind = "branch"
+	RefKindTag    RefKind = "tag"
+	RefKindCommit RefKind = "commit"
+)
+
+// ResolvedRef is a ref resolved to a commit.
+type ResolvedRef struct {
+	// Whether the ref names a branch, a tag or a commit. Relative refs, e.g. `main~3`, resolve to commits
+	Kind RefKind `json:"kind"`
+	// Name of the branch or tag, or the ref as given for commits
+	Name string `json:"name"`
+	// Full hash of the commit the ref points to
+	Hash string `json:"hash"`
+}
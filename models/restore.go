@@ -0,0 +1,35 @@
+package models
+
+// RestoreChange is a change a restore makes to an object.
+type RestoreChange struct {
+	// Path of the object
+	Path string `json:"path"`
+	// Type of the change: added if the object is recreated, changed if its content is restored, removed if it is deleted
+	Type ChangeType `json:"type"`
+	// Type of the object
+	ObjectType ObjectType `json:"object_type"`
+	// Row diff from the current to the restored content, for changed structured objects if requested
+	RowDiff *ObjectRowDiff `json:"row_diff,omitempty"`
+}
+
+// RestoreResult is the outcome, or with a dry run the preview, of restoring an object or group to a previous ref.
+type RestoreResult struct {
+	// Name of the repository
+	Repository string `json:"repository"`
+	// Branch restored to
+	Branch string `json:"branch"`
+	// Path of the restored object or group
+	Path string `json:"path"`
+	// Ref restored from
+	FromRef string `json:"from_ref"`
+	// Hash of the commit restored from
+	FromHash string `json:"from_hash"`
+	// Whether the changes were only previewed
+	DryRun bool `json:"dry_run"`
+	// Changes to the objects, by path
+	Changes []RestoreChange `json:"changes"`
+	// Number of objects already at their restored content
+	Unchanged int `json:"unchanged"`
+	// Message of the restore commit, if committed
+	CommitMessage string `json:"commit_message,omitempty"`
+}
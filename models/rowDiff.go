@@ -0,0 +1,80 @@
+package models
+
+// RowChangeType represents the type of change of a row in a row diff.
+type RowChangeType string
+
+const (
+	RowChangeAdded    RowChangeType = "added"
+	RowChangeRemoved  RowChangeType = "removed"
+	RowChangeModified RowChangeType = "modified"
+)
+
+// ColumnChange is the change of a single value in a modified row.
+type ColumnChange struct {
+	// Name of the column
+	Column string `json:"column"`
+	// Value in the base ref
+	Old JSONValue `json:"old"`
+	// Value in the compare ref
+	New JSONValue `json:"new"`
+}
+
+// RowChange is an added, removed or modified row of a structured object.
+type RowChange struct {
+	// Type of the change
+	Type RowChangeType `json:"type"`
+	// Key of the row, e.g. `id=2`, or its position, e.g. `#3`, when rows are aligned by position
+	Key string `json:"key"`
+	// Index of the row in the base ref, for removed and modified rows
+	BaseRow *int `json:"base_row,omitempty"`
+	// Index of the row in the compare ref, for added and modified rows
+	CompareRow *int `json:"compare_row,omitempty"`
+	// Row in the base ref, for removed and modified rows
+	Old JSONObject `json:"old,omitempty"`
+	// Row in the compare ref, for added and modified rows
+	New JSONObject `json:"new,omitempty"`
+	// Changed values, for modified rows. Only columns present in both refs are compared
+	Columns []ColumnChange `json:"columns,omitempty"`
+}
+
+// ObjectRowDiff is the row-level diff of a single structured object.
+type ObjectRowDiff struct {
+	// Path of the object
+	Path string `json:"path"`
+	// Format the object was decoded from (json, ndjson, csv or parquet)
+	Format string `json:"format,omitempty"`
+	// Columns rows were aligned by
+	KeyColumns []string `json:"key_columns,omitempty"`
+	// Whether rows were aligned by position, because no key was given or a key column is missing
+	Positional bool `json:"positional"`
+	// Columns of the object in the compare ref, followed by the removed columns
+	Columns []string `json:"columns"`
+	// Columns only present in the compare ref
+	AddedColumns []string `json:"added_columns,omitempty"`
+	// Columns only present in the base ref
+	RemovedColumns []string `json:"removed_columns,omitempty"`
+	// Number of added rows
+	Added int `json:"added"`
+	// Number of removed rows
+	Removed int `json:"removed"`
+	// Number of modified rows
+	Modified int `json:"modified"`
+	// Number of unchanged rows
+	Unchanged int `json:"unchanged"`
+	// Changed rows
+	Changes []RowChange `json:"changes"`
+	// Reason the object could not be compared, e.g. an unsupported format
+	Error string `json:"error,omitempty"`
+}
+
+// RowDiff is the row-level diff of the changed structured objects between two refs.
+type RowDiff struct {
+	// Name of the repository
+	Repository string `json:"repository"`
+	// Base reference
+	BaseRef string `json:"base_ref"`
+	// Compare reference
+	CompareRef string `json:"compare_ref"`
+	// Diffs of the changed structured objects
+	Objects []ObjectRowDiff `json:"objects"`
+}
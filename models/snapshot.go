@@ -0,0 +1,23 @@
+package models
+
+// Snapshot describes a repository archive downloaded and extracted into a local directory.
+type Snapshot struct {
+	// Name of the repository
+	Repository string `json:"repository"`
+	// Ref the archive was created from
+	Ref string `json:"ref"`
+	// Path of the object or group archived, or empty for the whole repository
+	Path string `json:"path"`
+	// Directory the archive was extracted into
+	Dest string `json:"dest"`
+	// Format of the archive: zip, tar.gz or tar
+	Format string `json:"format"`
+	// Size of the archive in bytes
+	Size int64 `json:"size"`
+	// Hex-encoded SHA-256 checksum of the archive
+	SHA256 string `json:"sha256"`
+	// Whether the download resumed a partial download
+	Resumed bool `json:"resumed"`
+	// Slash-separated paths of the extracted files, relative to Dest
+	Files []string `json:"files"`
+}
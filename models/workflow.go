@@ -1,5 +1,10 @@
 package models
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 // WorkflowableType represents the types of workflows that can exist.
 type WorkflowableType string
 
@@ -161,3 +166,181 @@ type PipelineStageRepository struct {
 func (r *PipelineStageRepository) GetType() string {
 	return "repository"
 }
+
+// workflowAlias prevents recursion when (un)marshalling Workflow.
+type workflowAlias Workflow
+
+// UnmarshalJSON decodes the workflowable into Import, Export, Action or Pipeline based on the workflow type.
+func (w *Workflow) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		*workflowAlias
+		Workflowable json.RawMessage `json:"workflowable"`
+	}{
+		workflowAlias: (*workflowAlias)(w),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	w.Workflowable = nil
+	if len(aux.Workflowable) == 0 || string(aux.Workflowable) == "null" {
+		return nil
+	}
+
+	var workflowable interface{}
+	switch w.Type {
+	case WorkflowableTypeImport:
+		workflowable = &Import{}
+	case WorkflowableTypeExport:
+		workflowable = &Export{}
+	case WorkflowableTypeAction:
+		workflowable = &Action{}
+	case WorkflowableTypePipeline:
+		workflowable = &Pipeline{}
+	default:
+		// Keep unknown workflow types as generic JSON
+		var value JSONValue
+		if err := json.Unmarshal(aux.Workflowable, &value); err != nil {
+			return err
+		}
+		w.Workflowable = value
+		return nil
+	}
+
+	if err := json.Unmarshal(aux.Workflowable, workflowable); err != nil {
+		return fmt.Errorf("failed to decode %s workflow: %w", w.Type, err)
+	}
+	w.Workflowable = workflowable
+	return nil
+}
+
+// MarshalJSON encodes the workflow, deriving the type from the workflowable if it is not set.
+func (w Workflow) MarshalJSON() ([]byte, error) {
+	if w.Type == "" {
+		switch w.Workflowable.(type) {
+		case Import, *Import:
+			w.Type = WorkflowableTypeImport
+		case Export, *Export:
+			w.Type = WorkflowableTypeExport
+		case Action, *Action:
+			w.Type = WorkflowableTypeAction
+		case Pipeline, *Pipeline:
+			w.Type = WorkflowableTypePipeline
+		}
+	}
+	return json.Marshal(workflowAlias(w))
+}
+
+// AsImport returns the import configuration if the workflow is an import workflow.
+func (w *Workflow) AsImport() (*Import, bool) {
+	switch v := w.Workflowable.(type) {
+	case *Import:
+		return v, v != nil
+	case Import:
+		return &v, true
+	}
+	return nil, false
+}
+
+// AsExport returns the export configuration if the workflow is an export workflow.
+func (w *Workflow) AsExport() (*Export, bool) {
+	switch v := w.Workflowable.(type) {
+	case *Export:
+		return v, v != nil
+	case Export:
+		return &v, true
+	}
+	return nil, false
+}
+
+// AsAction returns the action configuration if the workflow is an action workflow.
+func (w *Workflow) AsAction() (*Action, bool) {
+	switch v := w.Workflowable.(type) {
+	case *Action:
+		return v, v != nil
+	case Action:
+		return &v, true
+	}
+	return nil, false
+}
+
+// AsPipeline returns the pipeline configuration if the workflow is a pipeline workflow.
+func (w *Workflow) AsPipeline() (*Pipeline, bool) {
+	switch v := w.Workflowable.(type) {
+	case *Pipeline:
+		return v, v != nil
+	case Pipeline:
+		return &v, true
+	}
+	return nil, false
+}
+
+// UnmarshalJSON decodes the pipeline stages into their concrete types.
+func (p *Pipeline) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Live   bool              `json:"live"`
+		Stages []json.RawMessage `json:"stages"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	p.Live = aux.Live
+	p.Stages = make([]PipelineStage, 0, len(aux.Stages))
+	for i, raw := range aux.Stages {
+		stage, err := UnmarshalPipelineStage(raw)
+		if err != nil {
+			return fmt.Errorf("failed to decode pipeline stage %d: %w", i, err)
+		}
+		p.Stages = append(p.Stages, stage)
+	}
+	return nil
+}
+
+// UnmarshalPipelineStage decodes a single pipeline stage based on its type.
+func UnmarshalPipelineStage(data []byte) (PipelineStage, error) {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, err
+	}
+
+	var stage PipelineStage
+	switch head.Type {
+	case "action":
+		stage = &PipelineStageAction{}
+	case "connection":
+		stage = &PipelineStageConnection{}
+	case "repository":
+		stage = &PipelineStageRepository{}
+	default:
+		return nil, fmt.Errorf("unknown pipeline stage type: %q", head.Type)
+	}
+
+	if err := json.Unmarshal(data, stage); err != nil {
+		return nil, err
+	}
+	return stage, nil
+}
+
+// MarshalJSON encodes the stage, always setting its type.
+func (a PipelineStageAction) MarshalJSON() ([]byte, error) {
+	type alias PipelineStageAction
+	a.Type = a.GetType()
+	return json.Marshal(alias(a))
+}
+
+// MarshalJSON encodes the stage, always setting its type.
+func (c PipelineStageConnection) MarshalJSON() ([]byte, error) {
+	type alias PipelineStageConnection
+	c.Type = c.GetType()
+	return json.Marshal(alias(c))
+}
+
+// MarshalJSON encodes the stage, always setting its type.
+func (r PipelineStageRepository) MarshalJSON() ([]byte, error) {
+	type alias PipelineStageRepository
+	r.Type = r.GetType()
+	return json.Marshal(alias(r))
+}
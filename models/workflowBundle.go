@@ -0,0 +1,80 @@
+package models
+
+// WorkflowBundleVersion is the version of the bundle format written by this SDK.
+const WorkflowBundleVersion = 1
+
+// WorkflowBundle is a portable export of workflows, used to recreate them in another workspace.
+type WorkflowBundle struct {
+	// Version of the bundle format
+	Version int `json:"version"`
+	// Slug of the workspace the workflows were exported from
+	SourceWorkspace string `json:"source_workspace,omitempty"`
+	// Export date, in RFC 3339 format
+	ExportedAt string `json:"exported_at"`
+	// Exported workflows, in creation order
+	Workflows []WorkflowBundleWorkflow `json:"workflows"`
+	// Definitions of the connections referenced by the workflows (optional)
+	Connections []WorkflowBundleConnection `json:"connections,omitempty"`
+	// Editor files the workflows execute (optional)
+	Executables []WorkflowBundleExecutable `json:"executables,omitempty"`
+}
+
+// WorkflowBundleWorkflow is a single exported workflow.
+type WorkflowBundleWorkflow struct {
+	// ID of the workflow in the source workspace, used to remap workflow run triggers
+	ID string `json:"id"`
+	// Declarative definition of the workflow
+	Spec WorkflowSpec `json:"spec"`
+}
+
+// WorkflowBundleConnection is the definition of a connection, without its secret details.
+type WorkflowBundleConnection struct {
+	// ID of the connection in the source workspace
+	ID string `json:"id"`
+	// Connection name
+	Name string `json:"name"`
+	// Connection description
+	Description string `json:"description,omitempty"`
+	// ID of the connector
+	Connector string `json:"connector"`
+	// Connection settings
+	Settings map[string]string `json:"settings,omitempty"`
+	// Names of the connection details, whose values are not exported
+	Details []string `json:"details,omitempty"`
+}
+
+// WorkflowBundleExecutable is an editor file executed by an exported workflow.
+type WorkflowBundleExecutable struct {
+	// Name of the file
+	Name string `json:"name"`
+	// Path of the file in the editor files
+	Path string `json:"path"`
+	// Type of the file (file extension)
+	Type IrminFileType `json:"type"`
+	// Content of the file
+	Contents string `json:"contents"`
+}
+
+// WorkflowBundleMapping maps references in a bundle to objects in the target workspace.
+// References without a mapping are kept as they are.
+type WorkflowBundleMapping struct {
+	// Connection IDs in the source workspace to connection IDs in the target workspace
+	Connections map[string]string `json:"connections,omitempty"`
+	// Repository slugs in the source workspace to repository slugs in the target workspace
+	Repositories map[string]string `json:"repositories,omitempty"`
+	// Workflow IDs in the source workspace to workflow IDs in the target workspace, for workflow
+	// run triggers on workflows outside the bundle. Workflows in the bundle are mapped automatically
+	Workflows map[string]string `json:"workflows,omitempty"`
+}
+
+// WorkflowBundleImport is the result of importing a workflow bundle.
+type WorkflowBundleImport struct {
+	// Created workflows, in bundle order
+	Workflows []Workflow `json:"workflows"`
+	// Mapping of bundled workflow IDs to the IDs of the created workflows
+	WorkflowIDs map[string]string `json:"workflow_ids"`
+	// Mapping of bundled connection IDs to the IDs of the connections created from their definitions
+	Connections map[string]string `json:"connections"`
+	// Paths of the editor files created
+	Executables []string `json:"executables"`
+}
@@ -0,0 +1,83 @@
+package models
+
+// WorkflowGraphNodeKind represents the kinds of nodes in a workflow graph.
+type WorkflowGraphNodeKind string
+
+const (
+	WorkflowGraphNodeWorkflow   WorkflowGraphNodeKind = "workflow"
+	WorkflowGraphNodeRepository WorkflowGraphNodeKind = "repository"
+)
+
+// WorkflowGraphEdgeKind represents the kinds of dependencies in a workflow graph.
+type WorkflowGraphEdgeKind string
+
+const (
+	// A workflow run triggers another workflow through a workflow run trigger
+	WorkflowGraphEdgeRunEvent WorkflowGraphEdgeKind = "run-event"
+	// A workflow writes to a repository branch through an import or a pipeline stage
+	WorkflowGraphEdgeWrites WorkflowGraphEdgeKind = "writes"
+	// A repository event triggers a workflow through a repository trigger
+	WorkflowGraphEdgeRepositoryEvent WorkflowGraphEdgeKind = "repository-event"
+)
+
+// WorkflowGraphNode is a workflow or a repository branch in a workflow graph.
+type WorkflowGraphNode struct {
+	// Unique ID of the node, e.g. "workflow:<id>" or "repository:<slug>@<branch>"
+	ID string `json:"id"`
+	// Kind of the node
+	Kind WorkflowGraphNodeKind `json:"kind"`
+	// Human readable label
+	Label string `json:"label"`
+	// ID of the workflow, for workflow nodes
+	WorkflowID string `json:"workflow_id,omitempty"`
+	// Type of the workflow, for workflow nodes
+	WorkflowType WorkflowableType `json:"workflow_type,omitempty"`
+	// Repository slug, for repository nodes
+	Repository string `json:"repository,omitempty"`
+	// Branch, for repository nodes. Empty if the branch is not known
+	Branch string `json:"branch,omitempty"`
+}
+
+// WorkflowGraphEdge is a dependency between two nodes of a workflow graph.
+type WorkflowGraphEdge struct {
+	// ID of the source node
+	From string `json:"from"`
+	// ID of the target node
+	To string `json:"to"`
+	// Kind of the dependency
+	Kind WorkflowGraphEdgeKind `json:"kind"`
+	// Repository or workflow run event, for trigger edges
+	Event string `json:"event,omitempty"`
+}
+
+// Propagates reports whether activity of the source can fire the target. Workflows only
+// create commits, so repository triggers on other events cannot be fired by workflows.
+func (e WorkflowGraphEdge) Propagates() bool {
+	if e.Kind != WorkflowGraphEdgeRepositoryEvent {
+		return true
+	}
+	return e.Event == string(PreCommit) || e.Event == string(PostCommit)
+}
+
+// WorkflowGraphCycle is a loop of nodes that would trigger each other indefinitely.
+type WorkflowGraphCycle struct {
+	// IDs of the nodes in the loop, in order. The last node leads back to the first
+	Nodes []string `json:"nodes"`
+}
+
+// WorkflowGraph is a directed graph of the dependencies between workflows and repositories.
+type WorkflowGraph struct {
+	Nodes  []WorkflowGraphNode  `json:"nodes"`
+	Edges  []WorkflowGraphEdge  `json:"edges"`
+	Cycles []WorkflowGraphCycle `json:"cycles"`
+}
+
+// Node returns the node with the given ID.
+func (g *WorkflowGraph) Node(id string) (*WorkflowGraphNode, bool) {
+	for i := range g.Nodes {
+		if g.Nodes[i].ID == id {
+			return &g.Nodes[i], true
+		}
+	}
+	return nil, false
+}
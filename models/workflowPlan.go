@@ -0,0 +1,123 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WorkflowPlanAction represents what a plan does with a single workflow.
+type WorkflowPlanAction string
+
+const (
+	WorkflowPlanActionNoop    WorkflowPlanAction = "no-op"
+	WorkflowPlanActionCreate  WorkflowPlanAction = "create"
+	WorkflowPlanActionUpdate  WorkflowPlanAction = "update"
+	WorkflowPlanActionReplace WorkflowPlanAction = "replace"
+	WorkflowPlanActionDelete  WorkflowPlanAction = "delete"
+)
+
+// WorkflowFieldDiff describes a single changed field of a workflow.
+type WorkflowFieldDiff struct {
+	// Dotted path of the field, e.g. "import.branch"
+	Field string `json:"field"`
+	// Current value, empty if the field is added
+	Old string `json:"old"`
+	// Declared value, empty if the field is removed
+	New string `json:"new"`
+	// Whether the change cannot be applied in place and forces the workflow to be recreated
+	ForcesReplacement bool `json:"forces_replacement"`
+}
+
+// WorkflowPlanChange is a single planned change.
+type WorkflowPlanChange struct {
+	// What the plan does with the workflow
+	Action WorkflowPlanAction `json:"action"`
+	// Spec key of the workflow
+	Key string `json:"key"`
+	// ID of the existing workflow, empty when creating
+	WorkflowID string `json:"workflow_id,omitempty"`
+	// Declared spec, nil when deleting
+	Spec *WorkflowSpec `json:"spec,omitempty"`
+	// Existing workflow, nil when creating
+	Current *Workflow `json:"current,omitempty"`
+	// Changed fields, for updates and replacements
+	Diffs []WorkflowFieldDiff `json:"diffs,omitempty"`
+}
+
+// WorkflowPlan is the set of changes needed to reconcile workflows with a spec file.
+type WorkflowPlan struct {
+	Changes []WorkflowPlanChange `json:"changes"`
+}
+
+// HasChanges reports whether applying the plan would change anything.
+func (p *WorkflowPlan) HasChanges() bool {
+	for _, change := range p.Changes {
+		if change.Action != WorkflowPlanActionNoop {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of changes with the given action.
+func (p *WorkflowPlan) Count(action WorkflowPlanAction) int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// String renders the plan in a Terraform-like format.
+func (p *WorkflowPlan) String() string {
+	if !p.HasChanges() {
+		return "No changes. Workflows are up to date.\n"
+	}
+
+	var b strings.Builder
+	for _, change := range p.Changes {
+		var symbol string
+		switch change.Action {
+		case WorkflowPlanActionCreate:
+			symbol = "  +"
+		case WorkflowPlanActionUpdate:
+			symbol = "  ~"
+		case WorkflowPlanActionReplace:
+			symbol = "-/+"
+		case WorkflowPlanActionDelete:
+			symbol = "  -"
+		default:
+			continue
+		}
+
+		workflowType := ""
+		if change.Spec != nil {
+			workflowType = string(change.Spec.Type)
+		} else if change.Current != nil {
+			workflowType = string(change.Current.Type)
+		}
+		fmt.Fprintf(&b, "%s %s %s workflow %q", symbol, change.Action, workflowType, change.Key)
+		if change.WorkflowID != "" {
+			fmt.Fprintf(&b, " (id: %s)", change.WorkflowID)
+		}
+		b.WriteString("\n")
+
+		for _, diff := range change.Diffs {
+			fmt.Fprintf(&b, "      ~ %s: %q -> %q", diff.Field, diff.Old, diff.New)
+			if diff.ForcesReplacement {
+				b.WriteString(" # forces replacement")
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	fmt.Fprintf(&b, "\nPlan: %d to create, %d to update, %d to replace, %d to delete.\n",
+		p.Count(WorkflowPlanActionCreate),
+		p.Count(WorkflowPlanActionUpdate),
+		p.Count(WorkflowPlanActionReplace),
+		p.Count(WorkflowPlanActionDelete),
+	)
+	return b.String()
+}
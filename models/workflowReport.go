@@ -0,0 +1,53 @@
+package models
+
+// WorkflowRunReport summarises the run history of workflows over a time window.
+type WorkflowRunReport struct {
+	// Report generation date, in RFC 3339 format
+	GeneratedAt string `json:"generated_at"`
+	// Start of the window, in RFC 3339 format
+	WindowStart string `json:"window_start"`
+	// End of the window (exclusive), in RFC 3339 format
+	WindowEnd string `json:"window_end"`
+	// Statistics per workflow
+	Workflows []WorkflowRunStats `json:"workflows"`
+}
+
+// WorkflowRunStats are the run statistics of a single workflow within a report window.
+type WorkflowRunStats struct {
+	// ID of the workflow
+	WorkflowID string `json:"workflow_id"`
+	// Name of the workflow
+	WorkflowName string `json:"workflow_name"`
+	// Number of runs started in the window
+	Runs int `json:"runs"`
+	// Number of runs that completed
+	Succeeded int `json:"succeeded"`
+	// Number of runs that ended with an error
+	Failed int `json:"failed"`
+	// Number of runs that have not finished yet
+	Unfinished int `json:"unfinished"`
+	// Share of finished runs that completed, between 0 and 1
+	SuccessRate float64 `json:"success_rate"`
+	// Median duration of the finished runs, in seconds
+	DurationP50 float64 `json:"duration_p50_seconds"`
+	// 95th percentile duration of the finished runs, in seconds
+	DurationP95 float64 `json:"duration_p95_seconds"`
+	// Maximum number of retries of a failed run, from the workflow schedule
+	MaxRetries *int `json:"max_retries,omitempty"`
+	// Number of runs that retried a failed run
+	RetriesConsumed int `json:"retries_consumed"`
+	// Number of failures whose retries were all used without success
+	RetriesExhausted int `json:"retries_exhausted"`
+	// Maximum runtime of a run in seconds, from the workflow schedule
+	MaxRuntime *int `json:"max_runtime,omitempty"`
+	// Number of runs that ran longer than the maximum runtime
+	RuntimeExceeded int `json:"runtime_exceeded"`
+	// Number of windows the time triggers scheduled a run for
+	ScheduledWindows int `json:"scheduled_windows"`
+	// Start times of the scheduled windows in which no run started, in RFC 3339 format
+	MissedWindows []string `json:"missed_windows"`
+	// Number of error log events of the workflow in the window
+	ErrorEvents int `json:"error_events"`
+	// Number of warning log events of the workflow in the window
+	WarningEvents int `json:"warning_events"`
+}
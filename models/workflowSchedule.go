@@ -1,5 +1,10 @@
 package models
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 // RepositoryEvent represents repository-related events that can trigger a workflow.
 type RepositoryEvent string
 
@@ -38,7 +43,7 @@ type TimeTrigger struct {
 }
 
 func (t TimeTrigger) GetType() string {
-	return t.Type
+	return "time"
 }
 
 // RepositoryTrigger represents a trigger for repository-related events.
@@ -50,7 +55,7 @@ type RepositoryTrigger struct {
 }
 
 func (t RepositoryTrigger) GetType() string {
-	return t.Type
+	return "repository-event"
 }
 
 // WorkflowRunTrigger represents a trigger for workflow run-related events.
@@ -61,7 +66,7 @@ type WorkflowRunTrigger struct {
 }
 
 func (t WorkflowRunTrigger) GetType() string {
-	return t.Type
+	return "workflow-run-event"
 }
 
 // WorkflowSchedule represents the schedule configuration for a workflow.
@@ -71,3 +76,76 @@ type WorkflowSchedule struct {
 	MaxRuntime  *int              `json:"max_runtime,omitempty"`
 	MinInterval *int              `json:"min_interval,omitempty"`
 }
+
+// UnmarshalJSON decodes the schedule triggers into their concrete types.
+func (s *WorkflowSchedule) UnmarshalJSON(data []byte) error {
+	type alias WorkflowSchedule
+	aux := struct {
+		*alias
+		Triggers []json.RawMessage `json:"triggers"`
+	}{
+		alias: (*alias)(s),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	s.Triggers = make([]WorkflowTrigger, 0, len(aux.Triggers))
+	for i, raw := range aux.Triggers {
+		trigger, err := UnmarshalWorkflowTrigger(raw)
+		if err != nil {
+			return fmt.Errorf("failed to decode trigger %d: %w", i, err)
+		}
+		s.Triggers = append(s.Triggers, trigger)
+	}
+	return nil
+}
+
+// UnmarshalWorkflowTrigger decodes a single workflow trigger based on its type.
+// Triggers are returned as pointers, as expected by PrepareWorkflowScheduleData.
+func UnmarshalWorkflowTrigger(data []byte) (WorkflowTrigger, error) {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, err
+	}
+
+	var trigger WorkflowTrigger
+	switch head.Type {
+	case "time":
+		trigger = &TimeTrigger{}
+	case "repository-event":
+		trigger = &RepositoryTrigger{}
+	case "workflow-run-event":
+		trigger = &WorkflowRunTrigger{}
+	default:
+		return nil, fmt.Errorf("unknown trigger type: %q", head.Type)
+	}
+
+	if err := json.Unmarshal(data, trigger); err != nil {
+		return nil, err
+	}
+	return trigger, nil
+}
+
+// MarshalJSON encodes the trigger, always setting its type.
+func (t TimeTrigger) MarshalJSON() ([]byte, error) {
+	type alias TimeTrigger
+	t.Type = t.GetType()
+	return json.Marshal(alias(t))
+}
+
+// MarshalJSON encodes the trigger, always setting its type.
+func (t RepositoryTrigger) MarshalJSON() ([]byte, error) {
+	type alias RepositoryTrigger
+	t.Type = t.GetType()
+	return json.Marshal(alias(t))
+}
+
+// MarshalJSON encodes the trigger, always setting its type.
+func (t WorkflowRunTrigger) MarshalJSON() ([]byte, error) {
+	type alias WorkflowRunTrigger
+	t.Type = t.GetType()
+	return json.Marshal(alias(t))
+}
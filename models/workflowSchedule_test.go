@@ -0,0 +1,74 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestWorkflowTriggerJSONRoundTrip(t *testing.T) {
+	repository, ref, workflow := "lake", "main", "w1"
+	tests := []struct {
+		name     string
+		data     string
+		expected WorkflowTrigger
+	}{
+		{
+			name:     "time",
+			data:     `{"type":"time","rrule":"FREQ=DAILY"}`,
+			expected: &TimeTrigger{Type: "time", RRule: "FREQ=DAILY"},
+		},
+		{
+			name:     "repository-event",
+			data:     `{"type":"repository-event","event":"post-commit","repository":"lake","ref":"main"}`,
+			expected: &RepositoryTrigger{Type: "repository-event", Event: PostCommit, Repository: &repository, Ref: &ref},
+		},
+		{
+			name:     "workflow-run-event",
+			data:     `{"type":"workflow-run-event","event":"post-workflow-run","workflow":"w1"}`,
+			expected: &WorkflowRunTrigger{Type: "workflow-run-event", Event: PostWorkflowRun, Workflow: &workflow},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger, err := UnmarshalWorkflowTrigger([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("decoding trigger: %v", err)
+			}
+			if !reflect.DeepEqual(trigger, tt.expected) {
+				t.Errorf("got %#v, expected %#v", trigger, tt.expected)
+			}
+			if trigger.GetType() != tt.name {
+				t.Errorf("got type %q, expected %q", trigger.GetType(), tt.name)
+			}
+
+			// The type is set when encoding even if the field is empty
+			reflect.ValueOf(trigger).Elem().FieldByName("Type").SetString("")
+			data, err := json.Marshal(WorkflowSchedule{Triggers: []WorkflowTrigger{trigger}})
+			if err != nil {
+				t.Fatalf("encoding schedule: %v", err)
+			}
+			var schedule WorkflowSchedule
+			if err := json.Unmarshal(data, &schedule); err != nil {
+				t.Fatalf("decoding encoded schedule: %v", err)
+			}
+			if len(schedule.Triggers) != 1 || !reflect.DeepEqual(schedule.Triggers[0], tt.expected) {
+				t.Errorf("got %#v after round trip, expected [%#v]", schedule.Triggers, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnmarshalWorkflowTriggerUnknownType(t *testing.T) {
+	for _, data := range []string{`{"type":"webhook"}`, `{}`} {
+		if trigger, err := UnmarshalWorkflowTrigger([]byte(data)); err == nil {
+			t.Errorf("decoding %s: got %#v, expected an error", data, trigger)
+		}
+	}
+
+	var schedule WorkflowSchedule
+	if err := json.Unmarshal([]byte(`{"triggers":[{"type":"time","rrule":"FREQ=DAILY"},{"type":"webhook"}]}`), &schedule); err == nil {
+		t.Errorf("got %#v, expected an error for the unknown trigger", schedule)
+	}
+}
@@ -0,0 +1,140 @@
+package models
+
+// WorkflowSpecFile is a declarative definition of a set of workflows, usually stored as YAML or JSON.
+type WorkflowSpecFile struct {
+	// Workflows defined in the file
+	Workflows []WorkflowSpec `json:"workflows" yaml:"workflows"`
+}
+
+// WorkflowSpec declares a single workflow. Exactly one of Import, Export, Action
+// or Pipeline must be set, matching Type.
+type WorkflowSpec struct {
+	// Stable key used to match the spec with an existing workflow. Defaults to Name
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+	// Name of the workflow
+	Name string `json:"name" yaml:"name"`
+	// Short description of the workflow
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Markdown documentation of the workflow
+	Documentation string `json:"documentation,omitempty" yaml:"documentation,omitempty"`
+	// Type of the workflow
+	Type WorkflowableType `json:"type" yaml:"type"`
+	// Import configuration, for import workflows
+	Import *ImportSpec `json:"import,omitempty" yaml:"import,omitempty"`
+	// Export configuration, for export workflows
+	Export *ExportSpec `json:"export,omitempty" yaml:"export,omitempty"`
+	// Action configuration, for action workflows
+	Action *ActionSpec `json:"action,omitempty" yaml:"action,omitempty"`
+	// Pipeline configuration, for pipeline workflows
+	Pipeline *PipelineSpec `json:"pipeline,omitempty" yaml:"pipeline,omitempty"`
+	// Schedule of the workflow (optional)
+	Schedule *WorkflowScheduleSpec `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+}
+
+// SpecKey returns the key used to match the spec with an existing workflow.
+func (s WorkflowSpec) SpecKey() string {
+	if s.Key != "" {
+		return s.Key
+	}
+	return s.Name
+}
+
+// ImportSpec declares the configuration of an import workflow.
+type ImportSpec struct {
+	// ID of the connection to import from
+	Connection string `json:"connection" yaml:"connection"`
+	// Slug of the repository to import into
+	Repository string `json:"repository" yaml:"repository"`
+	// Branch to import into
+	Branch string `json:"branch" yaml:"branch"`
+	// Path in the repository to import into
+	Path string `json:"path" yaml:"path"`
+}
+
+// ExportSpec declares the configuration of an export workflow.
+type ExportSpec struct {
+	// ID of the connection to export to
+	Connection string `json:"connection" yaml:"connection"`
+	// Slug of the repository to export from
+	Repository string `json:"repository" yaml:"repository"`
+	// Branch to export from
+	Branch string `json:"branch" yaml:"branch"`
+	// Path in the repository to export from
+	Path string `json:"path" yaml:"path"`
+	// Whether nested objects are exported as well
+	Recursive bool `json:"recursive" yaml:"recursive"`
+}
+
+// ActionSpec declares the configuration of an action workflow.
+type ActionSpec struct {
+	// Path of the executable editor file
+	Executable string `json:"executable" yaml:"executable"`
+	// Slug of the repository the action works on (optional)
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty"`
+	// Branch the action works on (optional)
+	Branch string `json:"branch,omitempty" yaml:"branch,omitempty"`
+	// Path the action works on (optional)
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+// PipelineSpec declares the configuration of a pipeline workflow.
+type PipelineSpec struct {
+	// Whether the pipeline runs continuously
+	Live bool `json:"live" yaml:"live"`
+	// Chain of stages in the pipeline
+	Stages []PipelineStageSpec `json:"stages" yaml:"stages"`
+}
+
+// PipelineStageSpec declares a single pipeline stage. The fields used depend on Type.
+type PipelineStageSpec struct {
+	// Type of the stage ("action", "connection" or "repository")
+	Type string `json:"type" yaml:"type"`
+	// Explanation of the stage's responsibility
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Whether the input of the stage should be used
+	Write bool `json:"write" yaml:"write"`
+	// Whether the result should be passed to the next stage
+	Read bool `json:"read" yaml:"read"`
+	// Path of the executable editor file, for action stages
+	Executable string `json:"executable,omitempty" yaml:"executable,omitempty"`
+	// ID of the connection, for connection stages
+	Connection string `json:"connection,omitempty" yaml:"connection,omitempty"`
+	// Path to write within the connection, for connection stages
+	ConnectionWritePath string `json:"connection_write_path,omitempty" yaml:"connection_write_path,omitempty"`
+	// Path to read within the connection, for connection stages
+	ConnectionReadPath string `json:"connection_read_path,omitempty" yaml:"connection_read_path,omitempty"`
+	// Slug of the repository, for repository stages
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty"`
+	// Branch in the repository, for repository stages
+	Branch string `json:"branch,omitempty" yaml:"branch,omitempty"`
+	// Path within the repository, for repository stages
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+// WorkflowScheduleSpec declares the schedule of a workflow.
+type WorkflowScheduleSpec struct {
+	// Triggers that start the workflow
+	Triggers []WorkflowTriggerSpec `json:"triggers,omitempty" yaml:"triggers,omitempty"`
+	// Maximum number of retries of a failed run
+	MaxRetries *int `json:"max_retries,omitempty" yaml:"max_retries,omitempty"`
+	// Maximum runtime of a run
+	MaxRuntime *int `json:"max_runtime,omitempty" yaml:"max_runtime,omitempty"`
+	// Minimum interval between runs
+	MinInterval *int `json:"min_interval,omitempty" yaml:"min_interval,omitempty"`
+}
+
+// WorkflowTriggerSpec declares a single trigger. The fields used depend on Type.
+type WorkflowTriggerSpec struct {
+	// Type of the trigger ("time", "repository-event" or "workflow-run-event")
+	Type string `json:"type" yaml:"type"`
+	// Recurrence rule, for time triggers
+	RRule string `json:"rrule,omitempty" yaml:"rrule,omitempty"`
+	// Repository or workflow run event, for event triggers
+	Event string `json:"event,omitempty" yaml:"event,omitempty"`
+	// Repository slug, for repository event triggers (optional)
+	Repository *string `json:"repository,omitempty" yaml:"repository,omitempty"`
+	// Ref, for repository event triggers (optional)
+	Ref *string `json:"ref,omitempty" yaml:"ref,omitempty"`
+	// Workflow ID, for workflow run event triggers (optional)
+	Workflow *string `json:"workflow,omitempty" yaml:"workflow,omitempty"`
+}
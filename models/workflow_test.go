@@ -0,0 +1,173 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestWorkflowJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		workflowable interface{}
+	}{
+		{
+			name:         "import",
+			data:         `{"id":"w1","type":"import","workflowable":{"connection":{"id":"c1"},"connection_path":"/in","repository":{"slug":"lake"},"branch":"main","path":"/raw"}}`,
+			workflowable: &Import{Connection: Connection{ID: "c1"}, ConnectionPath: "/in", Repository: Repository{Slug: "lake"}, Branch: "main", Path: "/raw"},
+		},
+		{
+			name:         "export",
+			data:         `{"id":"w1","type":"export","workflowable":{"connection":{"id":"c1"},"repository":{"slug":"lake"},"branch":"main","path":"/out","recursive":true}}`,
+			workflowable: &Export{Connection: Connection{ID: "c1"}, Repository: Repository{Slug: "lake"}, Branch: "main", Path: "/out", Recursive: true},
+		},
+		{
+			name:         "action",
+			data:         `{"id":"w1","type":"action","workflowable":{"executable":"clean.py"}}`,
+			workflowable: &Action{Executable: "clean.py"},
+		},
+		{
+			name: "pipeline",
+			data: `{"id":"w1","type":"pipeline","workflowable":{"live":true,"stages":[{"type":"action","executable":"clean.py","read":true},{"type":"repository","repository":{"slug":"lake"},"branch":"main","path":"/clean","write":true}]}}`,
+			workflowable: &Pipeline{Live: true, Stages: []PipelineStage{
+				&PipelineStageAction{CommonProperties: CommonProperties{Read: true}, Type: "action", Executable: "clean.py"},
+				&PipelineStageRepository{CommonProperties: CommonProperties{Write: true}, Type: "repository", Repository: Repository{Slug: "lake"}, Branch: "main", Path: "/clean"},
+			}},
+		},
+		{
+			name:         "unknown type",
+			data:         `{"id":"w1","type":"transform","workflowable":{"steps":["a"]}}`,
+			workflowable: map[string]interface{}{"steps": []interface{}{"a"}},
+		},
+		{
+			name:         "no workflowable",
+			data:         `{"id":"w1","type":"action","workflowable":null}`,
+			workflowable: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var workflow Workflow
+			if err := json.Unmarshal([]byte(tt.data), &workflow); err != nil {
+				t.Fatalf("decoding workflow: %v", err)
+			}
+			if !reflect.DeepEqual(workflow.Workflowable, tt.workflowable) {
+				t.Errorf("got workflowable %#v, expected %#v", workflow.Workflowable, tt.workflowable)
+			}
+
+			data, err := json.Marshal(workflow)
+			if err != nil {
+				t.Fatalf("encoding workflow: %v", err)
+			}
+			var decoded Workflow
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("decoding encoded workflow: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, workflow) {
+				t.Errorf("got %#v after round trip, expected %#v", decoded, workflow)
+			}
+		})
+	}
+}
+
+func TestWorkflowMarshalDerivesType(t *testing.T) {
+	tests := []struct {
+		name         string
+		workflowable interface{}
+		expected     WorkflowableType
+	}{
+		{name: "import", workflowable: Import{}, expected: WorkflowableTypeImport},
+		{name: "export", workflowable: &Export{}, expected: WorkflowableTypeExport},
+		{name: "action", workflowable: Action{}, expected: WorkflowableTypeAction},
+		{name: "pipeline", workflowable: &Pipeline{}, expected: WorkflowableTypePipeline},
+		{name: "unknown", workflowable: map[string]interface{}{}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workflow := Workflow{}
+			workflow.Workflowable = tt.workflowable
+			data, err := json.Marshal(workflow)
+			if err != nil {
+				t.Fatalf("encoding workflow: %v", err)
+			}
+			var head struct {
+				Type WorkflowableType `json:"type"`
+			}
+			if err := json.Unmarshal(data, &head); err != nil {
+				t.Fatalf("decoding workflow type: %v", err)
+			}
+			if head.Type != tt.expected {
+				t.Errorf("got type %q, expected %q", head.Type, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPipelineStageJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected PipelineStage
+	}{
+		{
+			name:     "action",
+			data:     `{"type":"action","description":"Clean","executable":"clean.py","read":true}`,
+			expected: &PipelineStageAction{CommonProperties: CommonProperties{Description: "Clean", Read: true}, Type: "action", Executable: "clean.py"},
+		},
+		{
+			name:     "connection",
+			data:     `{"type":"connection","connection":{"id":"c1"},"connection_write_path":"/out","connection_read_path":"/in","write":true}`,
+			expected: &PipelineStageConnection{CommonProperties: CommonProperties{Write: true}, Type: "connection", Connection: Connection{ID: "c1"}, ConnectionWritePath: "/out", ConnectionReadPath: "/in"},
+		},
+		{
+			name:     "repository",
+			data:     `{"type":"repository","repository":{"slug":"lake"},"branch":"main","path":"/clean"}`,
+			expected: &PipelineStageRepository{Type: "repository", Repository: Repository{Slug: "lake"}, Branch: "main", Path: "/clean"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stage, err := UnmarshalPipelineStage([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("decoding stage: %v", err)
+			}
+			if !reflect.DeepEqual(stage, tt.expected) {
+				t.Errorf("got %#v, expected %#v", stage, tt.expected)
+			}
+			if stage.GetType() != tt.name {
+				t.Errorf("got type %q, expected %q", stage.GetType(), tt.name)
+			}
+
+			// The type is set when encoding even if the field is empty
+			reflect.ValueOf(stage).Elem().FieldByName("Type").SetString("")
+			data, err := json.Marshal(stage)
+			if err != nil {
+				t.Fatalf("encoding stage: %v", err)
+			}
+			decoded, err := UnmarshalPipelineStage(data)
+			if err != nil {
+				t.Fatalf("decoding encoded stage: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, tt.expected) {
+				t.Errorf("got %#v after round trip, expected %#v", decoded, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnmarshalPipelineStageUnknownType(t *testing.T) {
+	for _, data := range []string{`{"type":"transform"}`, `{}`} {
+		if stage, err := UnmarshalPipelineStage([]byte(data)); err == nil {
+			t.Errorf("decoding %s: got %#v, expected an error", data, stage)
+		}
+	}
+
+	var pipeline Pipeline
+	if err := json.Unmarshal([]byte(`{"stages":[{"type":"action"},{"type":"transform"}]}`), &pipeline); err == nil {
+		t.Errorf("got %#v, expected an error for the unknown stage", pipeline)
+	}
+}
@@ -0,0 +1,150 @@
+package rrule
+
+import (
+	"time"
+)
+
+// Builder builds a recurrence rule fluently, e.g. Daily().At(2, 0).Weekdays().
+type Builder struct {
+	rule RRule
+}
+
+// New starts a rule with the given frequency.
+func New(freq Frequency) *Builder {
+	return &Builder{rule: RRule{Freq: freq}}
+}
+
+// Yearly starts a rule that repeats every year.
+func Yearly() *Builder { return New(FreqYearly) }
+
+// Monthly starts a rule that repeats every month.
+func Monthly() *Builder { return New(FreqMonthly) }
+
+// Weekly starts a rule that repeats every week.
+func Weekly() *Builder { return New(FreqWeekly) }
+
+// Daily starts a rule that repeats every day.
+func Daily() *Builder { return New(FreqDaily) }
+
+// Hourly starts a rule that repeats every hour.
+func Hourly() *Builder { return New(FreqHourly) }
+
+// Minutely starts a rule that repeats every minute.
+func Minutely() *Builder { return New(FreqMinutely) }
+
+// Every sets the interval, e.g. Hourly().Every(6) repeats every six hours.
+func (b *Builder) Every(n int) *Builder {
+	b.rule.Interval = n
+	return b
+}
+
+// At adds a time of day. Calling it more than once combines every hour with every minute.
+func (b *Builder) At(hour, minute int) *Builder {
+	b.rule.ByHour = appendUnique(b.rule.ByHour, hour)
+	b.rule.ByMinute = appendUnique(b.rule.ByMinute, minute)
+	b.rule.BySecond = []int{0}
+	return b
+}
+
+// AtMinutes adds minutes of the hour, e.g. Hourly().AtMinutes(0, 30).
+func (b *Builder) AtMinutes(minutes ...int) *Builder {
+	for _, minute := range minutes {
+		b.rule.ByMinute = appendUnique(b.rule.ByMinute, minute)
+	}
+	b.rule.BySecond = []int{0}
+	return b
+}
+
+// On adds days of the week.
+func (b *Builder) On(days ...Weekday) *Builder {
+	for _, day := range days {
+		b.rule.ByDay = append(b.rule.ByDay, WeekdayNum{Weekday: day})
+	}
+	return b
+}
+
+// OnNth adds the nth weekday of the month or year, e.g. Monthly().OnNth(-1, FR) for the last Friday.
+func (b *Builder) OnNth(n int, day Weekday) *Builder {
+	b.rule.ByDay = append(b.rule.ByDay, WeekdayNum{Weekday: day, N: n})
+	return b
+}
+
+// Weekdays restricts the rule to Monday to Friday.
+func (b *Builder) Weekdays() *Builder {
+	return b.On(MO, TU, WE, TH, FR)
+}
+
+// Weekends restricts the rule to Saturday and Sunday.
+func (b *Builder) Weekends() *Builder {
+	return b.On(SA, SU)
+}
+
+// OnMonthDays adds days of the month. Negative days count from the end of the month.
+func (b *Builder) OnMonthDays(days ...int) *Builder {
+	for _, day := range days {
+		b.rule.ByMonthDay = appendUnique(b.rule.ByMonthDay, day)
+	}
+	return b
+}
+
+// InMonths restricts the rule to the given months.
+func (b *Builder) InMonths(months ...time.Month) *Builder {
+	for _, month := range months {
+		b.rule.ByMonth = appendUnique(b.rule.ByMonth, int(month))
+	}
+	return b
+}
+
+// SetPos selects positions within the occurrences of each period, e.g.
+// Monthly().Weekdays().SetPos(-1) for the last weekday of the month.
+func (b *Builder) SetPos(positions ...int) *Builder {
+	b.rule.BySetPos = append(b.rule.BySetPos, positions...)
+	return b
+}
+
+// Count limits the rule to n occurrences.
+func (b *Builder) Count(n int) *Builder {
+	b.rule.Count = n
+	return b
+}
+
+// Until sets the last possible occurrence.
+func (b *Builder) Until(t time.Time) *Builder {
+	b.rule.Until = t
+	return b
+}
+
+// Starting anchors the rule at a start time.
+func (b *Builder) Starting(t time.Time) *Builder {
+	b.rule.DTStart = t
+	return b
+}
+
+// WeekStart sets the first day of the week.
+func (b *Builder) WeekStart(day Weekday) *Builder {
+	b.rule.WeekStart = day
+	return b
+}
+
+// Build validates the rule and returns it.
+func (b *Builder) Build() (*RRule, error) {
+	rule := b.rule
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// String renders the rule without validating it.
+func (b *Builder) String() string {
+	return b.rule.String()
+}
+
+func appendUnique(values []int, value int) []int {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
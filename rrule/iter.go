@@ -0,0 +1,397 @@
+package rrule
+
+import (
+	"iter"
+	"slices"
+	"time"
+)
+
+const (
+	// maxYear is the last year occurrences are calculated for
+	maxYear = 9999
+	// maxEmptyYears stops the iteration of rules that produce no occurrence for this long.
+	// The Gregorian calendar repeats every 400 years, so such rules never produce one again.
+	maxEmptyYears = 400
+	// maxEmptyPeriods stops the iteration of sub-daily rules whose filters rarely match
+	maxEmptyPeriods = 1 << 22
+)
+
+// NextOccurrences parses a rule and returns its next n occurrences at or after from.
+// If the rule has no DTSTART, it is anchored at from.
+func NextOccurrences(rule string, from time.Time, n int) ([]time.Time, error) {
+	r, err := Parse(rule)
+	if err != nil {
+		return nil, err
+	}
+	return r.After(from, n), nil
+}
+
+// After returns the next n occurrences at or after from. If DTStart is zero, the
+// rule is anchored at from.
+func (r *RRule) After(from time.Time, n int) []time.Time {
+	var occurrences []time.Time
+	if n <= 0 {
+		return occurrences
+	}
+	for t := range r.iterate(r.anchor(from)) {
+		if t.Before(from) {
+			continue
+		}
+		occurrences = append(occurrences, t)
+		if len(occurrences) >= n {
+			break
+		}
+	}
+	return occurrences
+}
+
+// Between returns all occurrences in [start, end). If DTStart is zero, the rule is
+// anchored at start.
+func (r *RRule) Between(start, end time.Time) []time.Time {
+	var occurrences []time.Time
+	for t := range r.iterate(r.anchor(start)) {
+		if !t.Before(end) {
+			break
+		}
+		if !t.Before(start) {
+			occurrences = append(occurrences, t)
+		}
+	}
+	return occurrences
+}
+
+// All iterates over all occurrences of the rule in order. If DTStart is zero, the
+// rule is anchored at the current time. Rules without COUNT or UNTIL are unbounded.
+func (r *RRule) All() iter.Seq[time.Time] {
+	return r.iterate(r.anchor(time.Now()))
+}
+
+// anchor returns the start of the recurrence
+func (r *RRule) anchor(fallback time.Time) time.Time {
+	if !r.DTStart.IsZero() {
+		return r.DTStart
+	}
+	return fallback
+}
+
+// iterate yields the occurrences of the rule starting at dtstart
+func (r *RRule) iterate(dtstart time.Time) iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		dtstart = dtstart.Truncate(time.Second)
+		e := newExpansion(r, dtstart)
+
+		count := 0
+		lastYear := dtstart.Year()
+		empty := 0
+		var last time.Time
+		for k := int64(0); ; {
+			candidates, year, next := e.period(k)
+			if year > maxYear || year > lastYear+maxEmptyYears || empty > maxEmptyPeriods {
+				return
+			}
+			k = next
+
+			found := false
+			for _, c := range e.setPos(candidates) {
+				t := time.Date(c.Year(), c.Month(), c.Day(), c.Hour(), c.Minute(), c.Second(), 0, e.loc)
+				// Wall times that do not exist because of DST may normalise to an earlier time
+				if t.Before(dtstart) || !t.After(last) {
+					continue
+				}
+				if !r.Until.IsZero() && t.After(r.Until) {
+					return
+				}
+				if !yield(t) {
+					return
+				}
+				last, found = t, true
+				count++
+				if r.Count > 0 && count >= r.Count {
+					return
+				}
+			}
+			if found {
+				lastYear, empty = year, 0
+			} else {
+				empty++
+			}
+		}
+	}
+}
+
+// expansion holds the rule parts of a rule, with defaults taken from DTSTART.
+// Wall times are represented as times in UTC, so date arithmetic ignores DST.
+type expansion struct {
+	rule     *RRule
+	loc      *time.Location
+	interval int
+	// Civil start of the recurrence
+	start time.Time
+	// Expanded time of day sets, nil if the time is fixed by the period
+	hours, minutes, seconds []int
+	// Day filters, including defaults
+	byMonth, byMonthDay []int
+	byDay               []WeekdayNum
+}
+
+func newExpansion(r *RRule, dtstart time.Time) *expansion {
+	e := &expansion{
+		rule:       r,
+		loc:        dtstart.Location(),
+		interval:   max(r.Interval, 1),
+		start:      time.Date(dtstart.Year(), dtstart.Month(), dtstart.Day(), dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, time.UTC),
+		byMonth:    r.ByMonth,
+		byMonthDay: r.ByMonthDay,
+		byDay:      r.ByDay,
+	}
+
+	// Without any day rule parts, occurrences fall on the same day as DTSTART
+	if len(r.ByWeekNo) == 0 && len(r.ByYearDay) == 0 && len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		switch r.Freq {
+		case FreqYearly:
+			if len(e.byMonth) == 0 {
+				e.byMonth = []int{int(dtstart.Month())}
+			}
+			e.byMonthDay = []int{dtstart.Day()}
+		case FreqMonthly:
+			e.byMonthDay = []int{dtstart.Day()}
+		case FreqWeekly:
+			e.byDay = []WeekdayNum{{Weekday: weekdayOf(dtstart)}}
+		}
+	}
+
+	e.hours = timeSet(r.ByHour, dtstart.Hour(), r.Freq < FreqHourly)
+	e.minutes = timeSet(r.ByMinute, dtstart.Minute(), r.Freq < FreqMinutely)
+	e.seconds = timeSet(r.BySecond, dtstart.Second(), r.Freq < FreqSecondly)
+	return e
+}
+
+// timeSet returns the sorted values of a time rule part, or the DTSTART value if
+// the part is empty and the frequency expands it
+func timeSet(values []int, dtstart int, expand bool) []int {
+	if len(values) > 0 {
+		set := slices.Clone(values)
+		slices.Sort(set)
+		return slices.Compact(set)
+	}
+	if expand {
+		return []int{dtstart}
+	}
+	return nil
+}
+
+// period returns the candidate wall times of the kth period, its year and the
+// index of the next period worth looking at
+func (e *expansion) period(k int64) ([]time.Time, int, int64) {
+	if e.rule.Freq >= FreqHourly {
+		return e.subDailyPeriod(k)
+	}
+
+	first, days := e.periodDays(k)
+	var candidates []time.Time
+	for i := 0; i < days; i++ {
+		day := first.AddDate(0, 0, i)
+		if e.dayMatches(day) {
+			candidates = e.appendTimes(candidates, day, e.hours, e.minutes, e.seconds)
+		}
+	}
+	return candidates, first.Year(), k + 1
+}
+
+// periodDays returns the first day and number of days of the kth period
+func (e *expansion) periodDays(k int64) (time.Time, int) {
+	y, m, d := e.start.Date()
+	step := int(k) * e.interval
+	switch e.rule.Freq {
+	case FreqYearly:
+		first := time.Date(y+step, 1, 1, 0, 0, 0, 0, time.UTC)
+		return first, daysInYear(first.Year())
+	case FreqMonthly:
+		first := time.Date(y, m+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+		return first, daysInMonth(first.Year(), first.Month())
+	case FreqWeekly:
+		day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		back := (int(weekdayOf(day)) - int(e.rule.WeekStart) + 7) % 7
+		return day.AddDate(0, 0, 7*step-back), 7
+	default:
+		return time.Date(y, m, d+step, 0, 0, 0, 0, time.UTC), 1
+	}
+}
+
+// subDailyPeriod returns the candidates of an hourly, minutely or secondly period.
+// Periods on days, hours or minutes that are filtered out are skipped entirely.
+func (e *expansion) subDailyPeriod(k int64) ([]time.Time, int, int64) {
+	unit := int64(3600)
+	base := e.start.Truncate(time.Hour)
+	switch e.rule.Freq {
+	case FreqMinutely:
+		unit = 60
+		base = e.start.Truncate(time.Minute)
+	case FreqSecondly:
+		unit = 1
+		base = e.start
+	}
+	step := unit * int64(e.interval)
+	p := time.Unix(base.Unix()+k*step, 0).UTC()
+
+	skipTo := func(target time.Time) int64 {
+		next := (target.Unix() - base.Unix() + step - 1) / step
+		return max(next, k+1)
+	}
+
+	day := time.Date(p.Year(), p.Month(), p.Day(), 0, 0, 0, 0, time.UTC)
+	if !e.dayMatches(day) {
+		return nil, p.Year(), skipTo(day.AddDate(0, 0, 1))
+	}
+	if !allows(e.hours, p.Hour()) {
+		return nil, p.Year(), skipTo(p.Truncate(time.Hour).Add(time.Hour))
+	}
+	hours, minutes, seconds := []int{p.Hour()}, e.minutes, e.seconds
+	if e.rule.Freq >= FreqMinutely {
+		if !allows(e.minutes, p.Minute()) {
+			return nil, p.Year(), skipTo(p.Truncate(time.Minute).Add(time.Minute))
+		}
+		minutes = []int{p.Minute()}
+	}
+	if e.rule.Freq == FreqSecondly {
+		if !allows(e.seconds, p.Second()) {
+			return nil, p.Year(), k + 1
+		}
+		seconds = []int{p.Second()}
+	}
+	return e.appendTimes(nil, day, hours, minutes, seconds), p.Year(), k + 1
+}
+
+// appendTimes appends every combination of hours, minutes and seconds on day
+func (e *expansion) appendTimes(candidates []time.Time, day time.Time, hours, minutes, seconds []int) []time.Time {
+	for _, h := range hours {
+		for _, m := range minutes {
+			for _, s := range seconds {
+				candidates = append(candidates, day.Add(time.Duration(h)*time.Hour+time.Duration(m)*time.Minute+time.Duration(s)*time.Second))
+			}
+		}
+	}
+	return candidates
+}
+
+// setPos selects the BYSETPOS positions from the candidates of a period
+func (e *expansion) setPos(candidates []time.Time) []time.Time {
+	if len(e.rule.BySetPos) == 0 {
+		return candidates
+	}
+	selected := make([]bool, len(candidates))
+	for _, pos := range e.rule.BySetPos {
+		i := pos - 1
+		if pos < 0 {
+			i = len(candidates) + pos
+		}
+		if i >= 0 && i < len(candidates) {
+			selected[i] = true
+		}
+	}
+	var result []time.Time
+	for i, candidate := range candidates {
+		if selected[i] {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
+
+// dayMatches reports whether a day passes all day rule parts
+func (e *expansion) dayMatches(day time.Time) bool {
+	y, m, d := day.Date()
+	r := e.rule
+
+	if len(e.byMonth) > 0 && !slices.Contains(e.byMonth, int(m)) {
+		return false
+	}
+	if len(r.ByWeekNo) > 0 {
+		week, weeks := weekNumber(day, r.WeekStart)
+		if !matchesPosition(r.ByWeekNo, week, weeks) {
+			return false
+		}
+	}
+	if len(r.ByYearDay) > 0 && !matchesPosition(r.ByYearDay, day.YearDay(), daysInYear(y)) {
+		return false
+	}
+	if len(e.byMonthDay) > 0 && !matchesPosition(e.byMonthDay, d, daysInMonth(y, m)) {
+		return false
+	}
+	if len(e.byDay) > 0 {
+		// Numbered weekdays count within the month for monthly rules and yearly rules
+		// with BYMONTH, and within the year otherwise
+		index, total := day.YearDay(), daysInYear(y)
+		if r.Freq == FreqMonthly || len(r.ByMonth) > 0 {
+			index, total = d, daysInMonth(y, m)
+		}
+		nth, nthFromEnd := (index-1)/7+1, -((total-index)/7 + 1)
+
+		weekday := weekdayOf(day)
+		matched := false
+		for _, byDay := range e.byDay {
+			if byDay.Weekday == weekday && (byDay.N == 0 || byDay.N == nth || byDay.N == nthFromEnd) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesPosition reports whether value, out of total, is one of the positions,
+// where negative positions count from the end
+func matchesPosition(positions []int, value, total int) bool {
+	for _, pos := range positions {
+		if pos == value || (pos < 0 && total+pos+1 == value) {
+			return true
+		}
+	}
+	return false
+}
+
+// allows reports whether value is in set, where a nil set allows everything
+func allows(set []int, value int) bool {
+	return set == nil || slices.Contains(set, value)
+}
+
+// weekNumber returns the RFC 5545 week number of a day and the number of weeks in
+// its week-numbering year. Week 1 is the first week with at least four days in the year.
+func weekNumber(day time.Time, weekStart Weekday) (int, int) {
+	year := day.Year()
+	start := firstWeekStart(year, weekStart)
+	if day.Before(start) {
+		year--
+		start = firstWeekStart(year, weekStart)
+	} else if next := firstWeekStart(year+1, weekStart); !day.Before(next) {
+		year++
+		start = next
+	}
+	weeks := daysBetween(start, firstWeekStart(year+1, weekStart)) / 7
+	return daysBetween(start, day)/7 + 1, weeks
+}
+
+// firstWeekStart returns the first day of week 1 of a year
+func firstWeekStart(year int, weekStart Weekday) time.Time {
+	jan1 := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	back := (int(weekdayOf(jan1)) - int(weekStart) + 7) % 7
+	if back <= 3 {
+		return jan1.AddDate(0, 0, -back)
+	}
+	return jan1.AddDate(0, 0, 7-back)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func daysInYear(year int) int {
+	return time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
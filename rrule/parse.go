@@ -0,0 +1,326 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parse parses a recurrence rule, with or without an "RRULE:" prefix, optionally
+// preceded by a DTSTART line. All problems are reported at once.
+func Parse(s string) (*RRule, error) {
+	lines := strings.FieldsFunc(strings.TrimSpace(s), func(c rune) bool { return c == '\n' || c == '\r' })
+	if len(lines) == 0 {
+		return nil, &Error{Part: "RRULE", Reason: "is empty"}
+	}
+
+	r := &RRule{}
+	var errs []error
+	var rule string
+	haveRule := false
+	loc := time.UTC
+
+	// DTSTART determines the time zone of a floating UNTIL, so it is parsed first
+	for _, line := range lines {
+		name, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if ok && strings.HasPrefix(strings.ToUpper(name), "DTSTART") {
+			dtstart, err := parseDTStart(name, value)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			r.DTStart = dtstart
+			loc = dtstart.Location()
+		}
+	}
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		name, value, ok := strings.Cut(line, ":")
+		switch {
+		case ok && strings.HasPrefix(strings.ToUpper(name), "DTSTART"):
+			continue
+		case ok && strings.ToUpper(name) == "RRULE":
+		case !ok:
+			value = line
+		default:
+			errs = append(errs, &Error{Part: strings.ToUpper(name), Reason: "is not supported, only DTSTART and RRULE are"})
+			continue
+		}
+		if haveRule {
+			errs = append(errs, &Error{Part: "RRULE", Reason: "must only be given once"})
+			continue
+		}
+		haveRule = true
+		rule = value
+	}
+	if !haveRule {
+		return nil, errors.Join(append(errs, &Error{Part: "RRULE", Reason: "is missing"})...)
+	}
+
+	errs = append(errs, parseRule(r, rule, loc)...)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// parseRule parses the parts of an RRULE value into r
+func parseRule(r *RRule, rule string, loc *time.Location) []error {
+	var errs []error
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(rule, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+		if !ok {
+			errs = append(errs, &Error{Part: "RRULE", Value: part, Reason: "parts must have the form NAME=VALUE"})
+			continue
+		}
+		if seen[name] {
+			errs = append(errs, &Error{Part: name, Reason: "must only be given once"})
+			continue
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			err = parseFrequency(value, &r.Freq)
+		case "INTERVAL":
+			r.Interval, err = parseInt(name, value)
+		case "COUNT":
+			r.Count, err = parseInt(name, value)
+		case "UNTIL":
+			r.Until, err = parseTime(name, value, loc, true)
+		case "BYSECOND":
+			r.BySecond, err = parseIntList(name, value)
+		case "BYMINUTE":
+			r.ByMinute, err = parseIntList(name, value)
+		case "BYHOUR":
+			r.ByHour, err = parseIntList(name, value)
+		case "BYDAY":
+			r.ByDay, err = parseWeekdayList(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseIntList(name, value)
+		case "BYYEARDAY":
+			r.ByYearDay, err = parseIntList(name, value)
+		case "BYWEEKNO":
+			r.ByWeekNo, err = parseIntList(name, value)
+		case "BYMONTH":
+			r.ByMonth, err = parseIntList(name, value)
+		case "BYSETPOS":
+			r.BySetPos, err = parseIntList(name, value)
+		case "WKST":
+			r.WeekStart, err = parseWeekday(name, value)
+		default:
+			err = &Error{Part: name, Reason: "is not a known rule part"}
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if !seen["FREQ"] {
+		errs = append(errs, &Error{Part: "FREQ", Reason: "is required"})
+	}
+	if seen["INTERVAL"] && r.Interval == 0 {
+		errs = append(errs, &Error{Part: "INTERVAL", Value: "0", Reason: "must be at least 1"})
+	}
+	if seen["COUNT"] && r.Count == 0 {
+		errs = append(errs, &Error{Part: "COUNT", Value: "0", Reason: "must be at least 1"})
+	}
+	return errs
+}
+
+// Validate checks the ranges of all rule parts and the combinations RFC 5545 allows.
+// All problems are reported at once.
+func (r *RRule) Validate() error {
+	var errs []error
+
+	if r.Freq < FreqYearly || r.Freq > FreqSecondly {
+		errs = append(errs, &Error{Part: "FREQ", Value: r.Freq.String(), Reason: "is not a known frequency"})
+	}
+	if r.Interval < 0 {
+		errs = append(errs, &Error{Part: "INTERVAL", Value: strconv.Itoa(r.Interval), Reason: "must be at least 1"})
+	}
+	if r.Count < 0 {
+		errs = append(errs, &Error{Part: "COUNT", Value: strconv.Itoa(r.Count), Reason: "must be at least 1"})
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		errs = append(errs, &Error{Part: "COUNT", Reason: "must not be combined with UNTIL"})
+	}
+	if r.WeekStart < MO || r.WeekStart > SU {
+		errs = append(errs, &Error{Part: "WKST", Value: r.WeekStart.String(), Reason: "is not a known weekday"})
+	}
+
+	errs = append(errs, checkRange("BYSECOND", r.BySecond, 0, 59, false)...)
+	errs = append(errs, checkRange("BYMINUTE", r.ByMinute, 0, 59, false)...)
+	errs = append(errs, checkRange("BYHOUR", r.ByHour, 0, 23, false)...)
+	errs = append(errs, checkRange("BYMONTHDAY", r.ByMonthDay, 1, 31, true)...)
+	errs = append(errs, checkRange("BYYEARDAY", r.ByYearDay, 1, 366, true)...)
+	errs = append(errs, checkRange("BYWEEKNO", r.ByWeekNo, 1, 53, true)...)
+	errs = append(errs, checkRange("BYMONTH", r.ByMonth, 1, 12, false)...)
+	errs = append(errs, checkRange("BYSETPOS", r.BySetPos, 1, 366, true)...)
+
+	for _, day := range r.ByDay {
+		switch {
+		case day.Weekday < MO || day.Weekday > SU:
+			errs = append(errs, &Error{Part: "BYDAY", Value: day.String(), Reason: "is not a known weekday"})
+		case day.N == 0:
+		case day.N < -53 || day.N > 53:
+			errs = append(errs, &Error{Part: "BYDAY", Value: day.String(), Reason: "position must be between 1 and 53, or -53 and -1"})
+		case r.Freq != FreqMonthly && r.Freq != FreqYearly:
+			errs = append(errs, &Error{Part: "BYDAY", Value: day.String(), Reason: "a position is only allowed with FREQ=MONTHLY or FREQ=YEARLY"})
+		case r.Freq == FreqYearly && len(r.ByWeekNo) > 0:
+			errs = append(errs, &Error{Part: "BYDAY", Value: day.String(), Reason: "a position is not allowed together with BYWEEKNO"})
+		}
+	}
+
+	if len(r.ByMonthDay) > 0 && r.Freq == FreqWeekly {
+		errs = append(errs, &Error{Part: "BYMONTHDAY", Reason: "is not allowed with FREQ=WEEKLY"})
+	}
+	if len(r.ByYearDay) > 0 && (r.Freq == FreqMonthly || r.Freq == FreqWeekly || r.Freq == FreqDaily) {
+		errs = append(errs, &Error{Part: "BYYEARDAY", Reason: fmt.Sprintf("is not allowed with FREQ=%s", r.Freq)})
+	}
+	if len(r.ByWeekNo) > 0 && r.Freq != FreqYearly {
+		errs = append(errs, &Error{Part: "BYWEEKNO", Reason: "is only allowed with FREQ=YEARLY"})
+	}
+	if len(r.BySetPos) > 0 && len(r.BySecond)+len(r.ByMinute)+len(r.ByHour)+len(r.ByDay)+
+		len(r.ByMonthDay)+len(r.ByYearDay)+len(r.ByWeekNo)+len(r.ByMonth) == 0 {
+		errs = append(errs, &Error{Part: "BYSETPOS", Reason: "requires at least one other BYxxx rule part"})
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkRange checks that all values are within [min, max], or [-max, -min] if negative values are allowed
+func checkRange(part string, values []int, min, max int, negative bool) []error {
+	var errs []error
+	for _, value := range values {
+		if value >= min && value <= max {
+			continue
+		}
+		if negative && value <= -min && value >= -max {
+			continue
+		}
+		reason := fmt.Sprintf("must be between %d and %d", min, max)
+		if negative {
+			reason += fmt.Sprintf(", or %d and %d", -max, -min)
+		}
+		errs = append(errs, &Error{Part: part, Value: strconv.Itoa(value), Reason: reason})
+	}
+	return errs
+}
+
+func parseFrequency(value string, freq *Frequency) error {
+	for i, name := range frequencyNames {
+		if strings.EqualFold(value, name) {
+			*freq = Frequency(i)
+			return nil
+		}
+	}
+	return &Error{Part: "FREQ", Value: value, Reason: "must be one of " + strings.Join(frequencyNames[:], ", ")}
+}
+
+func parseInt(part, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, &Error{Part: part, Value: value, Reason: "is not an integer"}
+	}
+	return n, nil
+}
+
+func parseIntList(part, value string) ([]int, error) {
+	var values []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := parseInt(part, strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+func parseWeekday(part, value string) (Weekday, error) {
+	for i, name := range weekdayNames {
+		if strings.EqualFold(value, name) {
+			return Weekday(i), nil
+		}
+	}
+	return 0, &Error{Part: part, Value: value, Reason: "must be one of " + strings.Join(weekdayNames[:], ", ")}
+}
+
+func parseWeekdayList(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) < 2 {
+			return nil, &Error{Part: "BYDAY", Value: item, Reason: "must be a weekday such as MO or 1MO"}
+		}
+		weekday, err := parseWeekday("BYDAY", item[len(item)-2:])
+		if err != nil {
+			return nil, &Error{Part: "BYDAY", Value: item, Reason: "must be a weekday such as MO or 1MO"}
+		}
+		day := WeekdayNum{Weekday: weekday}
+		if prefix := item[:len(item)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 {
+				return nil, &Error{Part: "BYDAY", Value: item, Reason: "position must be a non-zero integer"}
+			}
+			day.N = n
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// parseDTStart parses a DTSTART property, e.g. "DTSTART;TZID=Europe/Amsterdam" and "20240101T020000"
+func parseDTStart(name, value string) (time.Time, error) {
+	loc := time.UTC
+	params := strings.Split(name, ";")[1:]
+	for _, param := range params {
+		key, paramValue, _ := strings.Cut(param, "=")
+		switch strings.ToUpper(key) {
+		case "TZID":
+			tz, err := time.LoadLocation(paramValue)
+			if err != nil {
+				return time.Time{}, &Error{Part: "DTSTART", Value: paramValue, Reason: "is not a known time zone"}
+			}
+			loc = tz
+		case "VALUE":
+		default:
+			return time.Time{}, &Error{Part: "DTSTART", Value: param, Reason: "is not a supported parameter"}
+		}
+	}
+	return parseTime("DTSTART", value, loc, false)
+}
+
+// parseTime parses a date or date-time. Floating times are interpreted in loc. A date
+// is the start of the day, or the end of the day if endOfDay is set.
+func parseTime(part, value string, loc *time.Location, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(utcLayout, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(floatingLayout, value, loc); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(dateLayout, value, loc); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1).Add(-time.Second)
+		}
+		return t, nil
+	}
+	return time.Time{}, &Error{Part: part, Value: value, Reason: "must be a date (20060102) or date-time (20060102T150405Z)"}
+}
@@ -0,0 +1,209 @@
+// Package rrule builds, parses and expands RFC 5545 recurrence rules, as used
+// by the RRule of a time trigger:
+//
+//	rule := rrule.Daily().At(2, 0).Weekdays().String()
+//	// FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;BYHOUR=2;BYMINUTE=0;BYSECOND=0
+//
+//	next, err := rrule.NextOccurrences(rule, time.Now(), 5)
+//
+// A rule may be preceded by a DTSTART line to anchor its occurrences. Without
+// one, occurrences are anchored at the time the calculation starts from.
+package rrule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the FREQ of a recurrence rule.
+type Frequency int
+
+const (
+	FreqYearly Frequency = iota
+	FreqMonthly
+	FreqWeekly
+	FreqDaily
+	FreqHourly
+	FreqMinutely
+	FreqSecondly
+)
+
+var frequencyNames = [...]string{"YEARLY", "MONTHLY", "WEEKLY", "DAILY", "HOURLY", "MINUTELY", "SECONDLY"}
+
+func (f Frequency) String() string {
+	if f < FreqYearly || f > FreqSecondly {
+		return "Frequency(" + strconv.Itoa(int(f)) + ")"
+	}
+	return frequencyNames[f]
+}
+
+// Weekday is a day of the week as used in BYDAY and WKST. Weeks start on Monday.
+type Weekday int
+
+const (
+	MO Weekday = iota
+	TU
+	WE
+	TH
+	FR
+	SA
+	SU
+)
+
+var weekdayNames = [...]string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}
+
+func (d Weekday) String() string {
+	if d < MO || d > SU {
+		return "Weekday(" + strconv.Itoa(int(d)) + ")"
+	}
+	return weekdayNames[d]
+}
+
+// TimeWeekday converts the weekday to a time.Weekday.
+func (d Weekday) TimeWeekday() time.Weekday {
+	return time.Weekday((int(d) + 1) % 7)
+}
+
+// weekdayOf returns the weekday of a time.
+func weekdayOf(t time.Time) Weekday {
+	return Weekday((int(t.Weekday()) + 6) % 7)
+}
+
+// WeekdayNum is a BYDAY entry. A non-zero N selects the Nth occurrence of the
+// weekday within the month or year, counting from the end if negative.
+type WeekdayNum struct {
+	Weekday Weekday
+	N       int
+}
+
+func (w WeekdayNum) String() string {
+	if w.N == 0 {
+		return w.Weekday.String()
+	}
+	return strconv.Itoa(w.N) + w.Weekday.String()
+}
+
+// RRule is a parsed recurrence rule.
+type RRule struct {
+	// Frequency of the rule
+	Freq Frequency
+	// Interval between periods. Zero is treated as 1
+	Interval int
+	// Number of occurrences, zero for no limit
+	Count int
+	// Last possible occurrence, zero for no limit
+	Until time.Time
+	// Seconds of the minute (0-59)
+	BySecond []int
+	// Minutes of the hour (0-59)
+	ByMinute []int
+	// Hours of the day (0-23)
+	ByHour []int
+	// Days of the week, optionally numbered
+	ByDay []WeekdayNum
+	// Days of the month (1-31, or -31 to -1 from the end)
+	ByMonthDay []int
+	// Days of the year (1-366, or -366 to -1 from the end)
+	ByYearDay []int
+	// Weeks of the year (1-53, or -53 to -1 from the end)
+	ByWeekNo []int
+	// Months of the year (1-12)
+	ByMonth []int
+	// Positions within the set of occurrences of each period
+	BySetPos []int
+	// First day of the week, used with WEEKLY and BYWEEKNO. Defaults to Monday
+	WeekStart Weekday
+	// Start of the recurrence, zero if not anchored
+	DTStart time.Time
+}
+
+// String renders the rule in RFC 5545 format. If DTStart is set, the rule is
+// preceded by a DTSTART line.
+func (r *RRule) String() string {
+	var parts []string
+	add := func(name, value string) {
+		parts = append(parts, name+"="+value)
+	}
+
+	add("FREQ", r.Freq.String())
+	if r.Interval > 1 {
+		add("INTERVAL", strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		add("COUNT", strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		add("UNTIL", r.Until.UTC().Format(utcLayout))
+	}
+	addInts := func(name string, values []int) {
+		if len(values) > 0 {
+			add(name, joinInts(values))
+		}
+	}
+	addInts("BYMONTH", r.ByMonth)
+	addInts("BYWEEKNO", r.ByWeekNo)
+	addInts("BYYEARDAY", r.ByYearDay)
+	addInts("BYMONTHDAY", r.ByMonthDay)
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = day.String()
+		}
+		add("BYDAY", strings.Join(days, ","))
+	}
+	addInts("BYHOUR", r.ByHour)
+	addInts("BYMINUTE", r.ByMinute)
+	addInts("BYSECOND", r.BySecond)
+	addInts("BYSETPOS", r.BySetPos)
+	if r.WeekStart != MO {
+		add("WKST", r.WeekStart.String())
+	}
+
+	rule := strings.Join(parts, ";")
+	if r.DTStart.IsZero() {
+		return rule
+	}
+	return formatDTStart(r.DTStart) + "\nRRULE:" + rule
+}
+
+const (
+	utcLayout      = "20060102T150405Z"
+	floatingLayout = "20060102T150405"
+	dateLayout     = "20060102"
+)
+
+// formatDTStart renders a DTSTART line, with a TZID unless the time is in UTC or local time.
+func formatDTStart(t time.Time) string {
+	loc := t.Location()
+	if loc == time.UTC || loc == time.Local {
+		return "DTSTART:" + t.UTC().Format(utcLayout)
+	}
+	return fmt.Sprintf("DTSTART;TZID=%s:%s", loc.String(), t.Format(floatingLayout))
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = strconv.Itoa(value)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Error describes a single problem with a recurrence rule.
+type Error struct {
+	// Rule part, e.g. "BYHOUR"
+	Part string
+	// Offending value, empty if the problem is not tied to a value
+	Value string
+	// What is wrong
+	Reason string
+}
+
+func (e *Error) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("rrule: %s %s", e.Part, e.Reason)
+	}
+	return fmt.Sprintf("rrule: invalid %s %q: %s", e.Part, e.Value, e.Reason)
+}
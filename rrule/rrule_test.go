@@ -0,0 +1,202 @@
+package rrule
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// formatTimes formats times with a layout
+func formatTimes(times []time.Time, layout string) []string {
+	formatted := make([]string, len(times))
+	for i, t := range times {
+		formatted[i] = t.Format(layout)
+	}
+	return formatted
+}
+
+// officeHours returns every 20 minutes from 09:00 to 16:40 on a day
+func officeHours(day string) []string {
+	var times []string
+	for hour := 9; hour <= 16; hour++ {
+		for minute := 0; minute < 60; minute += 20 {
+			times = append(times, fmt.Sprintf("%sT%02d%02d", day, hour, minute))
+		}
+	}
+	return times
+}
+
+func TestBuilderNextOccurrences(t *testing.T) {
+	rule := Daily().At(2, 0).Weekdays().String()
+	next, err := NextOccurrences(rule, time.Date(2024, 1, 5, 3, 0, 0, 0, time.UTC), 3)
+	if err != nil {
+		t.Fatalf("calculating occurrences of %s: %v", rule, err)
+	}
+	expected := []string{"Mon 2024-01-08 02:00", "Tue 2024-01-09 02:00", "Wed 2024-01-10 02:00"}
+	if got := formatTimes(next, "Mon 2006-01-02 15:04"); !slices.Equal(got, expected) {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+}
+
+// TestOccurrences checks the examples of RFC 5545, anchored in New York
+func TestOccurrences(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     string
+		n        int
+		layout   string
+		expected []string
+	}{
+		{
+			name:     "daily for 10 occurrences",
+			rule:     "DTSTART;TZID=America/New_York:19970902T090000\nRRULE:FREQ=DAILY;COUNT=10",
+			n:        20,
+			layout:   "0102",
+			expected: []string{"0902", "0903", "0904", "0905", "0906", "0907", "0908", "0909", "0910", "0911"},
+		},
+		{
+			name:     "every 3 hours until 17:00",
+			rule:     "DTSTART;TZID=America/New_York:19970902T090000\nRRULE:FREQ=HOURLY;INTERVAL=3;UNTIL=19970902T210000Z",
+			n:        10,
+			layout:   "15:04 MST",
+			expected: []string{"09:00 EDT", "12:00 EDT", "15:00 EDT"},
+		},
+		{
+			name:     "every other week on Tuesday and Thursday",
+			rule:     "DTSTART;TZID=America/New_York:19970902T090000\nRRULE:FREQ=WEEKLY;INTERVAL=2;WKST=SU;BYDAY=TU,TH;COUNT=8",
+			n:        10,
+			layout:   "0102",
+			expected: []string{"0902", "0904", "0916", "0918", "0930", "1002", "1014", "1016"},
+		},
+		{
+			name:     "first Friday of the month",
+			rule:     "DTSTART;TZID=America/New_York:19970905T090000\nRRULE:FREQ=MONTHLY;COUNT=10;BYDAY=1FR",
+			n:        10,
+			layout:   "20060102",
+			expected: []string{"19970905", "19971003", "19971107", "19971205", "19980102", "19980206", "19980306", "19980403", "19980501", "19980605"},
+		},
+		{
+			name:     "third-to-last day of the month",
+			rule:     "DTSTART;TZID=America/New_York:19970928T090000\nRRULE:FREQ=MONTHLY;BYMONTHDAY=-3",
+			n:        6,
+			layout:   "20060102",
+			expected: []string{"19970928", "19971029", "19971128", "19971229", "19980129", "19980226"},
+		},
+		{
+			name:     "last weekday of the month",
+			rule:     "DTSTART;TZID=America/New_York:19970929T090000\nRRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+			n:        7,
+			layout:   "20060102",
+			expected: []string{"19970930", "19971031", "19971128", "19971231", "19980130", "19980227", "19980331"},
+		},
+		{
+			name:     "Monday of week 20",
+			rule:     "DTSTART;TZID=America/New_York:19970512T090000\nRRULE:FREQ=YEARLY;BYWEEKNO=20;BYDAY=MO",
+			n:        3,
+			layout:   "20060102",
+			expected: []string{"19970512", "19980511", "19990517"},
+		},
+		{
+			name:     "20th Monday of the year",
+			rule:     "DTSTART;TZID=America/New_York:19970519T090000\nRRULE:FREQ=YEARLY;BYDAY=20MO",
+			n:        3,
+			layout:   "20060102",
+			expected: []string{"19970519", "19980518", "19990517"},
+		},
+		{
+			name:     "every 20 minutes during office hours",
+			rule:     "DTSTART;TZID=America/New_York:19970902T090000\nRRULE:FREQ=MINUTELY;INTERVAL=20;BYHOUR=9,10,11,12,13,14,15,16",
+			n:        26,
+			layout:   "0102T1504",
+			expected: append(officeHours("0902"), "0903T0900", "0903T0920"),
+		},
+		{
+			name:     "leap days only",
+			rule:     "DTSTART:20230101T000000Z\nRRULE:FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29",
+			n:        2,
+			layout:   "20060102",
+			expected: []string{"20240229", "20280229"},
+		},
+		{
+			name:     "impossible date never occurs",
+			rule:     "DTSTART:20230101T000000Z\nRRULE:FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+			n:        1,
+			layout:   "20060102",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("parsing rule: %v", err)
+			}
+			if got := formatTimes(r.After(r.DTStart, tt.n), tt.layout); !slices.Equal(got, tt.expected) {
+				t.Errorf("got %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseRejectsInvalidRules(t *testing.T) {
+	tests := []struct {
+		rule     string
+		expected string
+	}{
+		{rule: "FREQ=DAILY;BYHOUR=25", expected: "BYHOUR"},
+		{rule: "FREQ=FORTNIGHTLY", expected: "FREQ"},
+		{rule: "BYHOUR=2", expected: "FREQ is required"},
+		{rule: "FREQ=WEEKLY;BYDAY=1MO", expected: "a position is only allowed"},
+		{rule: "FREQ=DAILY;COUNT=2;UNTIL=2024", expected: "UNTIL"},
+		{rule: "FREQ=DAILY;COUNT=2;UNTIL=20240101", expected: "COUNT must not be combined with UNTIL"},
+		{rule: "FREQ=DAILY;BYWEEKNO=1", expected: "BYWEEKNO is only allowed with FREQ=YEARLY"},
+		{rule: "FREQ=DAILY;BYSETPOS=1", expected: "BYSETPOS requires"},
+		{rule: "FREQ=DAILY;FOO=1", expected: "FOO is not a known rule part"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			_, err := Parse(tt.rule)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("got %v, expected an error containing %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateScheduleMinInterval(t *testing.T) {
+	minInterval := 3600
+	tests := []struct {
+		name      string
+		triggers  []models.WorkflowTrigger
+		wantError bool
+	}{
+		{
+			name: "triggers closer than the minimum interval",
+			triggers: []models.WorkflowTrigger{
+				&models.TimeTrigger{RRule: Daily().At(2, 0).String()},
+				&models.TimeTrigger{RRule: Daily().At(2, 30).String()},
+			},
+			wantError: true,
+		},
+		{
+			name:     "hourly trigger",
+			triggers: []models.WorkflowTrigger{&models.TimeTrigger{RRule: Hourly().AtMinutes(0).String()}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := models.WorkflowSchedule{Triggers: tt.triggers, MinInterval: &minInterval}
+			err := ValidateSchedule(schedule, time.Date(2024, 1, 5, 3, 0, 0, 0, time.UTC))
+			if (err != nil) != tt.wantError {
+				t.Errorf("got error %v, expected error %t", err, tt.wantError)
+			}
+		})
+	}
+}
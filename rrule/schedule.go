@@ -0,0 +1,82 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// scheduleSamples is the number of occurrences per time trigger checked against the minimum interval
+const scheduleSamples = 500
+
+// MinIntervalError reports two occurrences that are closer than the minimum interval of a schedule.
+type MinIntervalError struct {
+	// Earlier occurrence
+	Previous time.Time
+	// Later occurrence
+	Next time.Time
+	// Minimum interval of the schedule
+	MinInterval time.Duration
+}
+
+func (e *MinIntervalError) Error() string {
+	return fmt.Sprintf("rrule: occurrences at %s and %s are %s apart, less than the minimum interval of %s",
+		e.Previous.Format(time.RFC3339), e.Next.Format(time.RFC3339), e.Next.Sub(e.Previous), e.MinInterval)
+}
+
+// CheckMinInterval returns a *MinIntervalError for the first two consecutive occurrences
+// that are less than minInterval apart. The occurrences must be sorted.
+func CheckMinInterval(occurrences []time.Time, minInterval time.Duration) error {
+	for i := 1; i < len(occurrences); i++ {
+		if occurrences[i].Sub(occurrences[i-1]) < minInterval {
+			return &MinIntervalError{Previous: occurrences[i-1], Next: occurrences[i], MinInterval: minInterval}
+		}
+	}
+	return nil
+}
+
+// ValidateSchedule checks the recurrence rules of all time triggers of a schedule, and
+// whether their combined occurrences after from respect the MinInterval of the schedule,
+// which is in seconds.
+func ValidateSchedule(schedule models.WorkflowSchedule, from time.Time) error {
+	var errs []error
+	var occurrences []time.Time
+	var horizon time.Time
+
+	for i, trigger := range schedule.Triggers {
+		var rule string
+		switch t := trigger.(type) {
+		case *models.TimeTrigger:
+			rule = t.RRule
+		case models.TimeTrigger:
+			rule = t.RRule
+		default:
+			continue
+		}
+
+		r, err := Parse(rule)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("trigger[%d]: %w", i, err))
+			continue
+		}
+		next := r.After(from, scheduleSamples)
+		occurrences = append(occurrences, next...)
+
+		// Beyond the last sample of a trigger, its occurrences are unknown
+		if len(next) == scheduleSamples && (horizon.IsZero() || next[len(next)-1].Before(horizon)) {
+			horizon = next[len(next)-1]
+		}
+	}
+	if len(errs) > 0 || schedule.MinInterval == nil {
+		return errors.Join(errs...)
+	}
+
+	slices.SortFunc(occurrences, func(a, b time.Time) int { return a.Compare(b) })
+	if !horizon.IsZero() {
+		occurrences = slices.DeleteFunc(occurrences, func(t time.Time) bool { return t.After(horizon) })
+	}
+	return CheckMinInterval(occurrences, time.Duration(*schedule.MinInterval)*time.Second)
+}
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// CommitApplier cherry-picks and reverts commits, which the API does not support directly
+type CommitApplier struct {
+	client *client.Client
+}
+
+// NewCommitApplier creates a new CommitApplier
+func NewCommitApplier(client *client.Client) *CommitApplier {
+	return &CommitApplier{
+		client: client,
+	}
+}
+
+// CherryPick applies the changes a commit made to its previous commit onto a branch and commits them with
+// the message of the commit. The commit can be given by anything RefResolver accepts, e.g. a short hash.
+// A change conflicts if the branch changed the same object differently, except that changed structured
+// objects are merged by rows when possible; nothing is applied if any change conflicts, in which case
+// ErrUnresolvedConflicts is returned with the result. Changes the branch already has are skipped.
+// The changes are uploaded one at a time before committing and are not rolled back, so if one fails,
+// those uploaded before it are left uncommitted on the branch.
+func (a *CommitApplier) CherryPick(ctx context.Context, repository, commitHash, ontoBranch string) (*models.CommitApplyResult, error) {
+	return a.apply(ctx, repository, commitHash, ontoBranch, false)
+}
+
+// RevertCommit undoes the changes a commit made to its previous commit on a branch and commits the reversal,
+// like CherryPick with the changes inverted: objects the commit added are removed, removed objects restored,
+// changed objects reset to their previous content and moved objects moved back.
+func (a *CommitApplier) RevertCommit(ctx context.Context, repository, commitHash, branch string) (*models.CommitApplyResult, error) {
+	return a.apply(ctx, repository, commitHash, branch, true)
+}
+
+// patchChange is a change to apply, from the content at the old ref to the content at the new ref
+type patchChange struct {
+	changeType models.ChangeType
+	oldPath    string
+	newPath    string
+	object     models.Object
+}
+
+func (a *CommitApplier) apply(ctx context.Context, repository, commitHash, branch string, revert bool) (*models.CommitApplyResult, error) {
+	apiClient := a.client.WithContext(ctx)
+	objectService := NewObjectService(apiClient)
+
+	resolved, err := NewRefResolver(a.client).ResolveRef(ctx, repository, commitHash)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := NewCommitGraph(a.client, repository).lookup(ctx)(resolved.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if commit.PreviousHash == nil {
+		return nil, fmt.Errorf("commit %s has no previous commit to compare with", commit.Hash)
+	}
+	diff, _, err := NewDiffService(apiClient).CompareRefs(repository, *commit.PreviousHash, commit.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Moves are paired with their source, which is not removed separately
+	var changes []patchChange
+	moved := make(map[string]bool)
+	for _, item := range diff.Items {
+		if item.Type != models.ChangeTypeMoved {
+			continue
+		}
+		change := patchChange{changeType: models.ChangeTypeAdded, newPath: item.Object.Path, object: item.Object}
+		if source := moveSource(objectService, repository, diff.Items, item, *commit); source != "" {
+			change = patchChange{changeType: models.ChangeTypeMoved, oldPath: source, newPath: item.Object.Path, object: item.Object}
+			moved[treePath(models.Object{Path: source})] = true
+		}
+		changes = append(changes, change)
+	}
+	for _, item := range diff.Items {
+		p := item.Object.Path
+		switch item.Type {
+		case models.ChangeTypeAdded:
+			changes = append(changes, patchChange{changeType: models.ChangeTypeAdded, newPath: p, object: item.Object})
+		case models.ChangeTypeRemoved:
+			if !moved[treePath(models.Object{Path: p})] {
+				changes = append(changes, patchChange{changeType: models.ChangeTypeRemoved, oldPath: p, object: item.Object})
+			}
+		case models.ChangeTypeChanged, models.ChangeTypeConflict:
+			changes = append(changes, patchChange{changeType: models.ChangeTypeChanged, oldPath: p, newPath: p, object: item.Object})
+		}
+	}
+
+	oldRef, newRef := *commit.PreviousHash, commit.Hash
+	if revert {
+		oldRef, newRef = newRef, oldRef
+		for i, change := range changes {
+			change.oldPath, change.newPath = change.newPath, change.oldPath
+			switch change.changeType {
+			case models.ChangeTypeAdded:
+				change.changeType = models.ChangeTypeRemoved
+			case models.ChangeTypeRemoved:
+				change.changeType = models.ChangeTypeAdded
+			}
+			changes[i] = change
+		}
+	}
+
+	result := &models.CommitApplyResult{
+		Repository: repository,
+		Branch:     branch,
+		CommitHash: commit.Hash,
+		Revert:     revert,
+		Changes:    []models.AppliedChange{},
+	}
+	var actions []func() error
+	for _, change := range changes {
+		applied, action, err := a.planChange(ctx, objectService, repository, branch, oldRef, newRef, change)
+		if err != nil {
+			return nil, err
+		}
+		if applied.Status == models.AppliedChangeConflict {
+			result.Conflicts++
+		}
+		if action != nil {
+			actions = append(actions, action)
+		}
+		result.Changes = append(result.Changes, applied)
+	}
+	slices.SortFunc(result.Changes, func(a, b models.AppliedChange) int {
+		return strings.Compare(a.Path+a.PreviousPath, b.Path+b.PreviousPath)
+	})
+	if result.Conflicts > 0 {
+		return result, fmt.Errorf("%w: %d conflicting change(s) applying %s to %s", ErrUnresolvedConflicts, result.Conflicts, commit.Hash, branch)
+	}
+	if len(actions) == 0 {
+		return result, nil
+	}
+
+	for _, action := range actions {
+		if err := action(); err != nil {
+			return result, err
+		}
+	}
+	message := fmt.Sprintf("%s\n\n(cherry picked from commit %s)", commit.Message, commit.Hash)
+	if revert {
+		message = fmt.Sprintf("Revert \"%s\"\n\nThis reverts commit %s.", commit.Message, commit.Hash)
+	}
+	if _, err := NewCommitService(apiClient).CreateCommit(repository, branch, message); err != nil {
+		return result, err
+	}
+	result.CommitMessage = message
+	return result, nil
+}
+
+// planChange checks a change against the branch and returns how it applies and the action applying it,
+// which is nil if there is nothing to do
+func (a *CommitApplier) planChange(ctx context.Context, objectService *ObjectService, repository, branch, oldRef, newRef string, change patchChange) (models.AppliedChange, func() error, error) {
+	applied := models.AppliedChange{Path: change.newPath, Type: change.changeType, Status: models.AppliedChangeApplied}
+	if change.changeType == models.ChangeTypeRemoved {
+		applied.Path = change.oldPath
+	}
+	if change.changeType == models.ChangeTypeMoved {
+		applied.PreviousPath = change.oldPath
+	}
+	conflict := func(reason string) (models.AppliedChange, func() error, error) {
+		applied.Status = models.AppliedChangeConflict
+		applied.Reason = reason
+		return applied, nil, nil
+	}
+	skip := func() (models.AppliedChange, func() error, error) {
+		applied.Status = models.AppliedChangeSkipped
+		return applied, nil, nil
+	}
+
+	var oldContent, newContent, atOld, atNew []byte
+	var err error
+	if change.oldPath != "" {
+		if oldContent, err = fetchExistingContent(objectService, repository, change.oldPath, oldRef); err != nil {
+			return applied, nil, err
+		}
+		if atOld, err = fetchExistingContent(objectService, repository, change.oldPath, branch); err != nil {
+			return applied, nil, err
+		}
+	}
+	if change.newPath != "" {
+		if newContent, err = fetchExistingContent(objectService, repository, change.newPath, newRef); err != nil {
+			return applied, nil, err
+		}
+		if atNew, err = fetchExistingContent(objectService, repository, change.newPath, branch); err != nil {
+			return applied, nil, err
+		}
+	}
+	upload := func(objectPath string, content []byte) func() error {
+		return func() error {
+			name := path.Base(objectPath)
+			_, _, err := objectService.UploadObject(repository, branch, objectPath, name, map[string][]byte{name: content})
+			return err
+		}
+	}
+
+	switch change.changeType {
+	case models.ChangeTypeAdded:
+		if atNew != nil {
+			if bytes.Equal(atNew, newContent) {
+				return skip()
+			}
+			return conflict("object exists on the branch with different content")
+		}
+		return applied, upload(change.newPath, newContent), nil
+
+	case models.ChangeTypeRemoved:
+		if atOld == nil {
+			return skip()
+		}
+		if !bytes.Equal(atOld, oldContent) {
+			return conflict("object was changed on the branch")
+		}
+		return applied, func() error {
+			_, err := objectService.DeleteObject(repository, branch, change.oldPath, path.Base(change.oldPath))
+			return err
+		}, nil
+
+	case models.ChangeTypeMoved:
+		if atOld == nil && bytes.Equal(atNew, newContent) {
+			return skip()
+		}
+		if atOld == nil {
+			return conflict("object was removed or moved on the branch")
+		}
+		if !bytes.Equal(atOld, oldContent) {
+			return conflict("object was changed on the branch")
+		}
+		if atNew != nil {
+			return conflict("another object exists at the new path on the branch")
+		}
+		return applied, func() error {
+			if _, _, err := objectService.MoveObject(repository, branch, change.oldPath, change.newPath, path.Base(change.newPath)); err != nil {
+				return err
+			}
+			if bytes.Equal(oldContent, newContent) {
+				return nil
+			}
+			return upload(change.newPath, newContent)()
+		}, nil
+
+	default:
+		if atNew == nil {
+			return conflict("object was removed or moved on the branch")
+		}
+		if bytes.Equal(atNew, newContent) {
+			return skip()
+		}
+		if bytes.Equal(atNew, oldContent) {
+			return applied, upload(change.newPath, newContent), nil
+		}
+		if change.object.Type != models.ObjectTypeStructured {
+			return conflict("object was changed on the branch")
+		}
+		merged, objectMerge, err := NewObjectMerger(a.client).MergeObject(ctx, repository, change.newPath, oldRef, branch, newRef, ObjectMergeOptions{})
+		if err != nil {
+			return applied, nil, err
+		}
+		if !objectMerge.Resolved() {
+			return conflict("object was changed on the branch and its rows cannot be merged")
+		}
+		applied.Status = models.AppliedChangeMerged
+		return applied, upload(change.newPath, merged), nil
+	}
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// commitApplyServer serves a repository with a commit bbbb2222 that changes /a.csv and adds /b.csv, and a
+// main branch with the given objects, recording every upload, delete and commit
+func commitApplyServer(t *testing.T, objectType models.ObjectType, main map[string]string, writes *[]string) *httptest.Server {
+	refs := map[string]map[string]string{
+		"aaaa1111": {"/a.csv": "id\n1\n"},
+		"bbbb2222": {"/a.csv": "id\n2\n", "/b.csv": "id\n3\n"},
+		"main":     main,
+	}
+	respond := func(w http.ResponseWriter, data any) {
+		json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint := strings.TrimPrefix(r.URL.Path, "/v1/repositories/test")
+		switch {
+		case endpoint == "/branches":
+			respond(w, []map[string]any{{"name": "main", "default": true}})
+		case endpoint == "/tags":
+			respond(w, []any{})
+		case endpoint == "/commits/bbbb2222":
+			respond(w, map[string]any{"hash": "bbbb2222", "previous_hash": "aaaa1111", "message": "Update ids", "timestamp": "2026-01-02 00:00:00"})
+		case endpoint == "/compare":
+			if r.URL.Query().Get("base_ref") != "aaaa1111" || r.URL.Query().Get("compare_ref") != "bbbb2222" {
+				t.Errorf("unexpected comparison %s", r.URL.RawQuery)
+			}
+			respond(w, map[string]any{"items": []any{
+				map[string]any{"type": "changed", "object": map[string]any{"name": "a.csv", "path": "/a.csv", "type": objectType}},
+				map[string]any{"type": "added", "object": map[string]any{"name": "b.csv", "path": "/b.csv", "type": objectType}},
+			}})
+		case endpoint == "/commits" && r.Method == http.MethodPost:
+			*writes = append(*writes, "commit "+r.FormValue("message"))
+			respond(w, nil)
+		case strings.HasPrefix(endpoint, "/objects/schema/"):
+			http.Error(w, "schema unavailable", http.StatusInternalServerError)
+		case strings.HasPrefix(endpoint, "/objects/content/"):
+			content, ok := refs[r.URL.Query().Get("ref")]["/"+strings.TrimPrefix(endpoint, "/objects/content/")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(content))
+		case strings.HasPrefix(endpoint, "/objects/") && r.Method == http.MethodPost:
+			objectPath := "/" + strings.TrimPrefix(endpoint, "/objects/")
+			if r.FormValue("_method") == "DELETE" {
+				*writes = append(*writes, "delete "+objectPath)
+			} else {
+				*writes = append(*writes, "upload "+objectPath)
+			}
+			respond(w, map[string]any{"name": strings.TrimPrefix(objectPath, "/"), "path": objectPath, "type": objectType})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestCommitApply(t *testing.T) {
+	tests := []struct {
+		name       string
+		revert     bool
+		objectType models.ObjectType
+		// Objects on the main branch, by path
+		main map[string]string
+		// Expected changes as "<status> <type> <path>"
+		expectedChanges []string
+		// Expected uploads, deletes and commits in order
+		expectedWrites    []string
+		expectedConflicts bool
+		expectedErr       bool
+	}{
+		{
+			name:       "cherry-pick",
+			objectType: models.ObjectTypeBinary,
+			main:       map[string]string{"/a.csv": "id\n1\n"},
+			expectedChanges: []string{
+				"applied changed /a.csv",
+				"applied added /b.csv",
+			},
+			expectedWrites: []string{
+				"upload /a.csv",
+				"upload /b.csv",
+				"commit Update ids\n\n(cherry picked from commit bbbb2222)",
+			},
+		},
+		{
+			name:       "revert",
+			revert:     true,
+			objectType: models.ObjectTypeBinary,
+			main:       map[string]string{"/a.csv": "id\n2\n", "/b.csv": "id\n3\n"},
+			expectedChanges: []string{
+				"applied changed /a.csv",
+				"applied removed /b.csv",
+			},
+			expectedWrites: []string{
+				"upload /a.csv",
+				"delete /b.csv",
+				"commit Revert \"Update ids\"\n\nThis reverts commit bbbb2222.",
+			},
+		},
+		{
+			// Nothing is committed if the branch already has every change
+			name:       "skip",
+			objectType: models.ObjectTypeBinary,
+			main:       map[string]string{"/a.csv": "id\n2\n", "/b.csv": "id\n3\n"},
+			expectedChanges: []string{
+				"skipped changed /a.csv",
+				"skipped added /b.csv",
+			},
+		},
+		{
+			// Nothing is applied if any change conflicts
+			name:       "conflict",
+			objectType: models.ObjectTypeBinary,
+			main:       map[string]string{"/a.csv": "id\n4\n"},
+			expectedChanges: []string{
+				"conflict changed /a.csv",
+				"applied added /b.csv",
+			},
+			expectedConflicts: true,
+		},
+		{
+			// A failure merging the rows of a structured object is an error rather than a conflict
+			name:        "merge error",
+			objectType:  models.ObjectTypeStructured,
+			main:        map[string]string{"/a.csv": "id\n4\n"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var writes []string
+			server := commitApplyServer(t, tt.objectType, tt.main, &writes)
+			defer server.Close()
+
+			applier := NewCommitApplier(client.NewClient(server.URL, "token", "en"))
+			apply := applier.CherryPick
+			if tt.revert {
+				apply = applier.RevertCommit
+			}
+			result, err := apply(context.Background(), "test", "bbbb2222", "main")
+			switch {
+			case tt.expectedErr:
+				if err == nil || errors.Is(err, ErrUnresolvedConflicts) {
+					t.Fatalf("got result %+v and error %v, expected an error", result, err)
+				}
+				return
+			case tt.expectedConflicts:
+				if !errors.Is(err, ErrUnresolvedConflicts) {
+					t.Fatalf("got error %v, expected unresolved conflicts", err)
+				}
+			case err != nil:
+				t.Fatalf("applying: %v", err)
+			}
+
+			var changes []string
+			for _, change := range result.Changes {
+				changes = append(changes, string(change.Status)+" "+string(change.Type)+" "+change.Path)
+			}
+			if !slices.Equal(changes, tt.expectedChanges) {
+				t.Errorf("got changes %v, expected %v", changes, tt.expectedChanges)
+			}
+			if !slices.Equal(writes, tt.expectedWrites) {
+				t.Errorf("got writes %q, expected %q", writes, tt.expectedWrites)
+			}
+		})
+	}
+}
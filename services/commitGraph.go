@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// CommitGraph walks the commit history of a repository. Commits are fetched as they are needed and cached,
+// as are the heads of refs, so a CommitGraph is a snapshot: create a new one to see later commits.
+// It is safe for concurrent use.
+type CommitGraph struct {
+	client     *client.Client
+	repository string
+
+	mu      sync.Mutex
+	commits map[string]*models.CommitNode
+	heads   map[string]string
+}
+
+// NewCommitGraph creates a new CommitGraph for a repository
+func NewCommitGraph(client *client.Client, repository string) *CommitGraph {
+	return &CommitGraph{
+		client:     client,
+		repository: repository,
+		commits:    make(map[string]*models.CommitNode),
+		heads:      make(map[string]string),
+	}
+}
+
+// lookup returns a lookup of commits, fetching the commits missing from the cache
+func (g *CommitGraph) lookup(ctx context.Context) utils.CommitLookup {
+	commitService := NewCommitService(g.client.WithContext(ctx))
+	return func(hash string) (*models.CommitNode, error) {
+		g.mu.Lock()
+		commit, ok := g.commits[hash]
+		g.mu.Unlock()
+		if ok {
+			return commit, nil
+		}
+
+		fetched, _, err := commitService.FetchCommit(g.repository, hash)
+		if err != nil {
+			return nil, err
+		}
+		node := utils.ParseCommit(*fetched)
+		g.mu.Lock()
+		g.commits[node.Hash] = &node
+		g.mu.Unlock()
+		return &node, nil
+	}
+}
+
+// Head returns the latest commit of a branch, tag or commit hash.
+// The history of the ref is fetched at once and cached, so walking it needs no further requests.
+func (g *CommitGraph) Head(ctx context.Context, ref string) (*models.CommitNode, error) {
+	g.mu.Lock()
+	hash, ok := g.heads[ref]
+	g.mu.Unlock()
+	if ok {
+		return g.lookup(ctx)(hash)
+	}
+
+	commits, _, err := NewCommitService(g.client.WithContext(ctx)).FetchCommits(g.repository, ref)
+	if err != nil || len(commits) == 0 {
+		// The ref may be a commit hash the commit list does not accept
+		commit, lookupErr := g.lookup(ctx)(ref)
+		if lookupErr != nil {
+			if err == nil {
+				err = fmt.Errorf("ref %s has no commits", ref)
+			}
+			return nil, err
+		}
+		g.mu.Lock()
+		g.heads[ref] = commit.Hash
+		g.mu.Unlock()
+		return commit, nil
+	}
+
+	// The head is the commit no other commit of the ref follows
+	followed := make(map[string]bool, len(commits))
+	for _, commit := range commits {
+		if commit.PreviousHash != nil {
+			followed[*commit.PreviousHash] = true
+		}
+	}
+	var head *models.CommitNode
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, commit := range commits {
+		if _, ok := g.commits[commit.Hash]; !ok {
+			node := utils.ParseCommit(commit)
+			g.commits[commit.Hash] = &node
+		}
+		if head == nil && !followed[commit.Hash] {
+			head = g.commits[commit.Hash]
+		}
+	}
+	if head == nil {
+		return nil, fmt.Errorf("commit history of %s has a cycle", ref)
+	}
+	g.heads[ref] = head.Hash
+	return head, nil
+}
+
+// Ancestors returns an iterator over the latest commit of a ref and its ancestors, newest first.
+// Iteration stops after the first commit or at the first error
+func (g *CommitGraph) Ancestors(ctx context.Context, ref string) iter.Seq2[models.CommitNode, error] {
+	return func(yield func(models.CommitNode, error) bool) {
+		head, err := g.Head(ctx, ref)
+		if err != nil {
+			yield(models.CommitNode{}, err)
+			return
+		}
+		for commit, err := range utils.CommitAncestors(head.Hash, g.lookup(ctx)) {
+			if !yield(commit, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// MergeBase returns the latest commit in the history of both refs, or nil if their histories are unrelated
+func (g *CommitGraph) MergeBase(ctx context.Context, baseRef, compareRef string) (*models.CommitNode, error) {
+	base, compare, err := g.refHeads(ctx, baseRef, compareRef)
+	if err != nil {
+		return nil, err
+	}
+	return utils.MergeBase(base, compare, g.lookup(ctx))
+}
+
+// Log returns the commits of compareRef that are not in baseRef, newest first, like `git log base..compare`,
+// that pass the filter. An empty baseRef returns the whole history of compareRef.
+func (g *CommitGraph) Log(ctx context.Context, baseRef, compareRef string, filter models.CommitFilter) ([]models.CommitNode, error) {
+	compare, err := g.Head(ctx, compareRef)
+	if err != nil {
+		return nil, err
+	}
+	base := ""
+	if baseRef != "" {
+		head, err := g.Head(ctx, baseRef)
+		if err != nil {
+			return nil, err
+		}
+		base = head.Hash
+	}
+
+	commits, err := utils.CommitsBetween(base, compare.Hash, g.lookup(ctx))
+	if err != nil {
+		return nil, err
+	}
+	filtered := []models.CommitNode{}
+	for _, commit := range commits {
+		if filter.Matches(commit) {
+			filtered = append(filtered, commit)
+		}
+	}
+	return filtered, nil
+}
+
+// IsFastForward reports whether baseRef can be fast-forwarded to compareRef,
+// i.e. whether the latest commit of baseRef is in the history of compareRef
+func (g *CommitGraph) IsFastForward(ctx context.Context, baseRef, compareRef string) (bool, error) {
+	base, compare, err := g.refHeads(ctx, baseRef, compareRef)
+	if err != nil {
+		return false, err
+	}
+	return utils.IsAncestor(base, compare, g.lookup(ctx))
+}
+
+// refHeads returns the hashes of the latest commits of two refs
+func (g *CommitGraph) refHeads(ctx context.Context, baseRef, compareRef string) (string, string, error) {
+	base, err := g.Head(ctx, baseRef)
+	if err != nil {
+		return "", "", err
+	}
+	compare, err := g.Head(ctx, compareRef)
+	if err != nil {
+		return "", "", err
+	}
+	return base.Hash, compare.Hash, nil
+}
+
+// cachedCommitsWithPrefix returns the hashes of the cached commits starting with a prefix
+func (g *CommitGraph) cachedCommitsWithPrefix(prefix string) []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var hashes []string
+	for hash := range g.commits {
+		if strings.HasPrefix(hash, prefix) {
+			hashes = append(hashes, hash)
+		}
+	}
+	slices.Sort(hashes)
+	return hashes
+}
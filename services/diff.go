@@ -1,11 +1,13 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
 	"github.com/IrminData/irmin-sdk-go/client"
 	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
 )
 
 // DiffService provides methods to compare and merge refs
@@ -55,3 +57,72 @@ func (s *DiffService) MergeRefs(repository, baseRef, compareRef, description, st
 	}
 	return apiResp, nil
 }
+
+// RowDiffOptions configures how the rows of structured objects are aligned
+type RowDiffOptions struct {
+	// Key columns rows are aligned by. Rows are aligned by position if empty or if a key column is missing
+	Key []string
+	// Key columns per object path, overriding Key
+	Keys map[string][]string
+}
+
+// CompareRows compares two refs and diffs the rows of every changed structured object.
+// Both versions of an object are fetched and decoded from JSON, NDJSON, CSV or Parquet;
+// objects that cannot be decoded are reported with an error instead of their changes.
+func (s *DiffService) CompareRows(ctx context.Context, repository, baseRef, compareRef string, opts RowDiffOptions) (*models.RowDiff, error) {
+	apiClient := s.client.WithContext(ctx)
+	diff, _, err := NewDiffService(apiClient).CompareRefs(repository, baseRef, compareRef)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := NewObjectService(apiClient)
+	result := &models.RowDiff{
+		Repository: repository,
+		BaseRef:    baseRef,
+		CompareRef: compareRef,
+		Objects:    []models.ObjectRowDiff{},
+	}
+	for _, item := range diff.Items {
+		if item.Type != models.ChangeTypeChanged || item.Object.Type != models.ObjectTypeStructured {
+			continue
+		}
+		key, ok := opts.Keys[item.Object.Path]
+		if !ok {
+			key = opts.Key
+		}
+
+		format, ok := utils.TableFormatOf(item.Object)
+		if !ok {
+			result.Objects = append(result.Objects, models.ObjectRowDiff{
+				Path:  item.Object.Path,
+				Error: "unknown structured object format",
+			})
+			continue
+		}
+
+		var tables [2]*models.QueryResultTable
+		var decodeErr error
+		for i, ref := range []string{baseRef, compareRef} {
+			data, err := objects.FetchContent(repository, contentPath(item.Object.Path), ref, true)
+			if err != nil {
+				return nil, err
+			}
+			if tables[i], err = utils.DecodeTable(data, format); err != nil {
+				decodeErr = fmt.Errorf("%s: %w", ref, err)
+				break
+			}
+		}
+
+		var objectDiff models.ObjectRowDiff
+		if decodeErr != nil {
+			objectDiff.Error = decodeErr.Error()
+		} else {
+			objectDiff = utils.DiffTables(tables[0], tables[1], key)
+		}
+		objectDiff.Path = item.Object.Path
+		objectDiff.Format = string(format)
+		result.Objects = append(result.Objects, objectDiff)
+	}
+	return result, nil
+}
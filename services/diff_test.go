@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+)
+
+func TestCompareRowsNormalisesObjectPaths(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "leading slash", path: "/lakes/cities.csv"},
+		{name: "relative", path: "lakes/cities.csv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fetched []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, "/compare") {
+					json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+						"items": []any{map[string]any{
+							"type":   "changed",
+							"object": map[string]any{"name": "cities.csv", "path": tt.path, "type": "structured"},
+						}},
+					}})
+					return
+				}
+				fetched = append(fetched, r.URL.Path)
+				if r.URL.Query().Get("ref") == "main" {
+					w.Write([]byte("id,name\n1,Oslo\n"))
+					return
+				}
+				w.Write([]byte("id,name\n1,Oslo\n2,Bergen\n"))
+			}))
+			defer server.Close()
+
+			diff, err := NewDiffService(client.NewClient(server.URL, "token", "en")).CompareRows(context.Background(), "test", "main", "feature", RowDiffOptions{Key: []string{"id"}})
+			if err != nil {
+				t.Fatalf("comparing rows: %v", err)
+			}
+			for _, p := range fetched {
+				if p != "/v1/repositories/test/objects/content/lakes/cities.csv" {
+					t.Errorf("fetched content from %s", p)
+				}
+			}
+			if len(diff.Objects) != 1 || diff.Objects[0].Added != 1 {
+				t.Errorf("got %+v, expected one object with an added row", diff.Objects)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// HistoryService walks the history of objects
+type HistoryService struct {
+	client *client.Client
+}
+
+// NewHistoryService creates a new HistoryService
+func NewHistoryService(client *client.Client) *HistoryService {
+	return &HistoryService{
+		client: client,
+	}
+}
+
+// sameObjectPath compares object paths with or without a leading slash
+func sameObjectPath(a, b string) bool {
+	return contentPath(a) == contentPath(b)
+}
+
+// ObjectHistory returns every commit on a branch that changed an object, newest first. Every commit is
+// compared with its previous commit, and the history follows the object across moves: a moved object,
+// or an added object with the content of an object removed in the same commit, continues the history of
+// the object it was moved from. The history ends at the commit that added the object, or at a move
+// whose source cannot be determined.
+func (s *HistoryService) ObjectHistory(ctx context.Context, repository, branch, objectPath string) (*models.ObjectHistory, error) {
+	apiClient := s.client.WithContext(ctx)
+	diffService := NewDiffService(apiClient)
+	objectService := NewObjectService(apiClient)
+
+	history := &models.ObjectHistory{
+		Repository: repository,
+		Branch:     branch,
+		Path:       objectPath,
+		Entries:    []models.ObjectHistoryEntry{},
+	}
+	current := objectPath
+	for commit, err := range NewCommitGraph(s.client, repository).Ancestors(ctx, branch) {
+		if err != nil {
+			return nil, err
+		}
+		if commit.PreviousHash == nil {
+			// The first commit adds every object it contains
+			if _, _, err := objectService.FetchObject(repository, current, commit.Hash); err == nil {
+				history.Entries = append(history.Entries, models.ObjectHistoryEntry{Commit: commit, Type: models.ChangeTypeAdded, Path: current})
+			}
+			break
+		}
+
+		diff, _, err := diffService.CompareRefs(repository, *commit.PreviousHash, commit.Hash)
+		if err != nil {
+			return nil, err
+		}
+		var item *models.ChangeItem
+		for i := range diff.Items {
+			if sameObjectPath(diff.Items[i].Object.Path, current) && diff.Items[i].Type != models.ChangeTypeRemoved {
+				item = &diff.Items[i]
+				break
+			}
+		}
+		if item == nil {
+			continue
+		}
+
+		entry := models.ObjectHistoryEntry{Commit: commit, Type: item.Type, Path: current}
+		if item.Type != models.ChangeTypeAdded && item.Type != models.ChangeTypeMoved {
+			history.Entries = append(history.Entries, entry)
+			continue
+		}
+		source := moveSource(objectService, repository, diff.Items, *item, commit)
+		if source != "" {
+			entry.Type = models.ChangeTypeMoved
+			entry.PreviousPath = source
+		}
+		history.Entries = append(history.Entries, entry)
+		if source == "" {
+			break
+		}
+		current = source
+	}
+	return history, nil
+}
+
+// moveSource returns the path an added or moved object was moved from in a commit: the only object removed
+// or moved away in the commit if the object is reported as moved, or else a removed or moved object with the
+// same content. It returns an empty path if there is no such object.
+func moveSource(objectService *ObjectService, repository string, items []models.ChangeItem, item models.ChangeItem, commit models.CommitNode) string {
+	var candidates []string
+	for _, other := range items {
+		if (other.Type == models.ChangeTypeRemoved || other.Type == models.ChangeTypeMoved) &&
+			!sameObjectPath(other.Object.Path, item.Object.Path) && other.Object.Type == item.Object.Type {
+			candidates = append(candidates, other.Object.Path)
+		}
+	}
+	if item.Type == models.ChangeTypeMoved && len(candidates) == 1 {
+		return candidates[0]
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	content, err := objectService.FetchContent(repository, contentPath(item.Object.Path), commit.Hash, true)
+	if err != nil {
+		return ""
+	}
+	for _, candidate := range candidates {
+		previous, err := objectService.FetchContent(repository, contentPath(candidate), *commit.PreviousHash, true)
+		if err == nil && bytes.Equal(previous, content) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// Blame attributes every row of a structured object on a branch to the commit of its history that last
+// added or modified it, following moves. Rows are aligned across versions by the key columns, or by position
+// if key is empty or a key column is missing from a version. Rows of an object whose history ends at a move
+// with an unknown source are attributed to the move.
+func (s *HistoryService) Blame(ctx context.Context, repository, branch, objectPath string, key []string) (*models.ObjectBlame, error) {
+	objectService := NewObjectService(s.client.WithContext(ctx))
+	object, _, err := objectService.FetchObject(repository, objectPath, branch)
+	if err != nil {
+		return nil, err
+	}
+	if object.Type != models.ObjectTypeStructured {
+		return nil, fmt.Errorf("object %s is not structured", objectPath)
+	}
+	format, ok := utils.TableFormatOf(*object)
+	if !ok {
+		return nil, fmt.Errorf("unknown format of structured object %s", objectPath)
+	}
+
+	history, err := s.ObjectHistory(ctx, repository, branch, objectPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(history.Entries) == 0 {
+		return nil, fmt.Errorf("object %s has no history on %s", objectPath, branch)
+	}
+
+	// The format of earlier versions follows their file extension, in case the object was moved to another one
+	fetch := func(objectPath, ref string) (*models.QueryResultTable, error) {
+		data, err := objectService.FetchContent(repository, contentPath(objectPath), ref, true)
+		if err != nil {
+			return nil, err
+		}
+		versionFormat, ok := utils.TableFormatOf(models.Object{Path: objectPath, Name: path.Base(objectPath)})
+		if !ok {
+			versionFormat = format
+		}
+		table, err := utils.DecodeTable(data, versionFormat)
+		if err != nil {
+			return nil, fmt.Errorf("decode %s at %s: %w", objectPath, ref, err)
+		}
+		return table, nil
+	}
+
+	versions := make([]*models.QueryResultTable, 0, len(history.Entries)+1)
+	for _, entry := range history.Entries {
+		table, err := fetch(entry.Path, entry.Commit.Hash)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, table)
+	}
+	oldest := history.Entries[len(history.Entries)-1]
+	before := &models.QueryResultTable{Columns: []string{}, Rows: [][]models.JSONValue{}}
+	if oldest.Type == models.ChangeTypeMoved && oldest.PreviousPath != "" {
+		if before, err = fetch(oldest.PreviousPath, *oldest.Commit.PreviousHash); err != nil {
+			return nil, err
+		}
+	} else if oldest.Type != models.ChangeTypeAdded && oldest.Type != models.ChangeTypeMoved && oldest.Commit.PreviousHash != nil {
+		if before, err = fetch(oldest.Path, *oldest.Commit.PreviousHash); err != nil {
+			return nil, err
+		}
+	}
+	versions = append(versions, before)
+
+	keys, origins, positional := utils.BlameRows(versions, key)
+	blame := &models.ObjectBlame{
+		Repository: repository,
+		Branch:     branch,
+		Path:       objectPath,
+		Format:     string(format),
+		KeyColumns: key,
+		Positional: positional,
+		Rows:       make([]models.RowBlame, len(keys)),
+	}
+	for i, rowKey := range keys {
+		blame.Rows[i] = models.RowBlame{Key: rowKey, Row: i}
+		if origins[i] >= 0 {
+			commit := history.Entries[origins[i]].Commit
+			blame.Rows[i].Commit = &commit
+		}
+	}
+	return blame, nil
+}
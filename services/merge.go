@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// ErrUnresolvedConflicts is returned when row merges leave conflicting or invalid values, in which case the refs are not merged
+var ErrUnresolvedConflicts = errors.New("unresolved merge conflicts")
+
+// MergeResolver decides how a conflicting object is resolved
+type MergeResolver func(ctx context.Context, conflict models.MergeConflict) (models.MergeResolution, error)
+
+// MergeOptions configures a merge
+type MergeOptions struct {
+	// Description of the merge
+	Description string
+	// Name of the temporary branch resolutions are committed to. Defaults to `merge-<compare ref>-<unix time>`
+	TempBranch string
+	// Keep the temporary branch after merging, e.g. to inspect the resolutions
+	KeepTempBranch bool
+	// Message of the resolution commit. Defaults to `Resolve conflicts merging <compare ref> into <base ref>`
+	CommitMessage string
+}
+
+// Merger merges refs, resolving conflicting objects with a callback
+type Merger struct {
+	client *client.Client
+}
+
+// NewMerger creates a new Merger
+func NewMerger(client *client.Client) *Merger {
+	return &Merger{
+		client: client,
+	}
+}
+
+var branchNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// Merge merges compareRef into baseRef. If the refs have no conflicting objects they are merged directly.
+// Otherwise every conflict is passed to the resolver, in path order, and the resolutions are committed to
+// a temporary branch created from compareRef, with the changes of baseRef merged in, which is then merged
+// into baseRef. Row resolutions merge the versions of a structured object against their common ancestor with
+// an ObjectMerger; if values remain in conflict or do not conform to the object's schema, the refs are not
+// merged and ErrUnresolvedConflicts is returned with the result.
+func (m *Merger) Merge(ctx context.Context, repository, baseRef, compareRef string, resolver MergeResolver, opts MergeOptions) (*models.MergeResult, error) {
+	apiClient := m.client.WithContext(ctx)
+	diffService := NewDiffService(apiClient)
+	objectService := NewObjectService(apiClient)
+	branchService := NewBranchService(apiClient)
+
+	diff, _, err := diffService.CompareRefs(repository, baseRef, compareRef)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.MergeResult{
+		Repository:  repository,
+		BaseRef:     baseRef,
+		CompareRef:  compareRef,
+		Changes:     diff.Items,
+		Resolutions: []models.MergeObjectResolution{},
+	}
+
+	var conflicts []models.ChangeItem
+	for _, item := range diff.Items {
+		if item.Type == models.ChangeTypeConflict {
+			conflicts = append(conflicts, item)
+		}
+	}
+	if len(conflicts) == 0 {
+		apiResp, err := diffService.MergeRefs(repository, baseRef, compareRef, opts.Description, string(models.MergeStrategyDefault))
+		if err != nil {
+			return result, err
+		}
+		result.Merged = true
+		if apiResp.Message != nil {
+			result.Message = *apiResp.Message
+		}
+		return result, nil
+	}
+	if resolver == nil {
+		return result, fmt.Errorf("%w: %d conflicting object(s) and no resolver", ErrUnresolvedConflicts, len(conflicts))
+	}
+	slices.SortFunc(conflicts, func(a, b models.ChangeItem) int { return strings.Compare(a.Object.Path, b.Object.Path) })
+
+	tempBranch := opts.TempBranch
+	if tempBranch == "" {
+		tempBranch = fmt.Sprintf("merge-%s-%d", strings.Trim(branchNameUnsafe.ReplaceAllString(compareRef, "-"), "-"), time.Now().Unix())
+	}
+	if _, err := branchService.CreateBranch(repository, tempBranch, compareRef); err != nil {
+		return result, err
+	}
+	result.ResolutionBranch = tempBranch
+	cleanup := func() {
+		if !opts.KeepTempBranch {
+			branchService.DeleteBranch(repository, tempBranch)
+			result.ResolutionBranch = ""
+		}
+	}
+
+	// Bring the changes of the base ref onto the temporary branch, keeping the compare version of conflicts
+	if _, err := diffService.MergeRefs(repository, tempBranch, baseRef, opts.Description, string(models.MergeStrategyDestWins)); err != nil {
+		cleanup()
+		return result, err
+	}
+
+	var ancestor *string
+	unresolved := 0
+	for _, item := range conflicts {
+		conflict := models.MergeConflict{Item: item}
+		// Objects absent from a ref, e.g. deleted in it, have no content. Other errors must not be taken
+		// for absence, as resolving to missing content deletes the object.
+		var err error
+		if conflict.BaseContent, err = fetchExistingContent(objectService, repository, item.Object.Path, baseRef); err != nil {
+			cleanup()
+			return result, fmt.Errorf("fetch %s at %s: %w", item.Object.Path, baseRef, err)
+		}
+		if conflict.CompareContent, err = fetchExistingContent(objectService, repository, item.Object.Path, compareRef); err != nil {
+			cleanup()
+			return result, fmt.Errorf("fetch %s at %s: %w", item.Object.Path, compareRef, err)
+		}
+
+		resolution, err := resolver(ctx, conflict)
+		if err != nil {
+			cleanup()
+			return result, fmt.Errorf("resolve %s: %w", item.Object.Path, err)
+		}
+		objectResolution := models.MergeObjectResolution{Path: item.Object.Path, Kind: resolution.Kind}
+
+		var content []byte
+		switch resolution.Kind {
+		case models.MergeResolutionBase:
+			content = conflict.BaseContent
+		case models.MergeResolutionCompare:
+			content = conflict.CompareContent
+		case models.MergeResolutionCustom:
+			content = resolution.Content
+		case models.MergeResolutionDelete:
+		case models.MergeResolutionRows:
+			if ancestor == nil {
+				base, err := NewCommitGraph(apiClient, repository).MergeBase(ctx, baseRef, compareRef)
+				if err != nil {
+					cleanup()
+					return result, err
+				}
+				hash := ""
+				if base != nil {
+					hash = base.Hash
+				}
+				ancestor = &hash
+			}
+			var objectMerge *models.ObjectMerge
+			content, objectMerge, err = NewObjectMerger(apiClient).MergeObject(ctx, repository, item.Object.Path, *ancestor, baseRef, compareRef, ObjectMergeOptions{
+				Key:    resolution.Key,
+				Policy: resolution.Policy,
+			})
+			if err != nil {
+				cleanup()
+				return result, fmt.Errorf("merge rows of %s: %w", item.Object.Path, err)
+			}
+			objectResolution.Conflicts = objectMerge.Conflicts
+			objectResolution.Violations = objectMerge.Violations
+			if !objectMerge.Resolved() {
+				unresolved++
+			}
+		default:
+			cleanup()
+			return result, fmt.Errorf("resolve %s: unknown resolution %q", item.Object.Path, resolution.Kind)
+		}
+		result.Resolutions = append(result.Resolutions, objectResolution)
+
+		name := item.Object.Name
+		if name == "" {
+			name = path.Base(item.Object.Path)
+		}
+		if content == nil {
+			_, err = objectService.DeleteObject(repository, tempBranch, item.Object.Path, name)
+		} else {
+			_, _, err = objectService.UploadObject(repository, tempBranch, item.Object.Path, name, map[string][]byte{name: content})
+		}
+		if err != nil {
+			cleanup()
+			return result, err
+		}
+	}
+	if unresolved > 0 {
+		cleanup()
+		return result, fmt.Errorf("%w: rows of %d object(s)", ErrUnresolvedConflicts, unresolved)
+	}
+
+	message := opts.CommitMessage
+	if message == "" {
+		message = fmt.Sprintf("Resolve conflicts merging %s into %s", compareRef, baseRef)
+	}
+	if _, err := NewCommitService(apiClient).CreateCommit(repository, tempBranch, message); err != nil {
+		cleanup()
+		return result, err
+	}
+	apiResp, err := diffService.MergeRefs(repository, baseRef, tempBranch, opts.Description, string(models.MergeStrategySourceWins))
+	if err != nil {
+		cleanup()
+		return result, err
+	}
+	result.Merged = true
+	if apiResp.Message != nil {
+		result.Message = *apiResp.Message
+	}
+	cleanup()
+	return result, nil
+}
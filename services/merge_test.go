@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+func TestMergeTreatsOnlyMissingContentAsAbsent(t *testing.T) {
+	tests := []struct {
+		name string
+		// Status of the response to fetching the base version of the conflicting object
+		baseStatus int
+		expected   []string
+		wantError  bool
+	}{
+		{
+			name:       "deleted in base",
+			baseStatus: http.StatusNotFound,
+			expected:   []string{"POST /branches", "DELETE /objects/lakes.csv", "POST /commits", "DELETE /branches/resolve"},
+		},
+		{
+			name:       "unavailable",
+			baseStatus: http.StatusServiceUnavailable,
+			expected:   []string{"POST /branches", "DELETE /branches/resolve"},
+			wantError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				endpoint := strings.TrimPrefix(r.URL.Path, "/v1/repositories/test")
+				switch {
+				case endpoint == "/compare":
+					json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"items": []any{map[string]any{
+						"type":   "conflict",
+						"object": map[string]any{"name": "lakes.csv", "path": "/lakes.csv", "type": "structured"},
+					}}}})
+					return
+				case strings.HasPrefix(endpoint, "/objects/content/"):
+					if r.URL.Query().Get("ref") == "main" {
+						http.Error(w, "base", tt.baseStatus)
+						return
+					}
+					w.Write([]byte("id,name\n1,Oslo\n"))
+					return
+				}
+				method := r.Method
+				if override := r.FormValue("_method"); override != "" {
+					method = override
+				}
+				if !strings.HasSuffix(endpoint, "/merge") {
+					calls = append(calls, method+" "+endpoint)
+				}
+				json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{}})
+			}))
+			defer server.Close()
+
+			resolver := func(ctx context.Context, conflict models.MergeConflict) (models.MergeResolution, error) {
+				return models.MergeResolution{Kind: models.MergeResolutionBase}, nil
+			}
+			merger := NewMerger(client.NewClient(server.URL, "token", "en"))
+			_, err := merger.Merge(context.Background(), "test", "main", "feature", resolver, MergeOptions{TempBranch: "resolve"})
+			if (err != nil) != tt.wantError {
+				t.Fatalf("got error %v, expected error %t", err, tt.wantError)
+			}
+			if !slices.Equal(calls, tt.expected) {
+				t.Errorf("got calls %q, expected %q", calls, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+)
+
+// contentPath returns an object path as FetchContent expects it, without a leading slash
+func contentPath(objectPath string) string {
+	return strings.TrimLeft(objectPath, "/")
+}
+
+// fetchExistingContent fetches the content of an object at a ref, or nil if it does not exist there
+func fetchExistingContent(objectService *ObjectService, repository, objectPath, ref string) ([]byte, error) {
+	content, err := objectService.FetchContent(repository, contentPath(objectPath), ref, true)
+	if client.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if content == nil {
+		content = []byte{}
+	}
+	return content, nil
+}
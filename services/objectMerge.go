@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// ObjectMergeOptions configures a three-way merge of a structured object
+type ObjectMergeOptions struct {
+	// Key columns rows are aligned by. Rows are aligned by position if empty or if a key column is missing
+	Key []string
+	// How values changed differently in both versions are merged. Defaults to models.TableConflictReport
+	Policy models.TableConflictPolicy
+	// Format of the object. Defaults to the content type of its schema, or else its file extension
+	Format utils.TableFormat
+	// Branch to upload the merged object to if it is resolved. The merged object is only returned if empty
+	UploadBranch string
+}
+
+// ObjectMerger merges the versions of structured objects
+type ObjectMerger struct {
+	client *client.Client
+}
+
+// NewObjectMerger creates a new ObjectMerger
+func NewObjectMerger(client *client.Client) *ObjectMerger {
+	return &ObjectMerger{
+		client: client,
+	}
+}
+
+// MergeObject merges the changes the baseRef and compareRef versions of a structured object made to its
+// ancestorRef version, and returns the encoded merged object with a summary of the merge. The object is
+// treated as empty in a ref it is absent from, including an empty ancestorRef. The object's schema in
+// baseRef, or else compareRef, determines its format, the types of merged values and the Parquet schema
+// it is encoded with; values that do not conform to it are reported as violations.
+// If opts.UploadBranch is set, the merged object is uploaded to it only if the merge is resolved.
+func (m *ObjectMerger) MergeObject(ctx context.Context, repository, objectPath, ancestorRef, baseRef, compareRef string, opts ObjectMergeOptions) ([]byte, *models.ObjectMerge, error) {
+	apiClient := m.client.WithContext(ctx)
+	objectService := NewObjectService(apiClient)
+
+	var schema *models.ObjectSchema
+	for _, ref := range []string{baseRef, compareRef} {
+		s, _, err := objectService.FetchObjectSchema(repository, contentPath(objectPath), ref)
+		if err == nil {
+			schema = s
+			break
+		}
+		if !client.IsNotFound(err) {
+			return nil, nil, err
+		}
+	}
+	if schema == nil {
+		return nil, nil, fmt.Errorf("object %s not found in %s or %s", objectPath, baseRef, compareRef)
+	}
+	if schema.Structured == nil {
+		return nil, nil, fmt.Errorf("object %s is not structured", objectPath)
+	}
+
+	format := opts.Format
+	if format == "" {
+		var ok bool
+		format, ok = utils.TableFormatOf(models.Object{Name: schema.Name, Path: objectPath, ContentType: schema.Structured.ContentType})
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown format of structured object %s", objectPath)
+		}
+	}
+	policy := opts.Policy
+	if policy == "" {
+		policy = models.TableConflictReport
+	}
+
+	var tables [3]*models.QueryResultTable
+	for i, ref := range []string{ancestorRef, baseRef, compareRef} {
+		var data []byte
+		if ref != "" {
+			// Objects absent from a ref have no content
+			var err error
+			if data, err = fetchExistingContent(objectService, repository, objectPath, ref); err != nil {
+				return nil, nil, fmt.Errorf("fetch %s at %s: %w", objectPath, ref, err)
+			}
+		}
+		if data == nil {
+			tables[i] = &models.QueryResultTable{Columns: []string{}, Rows: [][]models.JSONValue{}}
+			continue
+		}
+		table, err := utils.DecodeTable(data, format)
+		if err != nil {
+			return nil, nil, fmt.Errorf("decode %s at %s: %w", objectPath, ref, err)
+		}
+		tables[i] = table
+	}
+
+	merged, conflicts := utils.MergeTables(tables[0], tables[1], tables[2], opts.Key, policy)
+	result := &models.ObjectMerge{
+		Path:        objectPath,
+		Format:      string(format),
+		AncestorRef: ancestorRef,
+		BaseRef:     baseRef,
+		CompareRef:  compareRef,
+		KeyColumns:  opts.Key,
+		Policy:      policy,
+		Columns:     merged.Columns,
+		Rows:        len(merged.Rows),
+		Conflicts:   conflicts,
+		Violations:  utils.ConformTable(merged, schema.Structured.Schema),
+	}
+	if result.Conflicts == nil {
+		result.Conflicts = []models.TableMergeConflict{}
+	}
+	if result.Violations == nil {
+		result.Violations = []string{}
+	}
+
+	var parquetSchema map[string]interface{}
+	if format == utils.TableFormatParquet {
+		var err error
+		if parquetSchema, err = utils.StructuredSchemaMap(schema.Structured.Schema); err != nil {
+			return nil, result, err
+		}
+	}
+	content, err := utils.EncodeTable(merged, format, parquetSchema)
+	if err != nil {
+		return nil, result, fmt.Errorf("encode %s: %w", objectPath, err)
+	}
+
+	if opts.UploadBranch != "" && result.Resolved() {
+		name := schema.Name
+		if name == "" {
+			name = path.Base(objectPath)
+		}
+		if _, _, err := objectService.UploadObject(repository, opts.UploadBranch, objectPath, name, map[string][]byte{name: content}); err != nil {
+			return content, result, err
+		}
+		result.UploadedTo = opts.UploadBranch
+	}
+	return content, result, nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+)
+
+func TestMergeObjectTreatsOnlyMissingContentAsAbsent(t *testing.T) {
+	tests := []struct {
+		name string
+		// Status of the response to fetching the ancestor version of the object
+		ancestorStatus int
+		rows           int
+		wantError      bool
+	}{
+		// Both versions added the same row to an object the ancestor did not have
+		{name: "absent from ancestor", ancestorStatus: http.StatusNotFound, rows: 1},
+		{name: "unavailable", ancestorStatus: http.StatusServiceUnavailable, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/v1/repositories/test/objects/schema/lakes.csv":
+					json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+						"name": "lakes.csv", "path": "/lakes.csv", "type": "structured",
+						"structured": map[string]any{"type": "structured", "schema": map[string]any{}},
+					}})
+				case r.URL.Path != "/v1/repositories/test/objects/content/lakes.csv":
+					http.NotFound(w, r)
+				case r.URL.Query().Get("ref") == "ancestor":
+					http.Error(w, "ancestor", tt.ancestorStatus)
+				default:
+					w.Write([]byte("id,name\n1,Oslo\n"))
+				}
+			}))
+			defer server.Close()
+
+			merger := NewObjectMerger(client.NewClient(server.URL, "token", "en"))
+			_, merge, err := merger.MergeObject(context.Background(), "test", "/lakes.csv", "ancestor", "main", "feature", ObjectMergeOptions{Key: []string{"id"}})
+			if (err != nil) != tt.wantError {
+				t.Fatalf("got error %v, expected error %t", err, tt.wantError)
+			}
+			if tt.wantError {
+				if !strings.Contains(err.Error(), "ancestor") {
+					t.Errorf("got error %v, expected it to name the ref", err)
+				}
+				return
+			}
+			if merge.Rows != tt.rows || !merge.Resolved() {
+				t.Errorf("got %d rows, resolved %t, expected %d resolved rows", merge.Rows, merge.Resolved(), tt.rows)
+			}
+		})
+	}
+}
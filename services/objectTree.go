@@ -0,0 +1,75 @@
+package services
+
+import (
+	"path"
+	"strings"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// treePath returns the full path of an object with a leading slash, whether its path includes its name or not
+func treePath(object models.Object) string {
+	p := "/" + strings.Trim(object.Path, "/")
+	if object.Name != "" && path.Base(p) != object.Name {
+		p = path.Join(p, object.Name)
+	}
+	return p
+}
+
+// objectTree lists the objects and groups at or under a path at a ref, keyed by their full path with a
+// leading slash. Groups include the group at the path itself. Both are empty if the path does not exist.
+func objectTree(objectService *ObjectService, repository, objectPath, ref string) (map[string]models.Object, map[string]models.Object, error) {
+	objects := make(map[string]models.Object)
+	groups := make(map[string]models.Object)
+	root := "/" + strings.Trim(objectPath, "/")
+
+	// The parent group tells whether the path exists and is a group
+	if root != "/" {
+		siblings, _, err := objectService.FetchObjects(repository, path.Dir(root), ref)
+		if client.IsNotFound(err) {
+			return objects, groups, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		found := false
+		for _, sibling := range siblings {
+			if treePath(sibling) != root {
+				continue
+			}
+			found = true
+			if sibling.Type != models.ObjectTypeGroup {
+				objects[root] = sibling
+				return objects, groups, nil
+			}
+			groups[root] = sibling
+		}
+		if !found {
+			return objects, groups, nil
+		}
+	}
+
+	queue := []string{root}
+	for len(queue) > 0 {
+		dir := queue[0]
+		queue = queue[1:]
+		children, _, err := objectService.FetchObjects(repository, dir, ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, child := range children {
+			p := treePath(child)
+			if child.Type == models.ObjectTypeGroup {
+				if _, seen := groups[p]; seen || p == dir {
+					continue
+				}
+				groups[p] = child
+				queue = append(queue, p)
+			} else {
+				objects[p] = child
+			}
+		}
+	}
+	return objects, groups, nil
+}
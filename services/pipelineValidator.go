@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// PipelineValidationError is returned by PipelineValidator.Validate when a pipeline definition has problems
+type PipelineValidationError struct {
+	// Problems lists every problem found, in stage order
+	Problems []models.PipelineProblem
+}
+
+// Error implements the error interface
+func (e *PipelineValidationError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, fmt.Sprintf("invalid pipeline: %d problem(s)", len(e.Problems)))
+	for _, problem := range e.Problems {
+		lines = append(lines, "  "+problem.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PipelineValidator checks pipeline definitions against the workspace before they are created
+type PipelineValidator struct {
+	client *client.Client
+}
+
+// NewPipelineValidator creates a new PipelineValidator
+func NewPipelineValidator(client *client.Client) *PipelineValidator {
+	return &PipelineValidator{
+		client: client,
+	}
+}
+
+// Validate checks a pipeline definition as passed to CreatePipelineWorkflow. Besides the checks of
+// utils.ValidatePipelineDefinition, it verifies that the referenced connections, repositories, branches
+// and executables exist, that connectors support pulling or pushing as the stages require, and that
+// stages do not write to immutable repositories or branches.
+// All problems are reported at once as a *PipelineValidationError; other errors come from fetching the workspace.
+func (v *PipelineValidator) Validate(ctx context.Context, stages []models.PipelineStage, live bool, schedule *models.WorkflowSchedule) error {
+	problems := utils.ValidatePipelineDefinition(stages, live, schedule)
+	remote, err := v.checkReferences(ctx, stages)
+	if err != nil {
+		return err
+	}
+	problems = append(problems, remote...)
+	if len(problems) == 0 {
+		return nil
+	}
+	slices.SortStableFunc(problems, func(a, b models.PipelineProblem) int {
+		return a.Stage - b.Stage
+	})
+	return &PipelineValidationError{Problems: problems}
+}
+
+// checkReferences verifies the workspace objects referenced by the stages, fetching each kind of object only when needed
+func (v *PipelineValidator) checkReferences(ctx context.Context, stages []models.PipelineStage) ([]models.PipelineProblem, error) {
+	apiClient := v.client.WithContext(ctx)
+	var problems []models.PipelineProblem
+	add := func(stage int, format string, args ...interface{}) {
+		problems = append(problems, models.PipelineProblem{Stage: stage, Message: fmt.Sprintf(format, args...)})
+	}
+
+	var connections map[string]models.Connection
+	var repositories map[string]models.Repository
+	var executables map[string]bool
+	branches := make(map[string][]models.Branch)
+	capabilities := make(map[string][]models.ConnectorCapability)
+
+	for i, stage := range stages {
+		switch s := stage.(type) {
+		case *models.PipelineStageConnection:
+			if s.Connection.ID == "" || (!s.Read && !s.Write) {
+				continue
+			}
+			if connections == nil {
+				list, _, err := NewConnectionService(apiClient).FetchConnections()
+				if err != nil {
+					return nil, err
+				}
+				connections = make(map[string]models.Connection, len(list))
+				for _, connection := range list {
+					connections[connection.ID] = connection
+				}
+			}
+			connection, ok := connections[s.Connection.ID]
+			if !ok {
+				add(i, "connection %q does not exist", s.Connection.ID)
+				continue
+			}
+
+			connector := connection.Connector
+			supported, ok := capabilities[connector.ID]
+			if !ok {
+				supported = connector.Capabilities
+				if len(supported) == 0 && connector.ID != "" {
+					fetched, _, err := NewConnectorService(apiClient).FetchConnector(connector.ID)
+					if err != nil {
+						return nil, err
+					}
+					supported = fetched.Capabilities
+				}
+				capabilities[connector.ID] = supported
+			}
+			name := connector.Name
+			if name == "" {
+				name = connector.ID
+			}
+			if s.Write && !slices.Contains(supported, models.ConnectorCapabilityPushFullSync) && !slices.Contains(supported, models.ConnectorCapabilityPushPatchSync) {
+				add(i, "connector %q of connection %q does not support pushing data", name, s.Connection.ID)
+			}
+			if s.Read && !slices.Contains(supported, models.ConnectorCapabilityPullFullSync) && !slices.Contains(supported, models.ConnectorCapabilityPullPatchSync) {
+				add(i, "connector %q of connection %q does not support pulling data", name, s.Connection.ID)
+			}
+
+		case *models.PipelineStageRepository:
+			slug := s.Repository.Slug
+			if slug == "" {
+				continue
+			}
+			if repositories == nil {
+				list, _, err := NewRepositoryService(apiClient).FetchRepositories()
+				if err != nil {
+					return nil, err
+				}
+				repositories = make(map[string]models.Repository, len(list))
+				for _, repository := range list {
+					repositories[repository.Slug] = repository
+				}
+			}
+			repository, ok := repositories[slug]
+			if !ok {
+				add(i, "repository %q does not exist", slug)
+				continue
+			}
+			if s.Write && repository.IsImmutable {
+				add(i, "repository %q is immutable and cannot be written to", slug)
+			}
+			if s.Branch == "" {
+				continue
+			}
+
+			list, ok := branches[slug]
+			if !ok {
+				var err error
+				list, _, err = NewBranchService(apiClient).FetchBranches(slug)
+				if err != nil {
+					return nil, err
+				}
+				branches[slug] = list
+			}
+			index := slices.IndexFunc(list, func(b models.Branch) bool { return b.Name == s.Branch })
+			switch {
+			case index < 0:
+				add(i, "branch %q does not exist in repository %q", s.Branch, slug)
+			case s.Write && list[index].IsImmutable:
+				add(i, "branch %q of repository %q is immutable and cannot be written to", s.Branch, slug)
+			}
+
+		case *models.PipelineStageAction:
+			if s.Executable == "" {
+				continue
+			}
+			if executables == nil {
+				items, _, err := NewEditorItemsService(apiClient).FetchEditorItems()
+				if err != nil {
+					return nil, err
+				}
+				executables = make(map[string]bool, len(items.Files))
+				for _, file := range items.Files {
+					executables[path.Clean("/"+file.Path)] = true
+				}
+			}
+			if !executables[path.Clean("/"+s.Executable)] {
+				add(i, "executable %q does not exist", s.Executable)
+			}
+		}
+	}
+	return problems, nil
+}
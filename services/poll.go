@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// PollOptions configures how long-running operations are polled
+type PollOptions struct {
+	// Interval is the delay before the first poll. Defaults to 500ms
+	Interval time.Duration
+	// MaxInterval caps the delay between polls. Defaults to 10s
+	MaxInterval time.Duration
+	// Multiplier grows the delay after every poll. Defaults to 1.5
+	Multiplier float64
+}
+
+// withDefaults returns a copy of the options with unset fields filled in
+func (o *PollOptions) withDefaults() PollOptions {
+	opts := PollOptions{}
+	if o != nil {
+		opts = *o
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 500 * time.Millisecond
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = 10 * time.Second
+	}
+	if opts.MaxInterval < opts.Interval {
+		opts.MaxInterval = opts.Interval
+	}
+	if opts.Multiplier < 1 {
+		opts.Multiplier = 1.5
+	}
+	return opts
+}
+
+// poller waits between polls with exponential backoff
+type poller struct {
+	opts  PollOptions
+	delay time.Duration
+}
+
+// newPoller creates a poller from the given options
+func newPoller(opts *PollOptions) *poller {
+	o := opts.withDefaults()
+	return &poller{opts: o, delay: o.Interval}
+}
+
+// wait blocks until the next poll is due or the context is done
+func (p *poller) wait(ctx context.Context) error {
+	timer := time.NewTimer(p.delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+	}
+
+	p.delay = time.Duration(float64(p.delay) * p.opts.Multiplier)
+	if p.delay > p.opts.MaxInterval {
+		p.delay = p.opts.MaxInterval
+	}
+	return nil
+}
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/IrminData/irmin-sdk-go/client"
@@ -146,17 +148,19 @@ func (s *QueryService) UpdateQuery(
 	return &query, apiResp, nil
 }
 
-// ExecuteQuery executes a query by ID
-func (s *QueryService) ExecuteQuery(queryID string) (*client.IrminAPIResponse, error) {
+// ExecuteQuery executes a query by ID and returns its result
+func (s *QueryService) ExecuteQuery(queryID string) (*models.QueryExecutionResult, *client.IrminAPIResponse, error) {
 	endpoint := fmt.Sprintf("/v1/queries/%s/execute", queryID)
+	var result models.QueryExecutionResult
+
 	apiResp, err := s.client.FetchAPI(client.RequestOptions{
 		Method:   http.MethodGet,
 		Endpoint: endpoint,
-	}, nil)
+	}, &result)
 	if err != nil {
-		return nil, fmt.Errorf("execute query error: %w", err)
+		return nil, nil, fmt.Errorf("execute query error: %w", err)
 	}
-	return apiResp, nil
+	return &result, apiResp, nil
 }
 
 // GetQueryResults retrieves the results of a query, paginated
@@ -173,3 +177,123 @@ func (s *QueryService) GetQueryResults(queryID string, page int) (*models.QueryE
 	}
 	return &result, apiResp, nil
 }
+
+// ExecuteScriptInto executes a script and decodes the resulting rows into a slice of T
+func ExecuteScriptInto[T any](s *QueryService, scriptType, content string) ([]T, *models.QueryExecutionResult, *client.IrminAPIResponse, error) {
+	result, apiResp, err := s.ExecuteScript(scriptType, content)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	rows, err := models.DecodeQueryResult[T](result)
+	if err != nil {
+		return nil, result, apiResp, fmt.Errorf("execute script error: %w", err)
+	}
+	return rows, result, apiResp, nil
+}
+
+// QueryResults retrieves a page of query results and decodes the rows into a slice of T
+func QueryResults[T any](s *QueryService, queryID string, page int) ([]T, *models.QueryExecutionResult, *client.IrminAPIResponse, error) {
+	result, apiResp, err := s.GetQueryResults(queryID, page)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	rows, err := models.DecodeQueryResult[T](result)
+	if err != nil {
+		return nil, result, apiResp, fmt.Errorf("get query results error: %w", err)
+	}
+	return rows, result, apiResp, nil
+}
+
+// ExecuteAndWaitOptions configures QueryService.ExecuteAndWait
+type ExecuteAndWaitOptions struct {
+	// Poll controls the delay between checks of the query status
+	Poll *PollOptions
+	// OnLogs is called with every batch of new log lines while the query runs
+	OnLogs func(lines []string)
+}
+
+// ExecuteAndWait triggers the execution of a stored query, waits until it has finished
+// and returns the finished query along with an iterator over all pages of its results
+func (s *QueryService) ExecuteAndWait(ctx context.Context, queryID string, opts *ExecuteAndWaitOptions) (*models.Query, iter.Seq2[*models.QueryExecutionResult, error], error) {
+	if opts == nil {
+		opts = &ExecuteAndWaitOptions{}
+	}
+	queries := NewQueryService(s.client.WithContext(ctx))
+
+	// Remember the previous run so it is not mistaken for the new one
+	previous, _, err := queries.GetQuery(queryID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, _, err := queries.ExecuteQuery(queryID); err != nil {
+		return nil, nil, err
+	}
+
+	seenLogs := 0
+	p := newPoller(opts.Poll)
+	for {
+		query, _, err := queries.GetQuery(queryID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// The query keeps the logs of the previous run until the new run has started
+		if isNewQueryRun(previous, query) {
+			if len(query.Logs) > seenLogs {
+				if opts.OnLogs != nil {
+					opts.OnLogs(query.Logs[seenLogs:])
+				}
+				seenLogs = len(query.Logs)
+			}
+			// A finish time left over from the previous run does not finish the new run
+			if query.FinishedAt != "" && query.FinishedAt != previous.FinishedAt {
+				return query, s.AllQueryResults(ctx, queryID), nil
+			}
+		}
+
+		if err := p.wait(ctx); err != nil {
+			return nil, nil, fmt.Errorf("wait for query %s error: %w", queryID, err)
+		}
+	}
+}
+
+// isNewQueryRun reports whether a query describes a later run than previous. The API does not identify
+// runs, so they are told apart by their start and finish times. A run that starts and finishes with the
+// same timestamps as the previous run, e.g. within the same second, cannot be told apart from it.
+func isNewQueryRun(previous, query *models.Query) bool {
+	if query.StartedAt == "" {
+		return false
+	}
+	return query.StartedAt != previous.StartedAt || (query.FinishedAt != "" && query.FinishedAt != previous.FinishedAt)
+}
+
+// AllQueryResults returns an iterator over every page of the results of a query.
+// Iteration stops after the last page or at the first error
+func (s *QueryService) AllQueryResults(ctx context.Context, queryID string) iter.Seq2[*models.QueryExecutionResult, error] {
+	return func(yield func(*models.QueryExecutionResult, error) bool) {
+		queries := NewQueryService(s.client.WithContext(ctx))
+
+		for page := 1; ; page++ {
+			result, apiResp, err := queries.GetQueryResults(queryID, page)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(result, nil) {
+				return
+			}
+
+			pagination, err := apiResp.Pagination()
+			if err != nil {
+				yield(nil, fmt.Errorf("get query results error: %w", err))
+				return
+			}
+			if pagination == nil || page >= pagination.LastPage {
+				return
+			}
+		}
+	}
+}
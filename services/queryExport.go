@@ -0,0 +1,186 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// QueryExportFormat represents the file formats query results can be exported to
+type QueryExportFormat string
+
+const (
+	QueryExportFormatCSV     QueryExportFormat = "csv"
+	QueryExportFormatNDJSON  QueryExportFormat = "ndjson"
+	QueryExportFormatParquet QueryExportFormat = "parquet"
+)
+
+// QueryExportOptions configures how query results are exported
+type QueryExportOptions struct {
+	// Format of the exported file. Defaults to CSV
+	Format QueryExportFormat
+	// JSON Schema used for Parquet exports. Inferred from the results if nil
+	Schema map[string]interface{}
+	// Columns to export, in order. Defaults to every column of the results
+	Columns []string
+	// Parallelism of the Parquet writer. Defaults to 4
+	Parallelism int
+}
+
+// QueryExporter exports query results to local writers or repository objects
+type QueryExporter struct {
+	client *client.Client
+}
+
+// NewQueryExporter creates a new QueryExporter
+func NewQueryExporter(client *client.Client) *QueryExporter {
+	return &QueryExporter{
+		client: client,
+	}
+}
+
+// Export pages through all results of a query and writes them to w. The pages are merged into
+// a single table before anything is written, so the whole result is held in memory.
+func (e *QueryExporter) Export(ctx context.Context, queryID string, w io.Writer, opts QueryExportOptions) error {
+	table, err := e.collect(ctx, queryID)
+	if err != nil {
+		return err
+	}
+	return writeQueryExport(w, table, opts)
+}
+
+// ExportToRepository pages through all results of a query and uploads them as an object
+// at path in a repository branch. If commitMessage is not empty, the upload is committed.
+// The merged results and the encoded file are both buffered in memory before the upload.
+func (e *QueryExporter) ExportToRepository(
+	ctx context.Context,
+	queryID,
+	repository,
+	branch,
+	objectPath string,
+	opts QueryExportOptions,
+	commitMessage string,
+) (*models.Object, *client.IrminAPIResponse, error) {
+	var buf bytes.Buffer
+	if err := e.Export(ctx, queryID, &buf, opts); err != nil {
+		return nil, nil, err
+	}
+
+	apiClient := e.client.WithContext(ctx)
+	name := path.Base(objectPath)
+	object, apiResp, err := NewObjectService(apiClient).UploadObject(repository, branch, objectPath, name, map[string][]byte{name: buf.Bytes()})
+	if err != nil {
+		return nil, nil, fmt.Errorf("export query results error: %w", err)
+	}
+
+	if commitMessage != "" {
+		apiResp, err = NewCommitService(apiClient).CreateCommit(repository, branch, commitMessage)
+		if err != nil {
+			return object, nil, fmt.Errorf("export query results error: %w", err)
+		}
+	}
+	return object, apiResp, nil
+}
+
+// collect merges all pages of query results into a single table
+func (e *QueryExporter) collect(ctx context.Context, queryID string) (*models.QueryResultTable, error) {
+	merged := &models.QueryResultTable{Columns: []string{}}
+	columnIndex := make(map[string]int)
+
+	queries := NewQueryService(e.client)
+	for result, err := range queries.AllQueryResults(ctx, queryID) {
+		if err != nil {
+			return nil, fmt.Errorf("export query results error: %w", err)
+		}
+		page, err := result.Table()
+		if err != nil {
+			return nil, fmt.Errorf("export query results error: %w", err)
+		}
+
+		for _, column := range page.Columns {
+			if _, ok := columnIndex[column]; !ok {
+				columnIndex[column] = len(merged.Columns)
+				merged.Columns = append(merged.Columns, column)
+			}
+		}
+		for _, row := range page.Rows {
+			mergedRow := make([]models.JSONValue, len(merged.Columns))
+			for i, column := range page.Columns {
+				mergedRow[columnIndex[column]] = row[i]
+			}
+			merged.Rows = append(merged.Rows, mergedRow)
+		}
+	}
+
+	// Rows from earlier pages may be shorter if later pages added columns
+	for i, row := range merged.Rows {
+		if len(row) < len(merged.Columns) {
+			merged.Rows[i] = append(row, make([]models.JSONValue, len(merged.Columns)-len(row))...)
+		}
+	}
+	return merged, nil
+}
+
+// writeQueryExport writes a table in the requested format
+func writeQueryExport(w io.Writer, table *models.QueryResultTable, opts QueryExportOptions) error {
+	columns, rows := table.Columns, table.Rows
+	if len(opts.Columns) > 0 {
+		columns, rows = selectColumns(table, opts.Columns)
+	}
+
+	switch opts.Format {
+	case "", QueryExportFormatCSV:
+		if err := utils.WriteCSV(w, columns, rows); err != nil {
+			return fmt.Errorf("export query results error: %w", err)
+		}
+	case QueryExportFormatNDJSON:
+		if err := utils.WriteNDJSON(w, columns, rows); err != nil {
+			return fmt.Errorf("export query results error: %w", err)
+		}
+	case QueryExportFormatParquet:
+		schema := opts.Schema
+		if schema == nil {
+			schema = utils.InferJSONSchema(columns, rows)
+		}
+		parallelism := opts.Parallelism
+		if parallelism <= 0 {
+			parallelism = 4
+		}
+		data, err := utils.ConvertRowsToParquet(columns, rows, schema, parallelism)
+		if err != nil {
+			return fmt.Errorf("export query results error: %w", err)
+		}
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("export query results error: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown export format: %s", opts.Format)
+	}
+	return nil
+}
+
+// selectColumns returns the rows of a table restricted to the given columns
+func selectColumns(table *models.QueryResultTable, columns []string) ([]string, [][]models.JSONValue) {
+	index := make(map[string]int, len(table.Columns))
+	for i, column := range table.Columns {
+		index[column] = i
+	}
+
+	rows := make([][]models.JSONValue, len(table.Rows))
+	for i, row := range table.Rows {
+		selected := make([]models.JSONValue, len(columns))
+		for j, column := range columns {
+			if k, ok := index[column]; ok && k < len(row) {
+				selected[j] = row[k]
+			}
+		}
+		rows[i] = selected
+	}
+	return columns, rows
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// newQueryResultsServer serves the results of query q1 in two pages, the second of which adds a column
+func newQueryResultsServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	pages := map[string]string{
+		"1": `{"data": {"result": [{"id": 1, "name": "Oslo"}, {"id": 2, "name": "Bergen"}]}, "metadata": {"current_page": 1, "last_page": 2}}`,
+		"2": `{"data": {"result": [{"id": 3, "name": "Tromsø", "tags": ["arctic"]}]}, "metadata": {"current_page": 2, "last_page": 2}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/queries/q1/results" {
+			io.WriteString(w, pages[r.URL.Query().Get("page")])
+			return
+		}
+		if handler == nil {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestQueryExporterCollectsAllPages(t *testing.T) {
+	server := newQueryResultsServer(t, nil)
+	exporter := NewQueryExporter(client.NewClient(server.URL, "token", "en"))
+
+	table, err := exporter.collect(context.Background(), "q1")
+	if err != nil {
+		t.Fatalf("collecting results: %v", err)
+	}
+	expected := &models.QueryResultTable{
+		Columns: []string{"id", "name", "tags"},
+		Rows: [][]models.JSONValue{
+			{json.Number("1"), "Oslo", nil},
+			{json.Number("2"), "Bergen", nil},
+			{json.Number("3"), "Tromsø", []interface{}{"arctic"}},
+		},
+	}
+	if !reflect.DeepEqual(table, expected) {
+		t.Errorf("got %v, expected %v", table, expected)
+	}
+}
+
+func TestQueryExporterExport(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     QueryExportOptions
+		expected string
+	}{
+		{
+			name:     "csv",
+			opts:     QueryExportOptions{},
+			expected: "id,name,tags\n1,Oslo,\n2,Bergen,\n3,Tromsø,\"[\"\"arctic\"\"]\"\n",
+		},
+		{
+			name:     "ndjson",
+			opts:     QueryExportOptions{Format: QueryExportFormatNDJSON},
+			expected: "{\"id\":1,\"name\":\"Oslo\",\"tags\":null}\n{\"id\":2,\"name\":\"Bergen\",\"tags\":null}\n{\"id\":3,\"name\":\"Tromsø\",\"tags\":[\"arctic\"]}\n",
+		},
+		{
+			name:     "ndjson with selected columns",
+			opts:     QueryExportOptions{Format: QueryExportFormatNDJSON, Columns: []string{"name", "missing"}},
+			expected: "{\"name\":\"Oslo\",\"missing\":null}\n{\"name\":\"Bergen\",\"missing\":null}\n{\"name\":\"Tromsø\",\"missing\":null}\n",
+		},
+		{
+			name:     "parquet",
+			opts:     QueryExportOptions{Format: QueryExportFormatParquet, Parallelism: 1},
+			expected: `[{"Id":1,"Name":"Oslo","Tags":null},{"Id":2,"Name":"Bergen","Tags":null},{"Id":3,"Name":"Tromsø","Tags":"[\"arctic\"]"}]`,
+		},
+	}
+
+	server := newQueryResultsServer(t, nil)
+	exporter := NewQueryExporter(client.NewClient(server.URL, "token", "en"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := exporter.Export(context.Background(), "q1", &buf, tt.opts); err != nil {
+				t.Fatalf("exporting results: %v", err)
+			}
+			got := buf.String()
+			if tt.opts.Format == QueryExportFormatParquet {
+				got = parquetRowsJSON(t, buf.Bytes())
+			}
+			if got != tt.expected {
+				t.Errorf("got %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestQueryExporterExportToRepository(t *testing.T) {
+	var uploaded, committed string
+	server := newQueryResultsServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/repositories/lake/objects/exports/cities.csv":
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				t.Errorf("reading uploaded file: %v", err)
+				return
+			}
+			data, _ := io.ReadAll(file)
+			uploaded = string(data)
+			io.WriteString(w, `{"data": {"name": "cities.csv", "path": "/exports/cities.csv"}}`)
+		case "/v1/repositories/lake/commits":
+			committed = r.FormValue("branch") + ": " + r.FormValue("message")
+			io.WriteString(w, `{"data": {}}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	exporter := NewQueryExporter(client.NewClient(server.URL, "token", "en"))
+	object, _, err := exporter.ExportToRepository(context.Background(), "q1", "lake", "main", "exports/cities.csv", QueryExportOptions{}, "Export cities")
+	if err != nil {
+		t.Fatalf("exporting results: %v", err)
+	}
+	if object.Path != "/exports/cities.csv" {
+		t.Errorf("got object path %s, expected /exports/cities.csv", object.Path)
+	}
+	if expected := "id,name,tags\n1,Oslo,\n2,Bergen,\n3,Tromsø,\"[\"\"arctic\"\"]\"\n"; uploaded != expected {
+		t.Errorf("got uploaded content %q, expected %q", uploaded, expected)
+	}
+	if committed != "main: Export cities" {
+		t.Errorf("got commit %q, expected %q", committed, "main: Export cities")
+	}
+}
+
+// parquetRowsJSON reads every row of a Parquet file and returns them as JSON with sorted keys
+func parquetRowsJSON(t *testing.T, data []byte) string {
+	t.Helper()
+	pr, err := reader.NewParquetReader(buffer.NewBufferFileFromBytes(data), nil, 1)
+	if err != nil {
+		t.Fatalf("opening Parquet data: %v", err)
+	}
+	defer pr.ReadStop()
+
+	rows, err := pr.ReadByNumber(int(pr.GetNumRows()))
+	if err != nil {
+		t.Fatalf("reading Parquet rows: %v", err)
+	}
+	// Round trip through maps, as the field order follows the schema properties
+	data, err = json.Marshal(rows)
+	if err != nil {
+		t.Fatalf("encoding Parquet rows: %v", err)
+	}
+	var records []map[string]interface{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		t.Fatalf("decoding Parquet rows: %v", err)
+	}
+	data, err = json.Marshal(records)
+	if err != nil {
+		t.Fatalf("encoding Parquet rows: %v", err)
+	}
+	return string(data)
+}
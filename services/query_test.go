@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+func TestExecuteAndWaitSkipsPreviousRunLogs(t *testing.T) {
+	previous := models.Query{ID: "q1", StartedAt: "2026-01-01T10:00:00Z", FinishedAt: "2026-01-01T10:00:05Z", Logs: []string{"old run"}}
+	tests := []struct {
+		name string
+		// States of the query returned by the polls after the execution was triggered
+		polls    []models.Query
+		expected []string
+	}{
+		{
+			name: "finish time of the previous run kept",
+			polls: []models.Query{
+				previous,
+				{ID: "q1", StartedAt: "2026-01-01T11:00:00Z", FinishedAt: previous.FinishedAt, Logs: []string{"new 1"}},
+				{ID: "q1", StartedAt: "2026-01-01T11:00:00Z", FinishedAt: "2026-01-01T11:00:03Z", Logs: []string{"new 1", "new 2"}},
+			},
+			expected: []string{"new 1", "new 2"},
+		},
+		{
+			name: "finish time of the previous run cleared",
+			polls: []models.Query{
+				{ID: "q1", StartedAt: "2026-01-01T11:00:00Z", Logs: []string{"new 1"}},
+				{ID: "q1", StartedAt: "2026-01-01T11:00:00Z", FinishedAt: "2026-01-01T11:00:03Z", Logs: []string{"new 1", "new 2"}},
+			},
+			expected: []string{"new 1", "new 2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states := append([]models.Query{previous}, tt.polls...)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var data any = map[string]any{}
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/execute"):
+				case strings.HasSuffix(r.URL.Path, "/results"):
+					data = map[string]any{"result": []any{}}
+				default:
+					data = states[0]
+					if len(states) > 1 {
+						states = states[1:]
+					}
+				}
+				json.NewEncoder(w).Encode(map[string]any{"data": data})
+			}))
+			defer server.Close()
+
+			var logs []string
+			queries := NewQueryService(client.NewClient(server.URL, "token", "en"))
+			query, _, err := queries.ExecuteAndWait(context.Background(), "q1", &ExecuteAndWaitOptions{
+				Poll:   &PollOptions{Interval: time.Millisecond},
+				OnLogs: func(lines []string) { logs = append(logs, lines...) },
+			})
+			if err != nil {
+				t.Fatalf("executing query: %v", err)
+			}
+			if query.FinishedAt != "2026-01-01T11:00:03Z" {
+				t.Errorf("returned run finished at %s, expected the new run", query.FinishedAt)
+			}
+			if !slices.Equal(logs, tt.expected) {
+				t.Errorf("got logs %q, expected %q", logs, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// RefResolver resolves refs to the commits they point to. Branches, tags, commits and resolved refs
+// are cached per repository, so call ClearCache to see later changes. It is safe for concurrent use.
+type RefResolver struct {
+	client *client.Client
+
+	mu           sync.Mutex
+	repositories map[string]*repositoryRefs
+}
+
+// repositoryRefs caches the refs of a repository
+type repositoryRefs struct {
+	branches []models.Branch
+	tags     []models.Tag
+	graph    *CommitGraph
+	resolved map[string]models.ResolvedRef
+}
+
+// NewRefResolver creates a new RefResolver
+func NewRefResolver(client *client.Client) *RefResolver {
+	return &RefResolver{
+		client:       client,
+		repositories: make(map[string]*repositoryRefs),
+	}
+}
+
+// ClearCache forgets every cached branch, tag, commit and resolved ref
+func (r *RefResolver) ClearCache() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.repositories = make(map[string]*repositoryRefs)
+}
+
+// ResolveRef resolves a branch name, tag name, full or abbreviated commit hash, optionally followed by
+// relative suffixes such as `main~3` or `v1.2^`, to the commit it points to. Branch names take precedence
+// over tag names, and tag names over commit hashes. Abbreviated hashes are looked up in the history of
+// every branch and must be unambiguous.
+func (r *RefResolver) ResolveRef(ctx context.Context, repository, ref string) (*models.ResolvedRef, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	refs, ok := r.repositories[repository]
+	if !ok {
+		refs = &repositoryRefs{
+			graph:    NewCommitGraph(r.client, repository),
+			resolved: make(map[string]models.ResolvedRef),
+		}
+		r.repositories[repository] = refs
+	}
+	if resolved, ok := refs.resolved[ref]; ok {
+		return &resolved, nil
+	}
+
+	name, generations, err := utils.ParseRefExpression(ref)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := r.resolveName(ctx, repository, refs, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if generations > 0 {
+		hash := ""
+		ancestors := 0
+		for commit, err := range utils.CommitAncestors(resolved.Hash, refs.graph.lookup(ctx)) {
+			if err != nil {
+				return nil, err
+			}
+			if ancestors == generations {
+				hash = commit.Hash
+				break
+			}
+			ancestors++
+		}
+		if hash == "" {
+			return nil, fmt.Errorf("ref %s goes back %d commits but %s has %d ancestors", ref, generations, name, ancestors-1)
+		}
+		resolved = models.ResolvedRef{Kind: models.RefKindCommit, Name: ref, Hash: hash}
+	}
+
+	refs.resolved[ref] = resolved
+	return &resolved, nil
+}
+
+// resolveName resolves a branch name, tag name or commit hash
+func (r *RefResolver) resolveName(ctx context.Context, repository string, refs *repositoryRefs, name string) (models.ResolvedRef, error) {
+	apiClient := r.client.WithContext(ctx)
+
+	if refs.branches == nil {
+		branches, _, err := NewBranchService(apiClient).FetchBranches(repository)
+		if err != nil {
+			return models.ResolvedRef{}, err
+		}
+		refs.branches = branches
+	}
+	for _, branch := range refs.branches {
+		if branch.Name == name {
+			head, err := refs.graph.Head(ctx, name)
+			if err != nil {
+				return models.ResolvedRef{}, err
+			}
+			return models.ResolvedRef{Kind: models.RefKindBranch, Name: name, Hash: head.Hash}, nil
+		}
+	}
+
+	if refs.tags == nil {
+		tags, _, err := NewTagService(apiClient).FetchTags(repository)
+		if err != nil {
+			return models.ResolvedRef{}, err
+		}
+		refs.tags = tags
+	}
+	for _, tag := range refs.tags {
+		if tag.Name == name {
+			hash, err := r.resolveHash(ctx, refs, tag.Ref)
+			if err != nil {
+				return models.ResolvedRef{}, fmt.Errorf("tag %s: %w", name, err)
+			}
+			return models.ResolvedRef{Kind: models.RefKindTag, Name: name, Hash: hash}, nil
+		}
+	}
+
+	if utils.IsCommitHash(name) {
+		hash, err := r.resolveHash(ctx, refs, name)
+		if err != nil {
+			return models.ResolvedRef{}, err
+		}
+		return models.ResolvedRef{Kind: models.RefKindCommit, Name: name, Hash: hash}, nil
+	}
+	return models.ResolvedRef{}, fmt.Errorf("ref %s is not a branch, tag or commit of %s", name, repository)
+}
+
+// resolveHash expands a full or abbreviated commit hash. Cached commits are searched first, then the
+// commit is fetched by hash, and finally the history of every branch is searched.
+func (r *RefResolver) resolveHash(ctx context.Context, refs *repositoryRefs, prefix string) (string, error) {
+	prefix = strings.ToLower(prefix)
+	match := func() (string, bool, error) {
+		hashes := refs.graph.cachedCommitsWithPrefix(prefix)
+		for _, hash := range hashes {
+			if hash == prefix {
+				return hash, true, nil
+			}
+		}
+		switch len(hashes) {
+		case 0:
+			return "", false, nil
+		case 1:
+			return hashes[0], true, nil
+		default:
+			return "", false, fmt.Errorf("commit hash %s is ambiguous: %s", prefix, strings.Join(hashes, ", "))
+		}
+	}
+
+	if hash, ok, err := match(); ok || err != nil {
+		return hash, err
+	}
+	if commit, err := refs.graph.lookup(ctx)(prefix); err == nil && strings.HasPrefix(commit.Hash, prefix) {
+		return commit.Hash, nil
+	}
+	for _, branch := range refs.branches {
+		if _, err := refs.graph.Head(ctx, branch.Name); err != nil {
+			return "", err
+		}
+	}
+	if hash, ok, err := match(); ok || err != nil {
+		return hash, err
+	}
+	return "", fmt.Errorf("commit %s not found", prefix)
+}
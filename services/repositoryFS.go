@@ -0,0 +1,398 @@
+package services
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// RepositoryFSOptions configures a RepositoryFS
+type RepositoryFSOptions struct {
+	// Maximum total size in bytes of the object content kept in memory, evicting the least recently used
+	// content first. Content is fetched on every read if 0
+	CacheSize int64
+}
+
+// RepositoryFS is a read-only file system over the objects of a repository at a ref, implementing fs.FS,
+// fs.ReadDirFS, fs.StatFS and fs.ReadFileFS. Groups are directories and other objects are files with their
+// raw content. Listings and the sizes of files are cached for the life of the file system, so use a commit
+// hash as the ref, e.g. resolved with RefResolver, for a consistent view of a branch. Requests are bound to
+// the context of the client. It is safe for concurrent use.
+//
+// Directories are listed with ObjectService.FetchObjects, which describes every object, so objects are not
+// fetched one by one with FetchObject. Listings do not include the sizes of files though, so Stat and the
+// Info of directory entries fetch the schema of a file with FetchObjectSchema the first time its size is
+// needed, which costs a request per file, e.g. for every file of a directory listed by http.FileServerFS.
+// Only if the schema has no size is the content of the file fetched with FetchContent.
+type RepositoryFS struct {
+	client     *client.Client
+	repository string
+	ref        string
+
+	mu       sync.Mutex
+	listings map[string][]models.Object
+	sizes    map[string]int64
+	cache    *contentCache
+}
+
+// NewRepositoryFS creates a new RepositoryFS
+func NewRepositoryFS(client *client.Client, repository, ref string, opts RepositoryFSOptions) *RepositoryFS {
+	return &RepositoryFS{
+		client:     client,
+		repository: repository,
+		ref:        ref,
+		listings:   make(map[string][]models.Object),
+		sizes:      make(map[string]int64),
+		cache:      newContentCache(opts.CacheSize),
+	}
+}
+
+// Open opens a file or directory
+func (f *RepositoryFS) Open(name string) (fs.File, error) {
+	object, err := f.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if object.Type == models.ObjectTypeGroup {
+		return &repositoryDir{fsys: f, name: name, info: f.fileInfo(name, object, 0)}, nil
+	}
+	content, err := f.content("open", name, object)
+	if err != nil {
+		return nil, err
+	}
+	return &repositoryFile{info: f.fileInfo(name, object, int64(len(content))), Reader: bytes.NewReader(content)}, nil
+}
+
+// ReadFile returns the content of a file
+func (f *RepositoryFS) ReadFile(name string) ([]byte, error) {
+	object, err := f.lookup("readfile", name)
+	if err != nil {
+		return nil, err
+	}
+	if object.Type == models.ObjectTypeGroup {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+	content, err := f.content("readfile", name, object)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Clone(content), nil
+}
+
+// Stat describes a file or directory. The size of a file is fetched if not known, see RepositoryFS.
+func (f *RepositoryFS) Stat(name string) (fs.FileInfo, error) {
+	object, err := f.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	if object.Type == models.ObjectTypeGroup {
+		return f.fileInfo(name, object, 0), nil
+	}
+	size, err := f.size("stat", name, object)
+	if err != nil {
+		return nil, err
+	}
+	return f.fileInfo(name, object, size), nil
+}
+
+// ReadDir reads a directory, sorted by name. The sizes of files are only fetched by the Info of their entries.
+func (f *RepositoryFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	object, err := f.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if object.Type != models.ObjectTypeGroup {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	children, err := f.children(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	entries := make([]fs.DirEntry, 0, len(children))
+	for _, child := range children {
+		entries = append(entries, repositoryDirEntry{fsys: f, name: path.Join(name, child.Name), object: child})
+	}
+	return entries, nil
+}
+
+// objectPath returns the path of the object of a file system path
+func objectPath(name string) string {
+	if name == "." {
+		return "/"
+	}
+	return "/" + name
+}
+
+// lookup returns the object of a file system path. The root is a group named ".".
+func (f *RepositoryFS) lookup(op, name string) (models.Object, error) {
+	if !fs.ValidPath(name) {
+		return models.Object{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return models.Object{Name: ".", Path: "/", Type: models.ObjectTypeGroup}, nil
+	}
+	siblings, err := f.children(path.Dir(name))
+	if client.IsNotFound(err) {
+		return models.Object{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	if err != nil {
+		return models.Object{}, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	for _, sibling := range siblings {
+		if sibling.Name == path.Base(name) {
+			return sibling, nil
+		}
+	}
+	return models.Object{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+}
+
+// children lists the objects directly in a group, sorted by name and named after the last element of their path
+func (f *RepositoryFS) children(name string) ([]models.Object, error) {
+	f.mu.Lock()
+	children, ok := f.listings[name]
+	f.mu.Unlock()
+	if ok {
+		return children, nil
+	}
+
+	dir := objectPath(name)
+	objects, _, err := NewObjectService(f.client).FetchObjects(f.repository, dir, f.ref)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	children = []models.Object{}
+	for _, object := range objects {
+		p := treePath(object)
+		if p == dir || path.Dir(p) != dir || seen[p] {
+			continue
+		}
+		seen[p] = true
+		object.Name = path.Base(p)
+		children = append(children, object)
+	}
+	slices.SortFunc(children, func(a, b models.Object) int { return strings.Compare(a.Name, b.Name) })
+
+	f.mu.Lock()
+	f.listings[name] = children
+	f.mu.Unlock()
+	return children, nil
+}
+
+// content returns the raw content of a file, from the cache if possible
+func (f *RepositoryFS) content(op, name string, object models.Object) ([]byte, error) {
+	if content, ok := f.cache.get(name); ok {
+		return content, nil
+	}
+	content, err := NewObjectService(f.client).FetchContent(f.repository, contentPath(treePath(object)), f.ref, true)
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	if content == nil {
+		content = []byte{}
+	}
+	f.cache.put(name, content)
+	f.mu.Lock()
+	f.sizes[name] = int64(len(content))
+	f.mu.Unlock()
+	return content, nil
+}
+
+// size returns the size of a file, from its schema if it is not known yet, or else from its content
+func (f *RepositoryFS) size(op, name string, object models.Object) (int64, error) {
+	f.mu.Lock()
+	size, ok := f.sizes[name]
+	f.mu.Unlock()
+	if ok {
+		return size, nil
+	}
+
+	schema, _, err := NewObjectService(f.client).FetchObjectSchema(f.repository, contentPath(treePath(object)), f.ref)
+	if err == nil {
+		var schemaSize *int
+		if schema.Structured != nil {
+			schemaSize = schema.Structured.Size
+		} else if schema.Binary != nil {
+			schemaSize = schema.Binary.Size
+		}
+		if schemaSize != nil {
+			f.mu.Lock()
+			f.sizes[name] = int64(*schemaSize)
+			f.mu.Unlock()
+			return int64(*schemaSize), nil
+		}
+	}
+	// Errors fetching the schema surface when fetching the content, if they persist
+	content, err := f.content(op, name, object)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(content)), nil
+}
+
+func (f *RepositoryFS) fileInfo(name string, object models.Object, size int64) repositoryFileInfo {
+	info := repositoryFileInfo{name: path.Base(name), size: size, mode: 0o444}
+	if object.Type == models.ObjectTypeGroup {
+		info.mode = fs.ModeDir | 0o555
+	}
+	if object.LastModified != nil {
+		info.modTime, _ = utils.ParseAPITime(*object.LastModified)
+	}
+	return info
+}
+
+// repositoryFileInfo describes a file or directory of a RepositoryFS
+type repositoryFileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i repositoryFileInfo) Name() string       { return i.name }
+func (i repositoryFileInfo) Size() int64        { return i.size }
+func (i repositoryFileInfo) Mode() fs.FileMode  { return i.mode }
+func (i repositoryFileInfo) ModTime() time.Time { return i.modTime }
+func (i repositoryFileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i repositoryFileInfo) Sys() any           { return nil }
+
+// repositoryDirEntry is an entry of a directory of a RepositoryFS, whose size is fetched by Info if not known
+type repositoryDirEntry struct {
+	fsys   *RepositoryFS
+	name   string
+	object models.Object
+}
+
+func (e repositoryDirEntry) Name() string { return path.Base(e.name) }
+func (e repositoryDirEntry) IsDir() bool  { return e.object.Type == models.ObjectTypeGroup }
+
+func (e repositoryDirEntry) Type() fs.FileMode {
+	if e.IsDir() {
+		return fs.ModeDir
+	}
+	return 0
+}
+
+func (e repositoryDirEntry) Info() (fs.FileInfo, error) {
+	if e.IsDir() {
+		return e.fsys.fileInfo(e.name, e.object, 0), nil
+	}
+	size, err := e.fsys.size("stat", e.name, e.object)
+	if err != nil {
+		return nil, err
+	}
+	return e.fsys.fileInfo(e.name, e.object, size), nil
+}
+
+// repositoryFile is an open file of a RepositoryFS, which can also be read at offsets and seeked
+type repositoryFile struct {
+	info repositoryFileInfo
+	*bytes.Reader
+}
+
+func (f *repositoryFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *repositoryFile) Close() error               { return nil }
+
+// repositoryDir is an open directory of a RepositoryFS
+type repositoryDir struct {
+	fsys    *RepositoryFS
+	name    string
+	info    repositoryFileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *repositoryDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *repositoryDir) Close() error               { return nil }
+
+func (d *repositoryDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: fs.ErrInvalid}
+}
+
+// ReadDir reads the next n entries of the directory, or all remaining entries if n <= 0
+func (d *repositoryDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if d.entries == nil {
+		entries, err := d.fsys.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries = entries
+	}
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(remaining))
+	d.offset += n
+	return remaining[:n], nil
+}
+
+// contentCache is a least recently used cache of content bounded by its total size. It is safe for concurrent use.
+type contentCache struct {
+	mu      sync.Mutex
+	maxSize int64
+	size    int64
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// contentCacheEntry is the content cached for a key
+type contentCacheEntry struct {
+	key     string
+	content []byte
+}
+
+func newContentCache(maxSize int64) *contentCache {
+	return &contentCache{
+		maxSize: maxSize,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *contentCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*contentCacheEntry).content, true
+}
+
+// put caches content, evicting the least recently used content until it fits. Content larger than the cache is not cached.
+func (c *contentCache) put(key string, content []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if int64(len(content)) > c.maxSize {
+		return
+	}
+	if element, ok := c.entries[key]; ok {
+		c.size -= int64(len(element.Value.(*contentCacheEntry).content))
+		c.order.Remove(element)
+	}
+	c.entries[key] = c.order.PushFront(&contentCacheEntry{key: key, content: content})
+	c.size += int64(len(content))
+	for c.size > c.maxSize {
+		oldest := c.order.Back()
+		entry := oldest.Value.(*contentCacheEntry)
+		c.order.Remove(oldest)
+		delete(c.entries, entry.key)
+		c.size -= int64(len(entry.content))
+	}
+}
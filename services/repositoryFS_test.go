@@ -0,0 +1,272 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+const repositoryFSModified = "2026-03-01T12:00:00Z"
+
+var repositoryFSFiles = map[string]string{
+	"/cities/norway.csv":        "id,name\n1,Oslo\n2,Bergen\n",
+	"/cities/sweden.csv":        "id,name\n1,Stockholm\n",
+	"/templates/city.tmpl":      "{{range .}}<li>{{.}}</li>{{end}}",
+	"/templates/data/notes.txt": "Largest cities by population\n",
+}
+
+var repositoryFSGroups = map[string]bool{"/": true, "/cities": true, "/templates": true, "/templates/data": true}
+
+// repositoryFSServer is a stand-in for the objects API of the cities repository at ref abc123. It counts the
+// requests for content and schemas; schemas report the size of files if schemaSizes is set.
+type repositoryFSServer struct {
+	*httptest.Server
+	schemaSizes bool
+
+	mu              sync.Mutex
+	contentRequests int
+	schemaRequests  int
+}
+
+func newRepositoryFSServer(t *testing.T, schemaSizes bool) *repositoryFSServer {
+	t.Helper()
+	s := &repositoryFSServer{schemaSizes: schemaSizes}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *repositoryFSServer) requests() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.contentRequests, s.schemaRequests
+}
+
+func (s *repositoryFSServer) serve(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("ref") != "abc123" {
+		http.Error(w, "unknown ref", http.StatusNotFound)
+		return
+	}
+	if p, ok := strings.CutPrefix(r.URL.Path, "/v1/repositories/cities/objects/content/"); ok {
+		content, found := repositoryFSFiles["/"+p]
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		s.mu.Lock()
+		s.contentRequests++
+		s.mu.Unlock()
+		fmt.Fprint(w, content)
+		return
+	}
+	if p, ok := strings.CutPrefix(r.URL.Path, "/v1/repositories/cities/objects/schema/"); ok {
+		content, found := repositoryFSFiles["/"+p]
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		s.mu.Lock()
+		s.schemaRequests++
+		s.mu.Unlock()
+		binary := map[string]any{"type": "binary"}
+		if s.schemaSizes {
+			binary["size"] = len(content)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"name": path.Base(p), "path": "/" + p, "type": "binary", "binary": binary}})
+		return
+	}
+
+	dir := path.Clean("/" + strings.TrimPrefix(r.URL.Path, "/v1/repositories/cities/objects/"))
+	if !repositoryFSGroups[dir] {
+		http.NotFound(w, r)
+		return
+	}
+	lastModified := repositoryFSModified
+	objects := []models.Object{}
+	for p := range repositoryFSFiles {
+		if path.Dir(p) == dir {
+			objects = append(objects, models.Object{Name: path.Base(p), Path: dir, Type: models.ObjectTypeBinary, LastModified: &lastModified})
+		}
+	}
+	for p := range repositoryFSGroups {
+		if p != "/" && path.Dir(p) == dir {
+			objects = append(objects, models.Object{Name: path.Base(p), Path: dir, Type: models.ObjectTypeGroup})
+		}
+	}
+	json.NewEncoder(w).Encode(map[string]any{"data": objects})
+}
+
+func newTestRepositoryFS(server *repositoryFSServer, cacheSize int64) *RepositoryFS {
+	return NewRepositoryFS(client.NewClient(server.URL, "token", "en"), "cities", "abc123", RepositoryFSOptions{CacheSize: cacheSize})
+}
+
+func TestRepositoryFSWalkDir(t *testing.T) {
+	fsys := newTestRepositoryFS(newRepositoryFSServer(t, true), 64)
+	var walked []string
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			walked = append(walked, p)
+		}
+		return err
+	})
+	expected := []string{"cities/norway.csv", "cities/sweden.csv", "templates/city.tmpl", "templates/data/notes.txt"}
+	if err != nil || !slices.Equal(walked, expected) {
+		t.Errorf("got %q (%v), expected %q", walked, err, expected)
+	}
+}
+
+func TestRepositoryFSStat(t *testing.T) {
+	modTime, _ := time.Parse(time.RFC3339, repositoryFSModified)
+	tests := []struct {
+		name        string
+		schemaSizes bool
+		// Requests for content and schemas expected by the first Stat; repeated Stats make none
+		contentRequests, schemaRequests int
+	}{
+		{name: "size from schema", schemaSizes: true, schemaRequests: 1},
+		{name: "size from content", contentRequests: 1, schemaRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newRepositoryFSServer(t, tt.schemaSizes)
+			// Without a content cache, the size is still only fetched once
+			fsys := newTestRepositoryFS(server, 0)
+			for range 2 {
+				info, err := fs.Stat(fsys, "cities/norway.csv")
+				if err != nil {
+					t.Fatalf("stat: %v", err)
+				}
+				if info.Size() != int64(len(repositoryFSFiles["/cities/norway.csv"])) || !info.ModTime().Equal(modTime) || info.IsDir() {
+					t.Errorf("got size %d, modified %v and directory %t", info.Size(), info.ModTime(), info.IsDir())
+				}
+			}
+			contentRequests, schemaRequests := server.requests()
+			if contentRequests != tt.contentRequests || schemaRequests != tt.schemaRequests {
+				t.Errorf("got %d content and %d schema requests, expected %d and %d",
+					contentRequests, schemaRequests, tt.contentRequests, tt.schemaRequests)
+			}
+		})
+	}
+}
+
+func TestRepositoryFSDirEntryInfo(t *testing.T) {
+	server := newRepositoryFSServer(t, true)
+	entries, err := fs.ReadDir(newTestRepositoryFS(server, 0), "cities")
+	if err != nil {
+		t.Fatalf("reading directory: %v", err)
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			t.Fatalf("describing %s: %v", entry.Name(), err)
+		}
+		if expected := int64(len(repositoryFSFiles["/cities/"+entry.Name()])); info.Size() != expected {
+			t.Errorf("got size %d for %s, expected %d", info.Size(), entry.Name(), expected)
+		}
+	}
+	if contentRequests, _ := server.requests(); contentRequests != 0 {
+		t.Errorf("got %d content requests describing entries, expected none", contentRequests)
+	}
+}
+
+func TestRepositoryFSErrors(t *testing.T) {
+	fsys := newTestRepositoryFS(newRepositoryFSServer(t, true), 64)
+	if info, err := fs.Stat(fsys, "templates/data"); err != nil || !info.IsDir() {
+		t.Errorf("got %v (%v), expected a directory", info, err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		err  error
+	}{
+		{name: "missing file", path: "cities/denmark.csv", err: fs.ErrNotExist},
+		{name: "under a file", path: "cities/norway.csv/x", err: fs.ErrNotExist},
+		{name: "directory", path: "cities", err: fs.ErrInvalid},
+		{name: "invalid path", path: "/cities/norway.csv", err: fs.ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := fs.ReadFile(fsys, tt.path); !errors.Is(err, tt.err) {
+				t.Errorf("got %v, expected %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestRepositoryFSContentCache(t *testing.T) {
+	server := newRepositoryFSServer(t, true)
+	fsys := newTestRepositoryFS(server, 64)
+	read := func(name string) int {
+		t.Helper()
+		before, _ := server.requests()
+		if _, err := fs.ReadFile(fsys, name); err != nil {
+			t.Fatalf("reading %s: %v", name, err)
+		}
+		after, _ := server.requests()
+		return after - before
+	}
+
+	if n := read("cities/norway.csv"); n != 1 {
+		t.Errorf("got %d requests reading content, expected 1", n)
+	}
+	if n := read("cities/norway.csv"); n != 0 {
+		t.Errorf("got %d requests reading cached content, expected none", n)
+	}
+	// Reading the larger notes evicts the least recently used content to stay within 64 bytes
+	read("templates/data/notes.txt")
+	read("cities/sweden.csv")
+	if n := read("cities/norway.csv"); n != 1 {
+		t.Errorf("got %d requests reading evicted content, expected 1", n)
+	}
+}
+
+func TestRepositoryFSTemplates(t *testing.T) {
+	fsys := newTestRepositoryFS(newRepositoryFSServer(t, true), 64)
+	tmpl, err := template.ParseFS(fsys, "templates/*.tmpl")
+	if err != nil {
+		t.Fatalf("parsing templates: %v", err)
+	}
+	var rendered strings.Builder
+	if err := tmpl.ExecuteTemplate(&rendered, "city.tmpl", []string{"Oslo", "Bergen"}); err != nil {
+		t.Fatalf("rendering template: %v", err)
+	}
+	if expected := "<li>Oslo</li><li>Bergen</li>"; rendered.String() != expected {
+		t.Errorf("got %q, expected %q", rendered.String(), expected)
+	}
+}
+
+func TestRepositoryFSFileServer(t *testing.T) {
+	server := newRepositoryFSServer(t, true)
+	handler := http.FileServerFS(newTestRepositoryFS(server, 0))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/cities/", nil))
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), "norway.csv") {
+		t.Errorf("got directory listing %d %q", recorder.Code, recorder.Body.String())
+	}
+	if contentRequests, _ := server.requests(); contentRequests != 0 {
+		t.Errorf("got %d content requests listing a directory, expected none", contentRequests)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/cities/sweden.csv", nil))
+	if recorder.Code != http.StatusOK || recorder.Body.String() != repositoryFSFiles["/cities/sweden.csv"] {
+		t.Errorf("got file %d %q", recorder.Code, recorder.Body.String())
+	}
+}
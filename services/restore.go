@@ -0,0 +1,170 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// RestoreOptions configures a restore
+type RestoreOptions struct {
+	// Only preview the changes, without uploading, deleting or committing anything
+	DryRun bool
+	// Message of the restore commit. Defaults to `Restore <path> to <ref> (<hash>)`
+	Message string
+	// Diff the rows of changed structured objects
+	RowDiffs bool
+	// Key columns rows are aligned by in row diffs. Rows are aligned by position if empty
+	Key []string
+}
+
+// Restorer restores objects to their content at previous refs
+type Restorer struct {
+	client *client.Client
+}
+
+// NewRestorer creates a new Restorer
+func NewRestorer(client *client.Client) *Restorer {
+	return &Restorer{
+		client: client,
+	}
+}
+
+// Restore restores an object, or a group with every object under it, on a branch to its content at fromRef,
+// which can be anything RefResolver accepts, e.g. a commit hash, a tag or `main~3`. Objects whose content
+// differs are re-uploaded, objects that did not exist at fromRef are deleted along with groups that did not
+// exist, and the changes are committed with a message referencing the hash restored from.
+// With opts.DryRun the changes are only returned as a preview.
+func (r *Restorer) Restore(ctx context.Context, repository, branch, objectPath, fromRef string, opts RestoreOptions) (*models.RestoreResult, error) {
+	apiClient := r.client.WithContext(ctx)
+	objectService := NewObjectService(apiClient)
+
+	from, err := NewRefResolver(r.client).ResolveRef(ctx, repository, fromRef)
+	if err != nil {
+		return nil, err
+	}
+	thenObjects, thenGroups, err := objectTree(objectService, repository, objectPath, from.Hash)
+	if err != nil {
+		return nil, err
+	}
+	nowObjects, nowGroups, err := objectTree(objectService, repository, objectPath, branch)
+	if err != nil {
+		return nil, err
+	}
+	if len(thenObjects)+len(thenGroups)+len(nowObjects)+len(nowGroups) == 0 {
+		return nil, fmt.Errorf("object %s exists neither on %s nor at %s", objectPath, branch, fromRef)
+	}
+
+	result := &models.RestoreResult{
+		Repository: repository,
+		Branch:     branch,
+		Path:       objectPath,
+		FromRef:    fromRef,
+		FromHash:   from.Hash,
+		DryRun:     opts.DryRun,
+		Changes:    []models.RestoreChange{},
+	}
+	uploads := make(map[string][]byte)
+	for p, object := range thenObjects {
+		content, err := objectService.FetchContent(repository, contentPath(p), from.Hash, true)
+		if err != nil {
+			return nil, err
+		}
+		change := models.RestoreChange{Path: p, Type: models.ChangeTypeAdded, ObjectType: object.Type}
+		if _, ok := nowObjects[p]; ok {
+			current, err := objectService.FetchContent(repository, contentPath(p), branch, true)
+			if err != nil {
+				return nil, err
+			}
+			if bytes.Equal(current, content) {
+				result.Unchanged++
+				continue
+			}
+			change.Type = models.ChangeTypeChanged
+			if opts.RowDiffs && object.Type == models.ObjectTypeStructured {
+				change.RowDiff = restoreRowDiff(object, current, content, opts.Key)
+			}
+		}
+		uploads[p] = content
+		result.Changes = append(result.Changes, change)
+	}
+	for p, object := range nowObjects {
+		if _, ok := thenObjects[p]; !ok {
+			result.Changes = append(result.Changes, models.RestoreChange{Path: p, Type: models.ChangeTypeRemoved, ObjectType: object.Type})
+		}
+	}
+	var removedGroups []string
+	for p, group := range nowGroups {
+		if _, ok := thenGroups[p]; !ok {
+			removedGroups = append(removedGroups, p)
+			result.Changes = append(result.Changes, models.RestoreChange{Path: p, Type: models.ChangeTypeRemoved, ObjectType: group.Type})
+		}
+	}
+	slices.SortFunc(result.Changes, func(a, b models.RestoreChange) int { return strings.Compare(a.Path, b.Path) })
+
+	if opts.DryRun || len(result.Changes) == 0 {
+		return result, nil
+	}
+
+	for _, change := range result.Changes {
+		name := path.Base(change.Path)
+		switch {
+		case change.Type != models.ChangeTypeRemoved:
+			_, _, err = objectService.UploadObject(repository, branch, change.Path, name, map[string][]byte{name: uploads[change.Path]})
+		case change.ObjectType != models.ObjectTypeGroup:
+			_, err = objectService.DeleteObject(repository, branch, change.Path, name)
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+	// Groups are deleted once emptied, deepest first
+	slices.SortFunc(removedGroups, func(a, b string) int { return strings.Count(b, "/") - strings.Count(a, "/") })
+	for _, p := range removedGroups {
+		if _, err := objectService.DeleteObject(repository, branch, p, path.Base(p)); err != nil {
+			return result, err
+		}
+	}
+
+	message := opts.Message
+	if message == "" {
+		message = fmt.Sprintf("Restore %s to %s (%s)", objectPath, fromRef, from.Hash)
+	}
+	if _, err := NewCommitService(apiClient).CreateCommit(repository, branch, message); err != nil {
+		return result, err
+	}
+	result.CommitMessage = message
+	return result, nil
+}
+
+// restoreRowDiff diffs the rows of the current and restored content of a structured object,
+// reporting decoding errors in the diff
+func restoreRowDiff(object models.Object, current, restored []byte, key []string) *models.ObjectRowDiff {
+	diff := &models.ObjectRowDiff{Path: treePath(object)}
+	format, ok := utils.TableFormatOf(object)
+	if !ok {
+		diff.Error = "unknown structured object format"
+		return diff
+	}
+	currentTable, err := utils.DecodeTable(current, format)
+	if err != nil {
+		diff.Error = err.Error()
+		return diff
+	}
+	restoredTable, err := utils.DecodeTable(restored, format)
+	if err != nil {
+		diff.Error = err.Error()
+		return diff
+	}
+	*diff = utils.DiffTables(currentTable, restoredTable, key)
+	diff.Path = treePath(object)
+	diff.Format = string(format)
+	return diff
+}
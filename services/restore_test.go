@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// restoreServer serves a repository whose objects differ between the commit restored from and the main
+// branch, recording every upload, delete and commit
+func restoreServer(t *testing.T, writes *[]string) *httptest.Server {
+	// Content of the objects at each ref, by path. Groups have no content
+	refs := map[string]map[string]*string{
+		"abcd1234": {
+			"/data":            nil,
+			"/data/cities.csv": restoreContent("id,name\n1,Oslo\n"),
+			"/data/kept.csv":   restoreContent("id\n1\n"),
+		},
+		"main": {
+			"/data":                  nil,
+			"/data/cities.csv":       restoreContent("id,name\n1,Oslo\n2,Bergen\n"),
+			"/data/kept.csv":         restoreContent("id\n1\n"),
+			"/data/new.csv":          restoreContent("id\n2\n"),
+			"/data/extra":            nil,
+			"/data/extra/deep":       nil,
+			"/data/extra/deep/x.csv": restoreContent("id\n3\n"),
+		},
+	}
+	respond := func(w http.ResponseWriter, data any) {
+		json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const prefix = "/v1/repositories/test"
+		endpoint := strings.TrimPrefix(r.URL.Path, prefix)
+		objects := refs[r.URL.Query().Get("ref")]
+		switch {
+		case endpoint == "/branches":
+			respond(w, []map[string]any{{"name": "main", "default": true}})
+		case endpoint == "/tags":
+			respond(w, []any{})
+		case endpoint == "/commits/abcd1234":
+			respond(w, map[string]any{"hash": "abcd1234", "message": "Import", "timestamp": "2026-01-01 00:00:00"})
+		case endpoint == "/commits" && r.Method == http.MethodPost:
+			*writes = append(*writes, "commit "+r.FormValue("message"))
+			respond(w, nil)
+		case strings.HasPrefix(endpoint, "/objects/content/"):
+			content, ok := objects["/"+strings.TrimPrefix(endpoint, "/objects/content/")]
+			if !ok || content == nil {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(*content))
+		case strings.HasPrefix(endpoint, "/objects/") && r.Method == http.MethodPost:
+			objectPath := "/" + strings.TrimPrefix(endpoint, "/objects/")
+			if r.FormValue("_method") == "DELETE" {
+				*writes = append(*writes, "delete "+objectPath)
+			} else {
+				*writes = append(*writes, "upload "+objectPath)
+			}
+			respond(w, map[string]any{"name": path.Base(objectPath), "path": objectPath, "type": "structured"})
+		case strings.HasPrefix(endpoint, "/objects/"):
+			dir := "/" + strings.TrimPrefix(endpoint, "/objects/")
+			if _, ok := objects[dir]; !ok && dir != "/" {
+				http.NotFound(w, r)
+				return
+			}
+			children := []map[string]any{}
+			for p, content := range objects {
+				if p == "/" || path.Dir(p) != dir {
+					continue
+				}
+				objectType := models.ObjectTypeGroup
+				if content != nil {
+					objectType = models.ObjectTypeStructured
+				}
+				children = append(children, map[string]any{"name": path.Base(p), "path": p, "type": objectType})
+			}
+			respond(w, children)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func restoreContent(s string) *string {
+	return &s
+}
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		dryRun bool
+		// Expected changes as "<type> <path>"
+		expectedChanges   []string
+		expectedUnchanged int
+		// Expected uploads, deletes and commits in order
+		expectedWrites []string
+	}{
+		{
+			name:   "dry run",
+			path:   "/data",
+			dryRun: true,
+			expectedChanges: []string{
+				"changed /data/cities.csv",
+				"removed /data/extra",
+				"removed /data/extra/deep",
+				"removed /data/extra/deep/x.csv",
+				"removed /data/new.csv",
+			},
+			expectedUnchanged: 1,
+		},
+		{
+			name:            "changed object",
+			path:            "/data/cities.csv",
+			expectedChanges: []string{"changed /data/cities.csv"},
+			expectedWrites: []string{
+				"upload /data/cities.csv",
+				"commit Restore /data/cities.csv to abcd1234 (abcd1234)",
+			},
+		},
+		{
+			name:            "object missing at the ref",
+			path:            "/data/new.csv",
+			expectedChanges: []string{"removed /data/new.csv"},
+			expectedWrites: []string{
+				"delete /data/new.csv",
+				"commit Restore /data/new.csv to abcd1234 (abcd1234)",
+			},
+		},
+		{
+			// Objects are deleted first, then the groups emptied by them, deepest first
+			name: "groups missing at the ref",
+			path: "/data/extra",
+			expectedChanges: []string{
+				"removed /data/extra",
+				"removed /data/extra/deep",
+				"removed /data/extra/deep/x.csv",
+			},
+			expectedWrites: []string{
+				"delete /data/extra/deep/x.csv",
+				"delete /data/extra/deep",
+				"delete /data/extra",
+				"commit Restore /data/extra to abcd1234 (abcd1234)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var writes []string
+			server := restoreServer(t, &writes)
+			defer server.Close()
+
+			result, err := NewRestorer(client.NewClient(server.URL, "token", "en")).Restore(
+				context.Background(), "test", "main", tt.path, "abcd1234", RestoreOptions{DryRun: tt.dryRun},
+			)
+			if err != nil {
+				t.Fatalf("restoring: %v", err)
+			}
+			var changes []string
+			for _, change := range result.Changes {
+				changes = append(changes, string(change.Type)+" "+change.Path)
+			}
+			if !slices.Equal(changes, tt.expectedChanges) {
+				t.Errorf("got changes %v, expected %v", changes, tt.expectedChanges)
+			}
+			if result.Unchanged != tt.expectedUnchanged {
+				t.Errorf("got %d unchanged objects, expected %d", result.Unchanged, tt.expectedUnchanged)
+			}
+			if !slices.Equal(writes, tt.expectedWrites) {
+				t.Errorf("got writes %v, expected %v", writes, tt.expectedWrites)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// snapshotAttempts is how many times an interrupted archive download is resumed with a new download link
+const snapshotAttempts = 3
+
+// errArchiveChanged is returned when a resumed download link serves a different archive than the partial download
+var errArchiveChanged = errors.New("archive changed since the download started")
+
+// DownloadSnapshot downloads an archive of a repository, or of an object or group in it, at a ref and extracts
+// it into dest, creating it if needed. The archive is streamed to `<dest>.download`, which is kept while the
+// download is incomplete so that interrupted downloads, within the call or by a later call, resume where they
+// stopped. Within a call, resumed transfers are validated against the archive's ETag or Last-Modified date and
+// start over if it changed, e.g. because ref is a branch that moved. A partial download from an earlier call cannot
+// be validated, so it is downloaded again from the start if the completed archive fails verification.
+// The archive is verified before anything is extracted and its entries cannot escape dest.
+func (s *RepositoryService) DownloadSnapshot(ctx context.Context, repository, ref, path, dest string) (*models.Snapshot, error) {
+	archivePath := strings.TrimRight(dest, `/\`) + ".download"
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0o755); err != nil {
+		return nil, fmt.Errorf("download snapshot error: %w", err)
+	}
+	file, err := os.OpenFile(archivePath, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("download snapshot error: %w", err)
+	}
+	defer file.Close()
+
+	resumed, err := s.downloadArchive(ctx, repository, ref, path, file)
+	if err != nil {
+		return nil, fmt.Errorf("download snapshot error: %w", err)
+	}
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("download snapshot error: %w", err)
+	}
+	files, err := utils.ExtractArchive(file, size, dest)
+	if err != nil && resumed {
+		// The resumed part may belong to a different archive, so download it again from the start
+		if err := file.Truncate(0); err != nil {
+			return nil, fmt.Errorf("download snapshot error: %w", err)
+		}
+		if _, err := s.downloadArchive(ctx, repository, ref, path, file); err != nil {
+			return nil, fmt.Errorf("download snapshot error: %w", err)
+		}
+		if size, err = file.Seek(0, io.SeekEnd); err != nil {
+			return nil, fmt.Errorf("download snapshot error: %w", err)
+		}
+		resumed = false
+		files, err = utils.ExtractArchive(file, size, dest)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("download snapshot error: %w", err)
+	}
+
+	header := make([]byte, 512)
+	n, _ := file.ReadAt(header, 0)
+	format, _ := utils.DetectArchiveFormat(header[:n])
+	hash := sha256.New()
+	if _, err := io.Copy(hash, io.NewSectionReader(file, 0, size)); err != nil {
+		return nil, fmt.Errorf("download snapshot error: %w", err)
+	}
+	file.Close()
+	if err := os.Remove(archivePath); err != nil {
+		return nil, fmt.Errorf("download snapshot error: %w", err)
+	}
+
+	return &models.Snapshot{
+		Repository: repository,
+		Ref:        ref,
+		Path:       path,
+		Dest:       dest,
+		Format:     string(format),
+		Size:       size,
+		SHA256:     hex.EncodeToString(hash.Sum(nil)),
+		Resumed:    resumed,
+		Files:      files,
+	}, nil
+}
+
+// OpenSnapshot downloads an archive of a repository, or of an object or group in it, at a ref and returns its
+// content as a read-only in-memory file system. The archive is verified and its entries cannot escape the root.
+func (s *RepositoryService) OpenSnapshot(ctx context.Context, repository, ref, path string) (fs.FS, error) {
+	file, err := os.CreateTemp("", "irmin-snapshot-*")
+	if err != nil {
+		return nil, fmt.Errorf("open snapshot error: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if _, err := s.downloadArchive(ctx, repository, ref, path, file); err != nil {
+		return nil, fmt.Errorf("open snapshot error: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("open snapshot error: %w", err)
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("open snapshot error: %w", err)
+	}
+	fsys, err := utils.ArchiveFS(data)
+	if err != nil {
+		return nil, fmt.Errorf("open snapshot error: %w", err)
+	}
+	return fsys, nil
+}
+
+// downloadArchive appends the rest of an archive to a partially downloaded file, retrying interrupted
+// transfers with a new download link each time. It reports whether the archive includes a resumed transfer.
+func (s *RepositoryService) downloadArchive(ctx context.Context, repository, ref, path string, file *os.File) (bool, error) {
+	resumed := false
+	// Validator of the archive being downloaded, once a response reported one
+	validator := ""
+	for attempt := 1; ; attempt++ {
+		link, _, err := NewRepositoryService(s.client.WithContext(ctx)).GetRepositoryDownloadLink(repository, ref, path)
+		if err != nil {
+			return false, err
+		}
+		partial, err := s.fetchArchive(ctx, *link, file, &validator)
+		resumed = resumed || partial
+		if err == nil || ctx.Err() != nil || attempt == snapshotAttempts {
+			return resumed, err
+		}
+		var statusErr *client.StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode < http.StatusInternalServerError && statusErr.StatusCode != http.StatusForbidden {
+			return resumed, err
+		}
+	}
+}
+
+// fetchArchive requests the part of an archive after the content of a file and appends it to the file. It reports
+// whether the response resumed the content of the file. If validator is set, the part is only accepted if the archive
+// still has that validator; otherwise the file is emptied and errArchiveChanged is returned. validator is set to the
+// validator of the archive served. The API token is only sent if the link points to the API, not to e.g. a presigned
+// storage URL.
+func (s *RepositoryService) fetchArchive(ctx context.Context, link string, file *os.File, validator *string) (bool, error) {
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return false, err
+	}
+	if strings.HasPrefix(link, "/") {
+		link = s.client.BaseURL + link
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	if strings.HasPrefix(link, s.client.BaseURL) {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.client.Token))
+		req.Header.Set("Accept-Language", s.client.Locale)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if *validator != "" {
+			// Servers send the whole archive instead of the range if it changed
+			req.Header.Set("If-Range", *validator)
+		}
+	}
+
+	// Archives can take longer than the client timeout, so the download is bounded by ctx only
+	httpClient := http.Client{}
+	if s.client.HTTPClient != nil {
+		httpClient = *s.client.HTTPClient
+		httpClient.Timeout = 0
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	partial := false
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		var start int64
+		if _, err := fmt.Sscanf(resp.Header.Get("Content-Range"), "bytes %d-", &start); err != nil || start != offset {
+			return false, fmt.Errorf("unexpected content range %q resuming at byte %d", resp.Header.Get("Content-Range"), offset)
+		}
+		// Servers that ignore If-Range, or links to another copy of the archive, may serve a range of a newer archive
+		if *validator != "" && archiveValidator(resp.Header) != *validator {
+			*validator = ""
+			if err := file.Truncate(0); err != nil {
+				return false, err
+			}
+			return false, errArchiveChanged
+		}
+		partial = offset > 0
+	case http.StatusOK:
+		// The server ignored the range or the archive changed, so the archive is downloaded from the start
+		if err := file.Truncate(0); err != nil {
+			return false, err
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return false, err
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The file already holds the whole archive
+		if offset > 0 {
+			return true, nil
+		}
+		fallthrough
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return false, &client.StatusError{StatusCode: resp.StatusCode, Body: body}
+	}
+	*validator = archiveValidator(resp.Header)
+
+	written, err := io.Copy(file, resp.Body)
+	if err != nil {
+		return partial, fmt.Errorf("download interrupted after %d bytes: %w", written, err)
+	}
+	if resp.ContentLength >= 0 && written != resp.ContentLength {
+		return partial, fmt.Errorf("download interrupted after %d of %d bytes: %w", written, resp.ContentLength, io.ErrUnexpectedEOF)
+	}
+	return partial, nil
+}
+
+// archiveValidator returns the validator of an archive response that can be sent as If-Range: its ETag if it is
+// strong, or else its Last-Modified date
+func archiveValidator(header http.Header) string {
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return header.Get("Last-Modified")
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+)
+
+// snapshotArchive returns a tar.gz archive holding a single file
+func snapshotArchive(t *testing.T, name, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gz)
+	if err := w.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), ModTime: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte(content))
+	w.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+// archiveServer serves download links and passes archive requests to serve, numbering them from 1
+func archiveServer(t *testing.T, serve func(w http.ResponseWriter, r *http.Request, request int)) *httptest.Server {
+	t.Helper()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/download") {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"data":{"download_url":"/archives/cities.tar.gz"}}`)
+			return
+		}
+		requests++
+		serve(w, r, requests)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// serveInterrupted declares the whole archive but only sends its first half
+func serveInterrupted(w http.ResponseWriter, data []byte, etag string) {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+	w.Write(data[:len(data)/2])
+}
+
+func TestDownloadSnapshotResumesPartialDownload(t *testing.T) {
+	data := snapshotArchive(t, "cities.csv", "id,name\n1,Oslo\n")
+	var ranges []string
+	server := archiveServer(t, func(w http.ResponseWriter, r *http.Request, request int) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "cities.tar.gz", time.Time{}, bytes.NewReader(data))
+	})
+
+	dest := filepath.Join(t.TempDir(), "snapshot")
+	if err := os.WriteFile(dest+".download", data[:len(data)/2], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := NewRepositoryService(client.NewClient(server.URL, "token", "en")).DownloadSnapshot(context.Background(), "cities", "main", "/", dest)
+	if err != nil {
+		t.Fatalf("downloading snapshot: %v", err)
+	}
+	if expected := []string{fmt.Sprintf("bytes=%d-", len(data)/2)}; !slices.Equal(ranges, expected) {
+		t.Errorf("got ranges %q, expected %q", ranges, expected)
+	}
+	if !snapshot.Resumed || snapshot.Size != int64(len(data)) || !slices.Equal(snapshot.Files, []string{"cities.csv"}) {
+		t.Errorf("got %+v, expected the resumed archive", snapshot)
+	}
+	if _, err := os.Stat(dest + ".download"); err == nil {
+		t.Error("partial download was kept after extraction")
+	}
+}
+
+func TestDownloadSnapshotCreatesParentDirectories(t *testing.T) {
+	data := snapshotArchive(t, "cities.csv", "id,name\n1,Oslo\n")
+	server := archiveServer(t, func(w http.ResponseWriter, r *http.Request, request int) {
+		http.ServeContent(w, r, "cities.tar.gz", time.Time{}, bytes.NewReader(data))
+	})
+
+	dest := filepath.Join(t.TempDir(), "snapshots", "cities", "main")
+	if _, err := NewRepositoryService(client.NewClient(server.URL, "token", "en")).DownloadSnapshot(context.Background(), "cities", "main", "/", dest); err != nil {
+		t.Fatalf("downloading snapshot: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "cities.csv")); err != nil {
+		t.Errorf("snapshot was not extracted: %v", err)
+	}
+}
+
+func TestDownloadSnapshotValidatesResumedTransfers(t *testing.T) {
+	before := snapshotArchive(t, "before.csv", "id,name\n1,Oslo\n")
+	after := snapshotArchive(t, "after.csv", "id,name\n1,Oslo\n2,Bergen\n")
+
+	tests := []struct {
+		name string
+		// Whether the branch moved after the first, interrupted transfer
+		moved bool
+		// Whether the server serves ranges regardless of If-Range
+		ignoreIfRange bool
+		files         []string
+		resumed       bool
+	}{
+		{name: "unchanged", files: []string{"before.csv"}, resumed: true},
+		{name: "moved", moved: true, files: []string{"after.csv"}},
+		{name: "moved, If-Range ignored", moved: true, ignoreIfRange: true, files: []string{"after.csv"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ifRanges []string
+			server := archiveServer(t, func(w http.ResponseWriter, r *http.Request, request int) {
+				if request == 1 {
+					serveInterrupted(w, before, `"before"`)
+					return
+				}
+				ifRanges = append(ifRanges, r.Header.Get("If-Range"))
+				data, etag := before, `"before"`
+				if tt.moved {
+					data, etag = after, `"after"`
+				}
+				if tt.ignoreIfRange {
+					r.Header.Del("If-Range")
+				}
+				w.Header().Set("ETag", etag)
+				http.ServeContent(w, r, "cities.tar.gz", time.Time{}, bytes.NewReader(data))
+			})
+
+			dest := filepath.Join(t.TempDir(), "snapshot")
+			snapshot, err := NewRepositoryService(client.NewClient(server.URL, "token", "en")).DownloadSnapshot(context.Background(), "cities", "main", "/", dest)
+			if err != nil {
+				t.Fatalf("downloading snapshot: %v", err)
+			}
+			if len(ifRanges) == 0 || ifRanges[0] != `"before"` {
+				t.Errorf("got If-Range headers %q, expected the resumed transfer to send %q", ifRanges, `"before"`)
+			}
+			if !slices.Equal(snapshot.Files, tt.files) || snapshot.Resumed != tt.resumed {
+				t.Errorf("got files %q (resumed %t), expected %q (resumed %t)", snapshot.Files, snapshot.Resumed, tt.files, tt.resumed)
+			}
+		})
+	}
+}
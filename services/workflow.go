@@ -1,8 +1,10 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/IrminData/irmin-sdk-go/client"
 	"github.com/IrminData/irmin-sdk-go/models"
@@ -34,6 +36,24 @@ func (s *WorkflowService) FetchWorkflows() ([]models.Workflow, *client.IrminAPIR
 	return workflows, apiResp, nil
 }
 
+// FetchWorkflowGraph retrieves all workflows and builds their dependency graph, including trigger cycles
+func (s *WorkflowService) FetchWorkflowGraph() (*models.WorkflowGraph, *client.IrminAPIResponse, error) {
+	workflows, apiResp, err := s.FetchWorkflows()
+	if err != nil {
+		return nil, nil, err
+	}
+	return utils.BuildWorkflowGraph(workflows), apiResp, nil
+}
+
+// FetchLineageGraph retrieves all workflows and builds the lineage of the data they move
+func (s *WorkflowService) FetchLineageGraph() (*models.LineageGraph, *client.IrminAPIResponse, error) {
+	workflows, apiResp, err := s.FetchWorkflows()
+	if err != nil {
+		return nil, nil, err
+	}
+	return utils.BuildLineageGraph(workflows), apiResp, nil
+}
+
 // FetchWorkflow retrieves a single workflow by its ID
 func (s *WorkflowService) FetchWorkflow(workflowID string) (*models.Workflow, *client.IrminAPIResponse, error) {
 	endpoint := fmt.Sprintf("/v1/workflows/%s", workflowID)
@@ -105,17 +125,164 @@ func (s *WorkflowService) DeleteWorkflow(workflowID string) (*client.IrminAPIRes
 }
 
 // TriggerWorkflowRun triggers a workflow run manually
-func (s *WorkflowService) TriggerWorkflowRun(workflowID string) (*client.IrminAPIResponse, error) {
+func (s *WorkflowService) TriggerWorkflowRun(workflowID string) (*models.WorkflowRun, *client.IrminAPIResponse, error) {
+	var run models.WorkflowRun
 	apiResp, err := s.client.FetchAPI(client.RequestOptions{
 		Method:   http.MethodGet,
 		Endpoint: fmt.Sprintf("/v1/workflows/%s/run", workflowID),
+	}, &run)
+	if err != nil {
+		return nil, nil, fmt.Errorf("trigger workflow run error: %w", err)
+	}
+	return &run, apiResp, nil
+}
+
+// ListWorkflowRuns retrieves all runs of a workflow
+func (s *WorkflowService) ListWorkflowRuns(workflowID string) ([]models.WorkflowRun, *client.IrminAPIResponse, error) {
+	var runs []models.WorkflowRun
+	apiResp, err := s.client.FetchAPI(client.RequestOptions{
+		Method:   http.MethodGet,
+		Endpoint: fmt.Sprintf("/v1/workflows/%s/runs", workflowID),
+	}, &runs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list workflow runs error: %w", err)
+	}
+	return runs, apiResp, nil
+}
+
+// FetchWorkflowRun retrieves a single run of a workflow
+func (s *WorkflowService) FetchWorkflowRun(workflowID, workflowRunID string) (*models.WorkflowRun, *client.IrminAPIResponse, error) {
+	var run models.WorkflowRun
+	apiResp, err := s.client.FetchAPI(client.RequestOptions{
+		Method:   http.MethodGet,
+		Endpoint: fmt.Sprintf("/v1/workflows/%s/runs/%s", workflowID, workflowRunID),
+	}, &run)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetch workflow run error: %w", err)
+	}
+	return &run, apiResp, nil
+}
+
+// CancelWorkflowRun cancels a pending or running workflow run
+func (s *WorkflowService) CancelWorkflowRun(workflowID, workflowRunID string) (*client.IrminAPIResponse, error) {
+	apiResp, err := s.client.FetchAPI(client.RequestOptions{
+		Method:   http.MethodPost,
+		Endpoint: fmt.Sprintf("/v1/workflows/%s/runs/%s/cancel", workflowID, workflowRunID),
 	}, nil)
 	if err != nil {
-		return nil, fmt.Errorf("trigger workflow run error: %w", err)
+		return nil, fmt.Errorf("cancel workflow run error: %w", err)
 	}
 	return apiResp, nil
 }
 
+// WorkflowRunError is returned by TriggerAndWait when a workflow run finishes with an error
+type WorkflowRunError struct {
+	// Run is the failed workflow run
+	Run models.WorkflowRun
+	// Logs is the log feed of the run, if it could be fetched
+	Logs []string
+}
+
+// Error implements the error interface
+func (e *WorkflowRunError) Error() string {
+	msg := fmt.Sprintf("workflow run %s of workflow %s failed", e.Run.ID, e.Run.WorkflowID)
+	if len(e.Logs) > 0 {
+		msg += ": " + e.Logs[len(e.Logs)-1]
+	}
+	return msg
+}
+
+// TriggerAndWaitOptions configures how TriggerAndWait waits for a workflow run
+type TriggerAndWaitOptions struct {
+	// Poll controls the delay between checks of the run status
+	Poll *PollOptions
+	// QueueTimeout limits how long the run may take to appear and how long it may stay in a
+	// state other than running, such as pending or paused. Defaults to 5 minutes
+	QueueTimeout time.Duration
+}
+
+// TriggerAndWait triggers a workflow run and polls it until it is complete or has failed.
+// A failed run is reported as a *WorkflowRunError carrying the run logs.
+// If the trigger does not return the run, TriggerAndWait waits for a run that did not exist before
+// the trigger. A run started at the same time by a schedule or an event may be picked up instead.
+func (s *WorkflowService) TriggerAndWait(ctx context.Context, workflowID string, opts *TriggerAndWaitOptions) (*models.WorkflowRun, error) {
+	if opts == nil {
+		opts = &TriggerAndWaitOptions{}
+	}
+	queueTimeout := opts.QueueTimeout
+	if queueTimeout <= 0 {
+		queueTimeout = 5 * time.Minute
+	}
+
+	apiClient := s.client.WithContext(ctx)
+	workflows := NewWorkflowService(apiClient)
+
+	// Remember the existing runs so an older run is not mistaken for the triggered one
+	previous, _, err := workflows.ListWorkflowRuns(workflowID)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(previous))
+	for _, run := range previous {
+		known[run.ID] = true
+	}
+
+	run, _, err := workflows.TriggerWorkflowRun(workflowID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newPoller(opts.Poll)
+	queuedSince := time.Now()
+	for run.ID == "" {
+		if time.Since(queuedSince) > queueTimeout {
+			return nil, fmt.Errorf("trigger workflow run error: no run of workflow %s appeared within %s", workflowID, queueTimeout)
+		}
+		if err := p.wait(ctx); err != nil {
+			return nil, fmt.Errorf("wait for run of workflow %s error: %w", workflowID, err)
+		}
+
+		runs, _, err := workflows.ListWorkflowRuns(workflowID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range runs {
+			if runs[i].ID != "" && !known[runs[i].ID] {
+				run = &runs[i]
+				break
+			}
+		}
+	}
+
+	for {
+		switch run.Status {
+		case models.WorkflowStatusComplete:
+			return run, nil
+		case models.WorkflowStatusError:
+			runErr := &WorkflowRunError{Run: *run}
+			if logs, _, err := NewLogService(apiClient).FetchWorkflowRunLogs(workflowID, run.ID); err == nil {
+				runErr.Logs = logs.Logs
+			}
+			return run, runErr
+		case models.WorkflowStatusRunning:
+			queuedSince = time.Now()
+		default:
+			if time.Since(queuedSince) > queueTimeout {
+				return run, fmt.Errorf("wait for workflow run %s error: run is still %s after %s", run.ID, run.Status, queueTimeout)
+			}
+		}
+
+		if err := p.wait(ctx); err != nil {
+			return run, fmt.Errorf("wait for workflow run %s error: %w", run.ID, err)
+		}
+
+		run, _, err = workflows.FetchWorkflowRun(workflowID, run.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
 // CreateImportWorkflow creates a new import workflow
 func (s *WorkflowService) CreateImportWorkflow(
 	connection,
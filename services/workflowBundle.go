@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// WorkflowBundleExportOptions configures what is exported along with the workflows
+type WorkflowBundleExportOptions struct {
+	// IncludeConnections adds the definitions of the referenced connections, without the values of their details
+	IncludeConnections bool
+	// IncludeExecutables adds the editor files executed by action workflows and action stages
+	IncludeExecutables bool
+}
+
+// WorkflowBundleImportOptions configures how a bundle is recreated in the target workspace
+type WorkflowBundleImportOptions struct {
+	// Mapping of references in the bundle to objects in the target workspace
+	Mapping models.WorkflowBundleMapping
+	// ConnectionDetails holds the details of bundled connections that are created, keyed by their bundled ID
+	ConnectionDetails map[string]map[string]string
+}
+
+// WorkflowBundleService exports workflows to portable bundles and imports them into other workspaces
+type WorkflowBundleService struct {
+	client *client.Client
+}
+
+// NewWorkflowBundleService creates a new WorkflowBundleService
+func NewWorkflowBundleService(client *client.Client) *WorkflowBundleService {
+	return &WorkflowBundleService{
+		client: client,
+	}
+}
+
+// ExportWorkflows exports workflows to a bundle. If workspace is set, the client switches to it first.
+// Without workflow IDs, all workflows of the workspace are exported.
+func (s *WorkflowBundleService) ExportWorkflows(ctx context.Context, workspace string, workflowIDs []string, opts WorkflowBundleExportOptions) (*models.WorkflowBundle, error) {
+	apiClient := s.client.WithContext(ctx)
+	if workspace != "" {
+		if _, err := NewWorkspaceService(apiClient).SwitchWorkspace(workspace); err != nil {
+			return nil, err
+		}
+	}
+
+	workflows, _, err := NewWorkflowService(apiClient).FetchWorkflows()
+	if err != nil {
+		return nil, err
+	}
+	if len(workflowIDs) > 0 {
+		selected := make([]models.Workflow, 0, len(workflowIDs))
+		for _, id := range workflowIDs {
+			index := slices.IndexFunc(workflows, func(w models.Workflow) bool { return w.ID == id })
+			if index < 0 {
+				return nil, fmt.Errorf("workflow %q not found", id)
+			}
+			selected = append(selected, workflows[index])
+		}
+		workflows = selected
+	}
+
+	bundle := &models.WorkflowBundle{
+		Version:         models.WorkflowBundleVersion,
+		SourceWorkspace: workspace,
+		ExportedAt:      time.Now().UTC().Format(time.RFC3339),
+		Workflows:       make([]models.WorkflowBundleWorkflow, 0, len(workflows)),
+	}
+	var connectionIDs, executables []string
+	for _, workflow := range workflows {
+		spec, err := utils.WorkflowToSpec(workflow)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Workflows = append(bundle.Workflows, models.WorkflowBundleWorkflow{ID: workflow.ID, Spec: spec})
+
+		connections, _, files := utils.WorkflowSpecReferences(spec)
+		connectionIDs = append(connectionIDs, connections...)
+		executables = append(executables, files...)
+	}
+
+	if opts.IncludeConnections && len(connectionIDs) > 0 {
+		connections, _, err := NewConnectionService(apiClient).FetchConnections()
+		if err != nil {
+			return nil, err
+		}
+		for _, connection := range connections {
+			if !slices.Contains(connectionIDs, connection.ID) {
+				continue
+			}
+			bundle.Connections = append(bundle.Connections, models.WorkflowBundleConnection{
+				ID:          connection.ID,
+				Name:        connection.Name,
+				Description: connection.Description,
+				Connector:   connection.Connector.ID,
+				Settings:    connection.Settings,
+				Details:     slices.Sorted(maps.Keys(connection.Details)),
+			})
+		}
+	}
+
+	if opts.IncludeExecutables && len(executables) > 0 {
+		items, _, err := NewEditorItemsService(apiClient).FetchEditorItems()
+		if err != nil {
+			return nil, err
+		}
+		for i := range executables {
+			executables[i] = path.Clean("/" + executables[i])
+		}
+		for _, file := range items.Files {
+			if !slices.Contains(executables, path.Clean("/"+file.Path)) {
+				continue
+			}
+			bundle.Executables = append(bundle.Executables, models.WorkflowBundleExecutable{
+				Name:     file.Name,
+				Path:     file.Path,
+				Type:     file.Type,
+				Contents: file.Contents,
+			})
+		}
+	}
+	return bundle, nil
+}
+
+// ImportWorkflows recreates the workflows of a bundle. If workspace is set, the client switches to it first.
+// References are remapped with the mapping of the options. Bundled connections without a mapping are created
+// from their definition, and bundled executables missing from the editor files are created; existing files are kept.
+// Workflow run triggers on workflows in the bundle are remapped to the created workflows. On error, the result
+// lists everything created before the failure.
+func (s *WorkflowBundleService) ImportWorkflows(ctx context.Context, workspace string, bundle *models.WorkflowBundle, opts WorkflowBundleImportOptions) (*models.WorkflowBundleImport, error) {
+	if bundle.Version > models.WorkflowBundleVersion {
+		return nil, fmt.Errorf("unsupported workflow bundle version %d", bundle.Version)
+	}
+
+	apiClient := s.client.WithContext(ctx)
+	if workspace != "" {
+		if _, err := NewWorkspaceService(apiClient).SwitchWorkspace(workspace); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &models.WorkflowBundleImport{
+		Workflows:   []models.Workflow{},
+		WorkflowIDs: make(map[string]string),
+		Connections: make(map[string]string),
+		Executables: []string{},
+	}
+	mapping := models.WorkflowBundleMapping{
+		Connections:  maps.Clone(opts.Mapping.Connections),
+		Repositories: opts.Mapping.Repositories,
+		Workflows:    maps.Clone(opts.Mapping.Workflows),
+	}
+	if mapping.Connections == nil {
+		mapping.Connections = make(map[string]string)
+	}
+	if mapping.Workflows == nil {
+		mapping.Workflows = make(map[string]string)
+	}
+
+	connections := NewConnectionService(apiClient)
+	for _, connection := range bundle.Connections {
+		if _, ok := mapping.Connections[connection.ID]; ok {
+			continue
+		}
+		created, _, err := connections.CreateConnection(
+			connection.Connector,
+			opts.ConnectionDetails[connection.ID],
+			connection.Settings,
+			connection.Name,
+			connection.Description,
+		)
+		if err != nil {
+			return result, fmt.Errorf("create connection %q error: %w", connection.Name, err)
+		}
+		mapping.Connections[connection.ID] = created.ID
+		result.Connections[connection.ID] = created.ID
+	}
+
+	if len(bundle.Executables) > 0 {
+		created, err := importExecutables(NewEditorItemsService(apiClient), bundle.Executables)
+		result.Executables = append(result.Executables, created...)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	bundled := make(map[string]bool, len(bundle.Workflows))
+	for _, entry := range bundle.Workflows {
+		bundled[entry.ID] = true
+	}
+
+	// Run triggers on bundled workflows that do not exist yet are added once all workflows are created
+	workflows := NewWorkflowService(apiClient)
+	var deferred []int
+	for i, entry := range bundle.Workflows {
+		spec := entry.Spec
+		pending := func(trigger models.WorkflowTriggerSpec) bool {
+			return trigger.Workflow != nil && bundled[*trigger.Workflow] && result.WorkflowIDs[*trigger.Workflow] == ""
+		}
+		if spec.Schedule != nil && slices.ContainsFunc(spec.Schedule.Triggers, pending) {
+			schedule := *spec.Schedule
+			schedule.Triggers = slices.DeleteFunc(slices.Clone(schedule.Triggers), pending)
+			spec.Schedule = &schedule
+			deferred = append(deferred, i)
+		}
+
+		spec = utils.RemapWorkflowSpec(spec, mapping)
+		workflow, err := createWorkflow(workflows, &spec, bundledWorkflowDocumentation(spec))
+		if err != nil {
+			return result, fmt.Errorf("create workflow %q error: %w", spec.Name, err)
+		}
+		mapping.Workflows[entry.ID] = workflow.ID
+		result.WorkflowIDs[entry.ID] = workflow.ID
+		result.Workflows = append(result.Workflows, *workflow)
+	}
+
+	for _, i := range deferred {
+		spec := utils.RemapWorkflowSpec(bundle.Workflows[i].Spec, mapping)
+		schedule, err := utils.WorkflowSpecSchedule(spec.Schedule)
+		if err != nil {
+			return result, err
+		}
+		workflow, _, err := workflows.UpdateWorkflow(result.Workflows[i].ID, spec.Name, spec.Description, bundledWorkflowDocumentation(spec), schedule)
+		if err != nil {
+			return result, fmt.Errorf("update workflow %q error: %w", spec.Name, err)
+		}
+		result.Workflows[i] = *workflow
+	}
+	return result, nil
+}
+
+// CloneWorkflows exports workflows from the source workspace and imports them into the target workspace.
+// The client is left switched to the target workspace.
+func (s *WorkflowBundleService) CloneWorkflows(
+	ctx context.Context,
+	source, target string,
+	workflowIDs []string,
+	exportOpts WorkflowBundleExportOptions,
+	importOpts WorkflowBundleImportOptions,
+) (*models.WorkflowBundleImport, error) {
+	bundle, err := s.ExportWorkflows(ctx, source, workflowIDs, exportOpts)
+	if err != nil {
+		return nil, err
+	}
+	return s.ImportWorkflows(ctx, target, bundle, importOpts)
+}
+
+// bundledWorkflowDocumentation returns the documentation of an imported workflow, keeping the spec key of managed workflows
+func bundledWorkflowDocumentation(spec models.WorkflowSpec) string {
+	if spec.Key == "" {
+		return spec.Documentation
+	}
+	return utils.WithWorkflowSpecKey(spec.Documentation, spec.Key)
+}
+
+// importExecutables creates the bundled editor files missing from the workspace, along with their folders
+func importExecutables(editorItems *EditorItemsService, executables []models.WorkflowBundleExecutable) ([]string, error) {
+	items, _, err := editorItems.FetchEditorItems()
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(items.Files)+len(items.Folders))
+	for _, file := range items.Files {
+		existing[path.Clean("/"+file.Path)] = true
+	}
+	for _, folder := range items.Folders {
+		existing[path.Clean("/"+folder.Path)] = true
+	}
+
+	var created []string
+	for _, executable := range executables {
+		filePath := path.Clean("/" + executable.Path)
+		if existing[filePath] {
+			continue
+		}
+
+		// Create the folders containing the file, from the outermost one
+		folderPath := ""
+		for _, part := range strings.Split(strings.TrimPrefix(path.Dir(filePath), "/"), "/") {
+			if part == "" {
+				continue
+			}
+			folderPath += "/" + part
+			if existing[folderPath] {
+				continue
+			}
+			if _, _, err := editorItems.CreateFolder(&models.EditorItemsFolder{Name: part, Path: folderPath}); err != nil {
+				return created, err
+			}
+			existing[folderPath] = true
+		}
+
+		file := &models.EditorItemsFile{
+			Name:     executable.Name,
+			Path:     filePath,
+			Type:     executable.Type,
+			Contents: executable.Contents,
+		}
+		if _, _, err := editorItems.CreateFile(file, false); err != nil {
+			return created, err
+		}
+		existing[filePath] = true
+		created = append(created, filePath)
+	}
+	return created, nil
+}
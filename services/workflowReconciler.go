@@ -0,0 +1,375 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// WorkflowPlanOptions configures how a workflow plan is computed
+type WorkflowPlanOptions struct {
+	// PruneUnmanaged also deletes workflows that were not created from a spec and are not declared in it.
+	// Managed workflows that are no longer declared are always deleted
+	PruneUnmanaged bool
+}
+
+// WorkflowReconciler reconciles the workflows of a workspace with a declarative spec file
+type WorkflowReconciler struct {
+	client *client.Client
+}
+
+// NewWorkflowReconciler creates a new WorkflowReconciler
+func NewWorkflowReconciler(client *client.Client) *WorkflowReconciler {
+	return &WorkflowReconciler{
+		client: client,
+	}
+}
+
+// Plan compares a spec file with the existing workflows and returns the changes needed to reconcile them.
+// Workflows are matched by the key stored in their documentation, or by name for workflows not yet managed.
+func (r *WorkflowReconciler) Plan(ctx context.Context, file *models.WorkflowSpecFile, opts WorkflowPlanOptions) (*models.WorkflowPlan, error) {
+	if err := utils.ValidateWorkflowSpec(file); err != nil {
+		return nil, err
+	}
+
+	workflows, _, err := NewWorkflowService(r.client.WithContext(ctx)).FetchWorkflows()
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]*models.Workflow)
+	byName := make(map[string]*models.Workflow)
+	for i := range workflows {
+		key, _ := utils.WorkflowSpecKey(workflows[i].Documentation)
+		if key != "" {
+			byKey[key] = &workflows[i]
+		} else if _, ok := byName[workflows[i].Name]; !ok {
+			byName[workflows[i].Name] = &workflows[i]
+		}
+	}
+
+	plan := &models.WorkflowPlan{}
+	matched := make(map[string]bool)
+
+	for i := range file.Workflows {
+		spec := &file.Workflows[i]
+		key := spec.SpecKey()
+
+		current, ok := byKey[key]
+		if !ok {
+			current, ok = byName[spec.Name]
+			if ok && matched[current.ID] {
+				ok = false
+			}
+		}
+		if !ok {
+			plan.Changes = append(plan.Changes, models.WorkflowPlanChange{
+				Action: models.WorkflowPlanActionCreate,
+				Key:    key,
+				Spec:   spec,
+			})
+			continue
+		}
+		matched[current.ID] = true
+
+		currentSpec, err := utils.WorkflowToSpec(*current)
+		if err != nil {
+			return nil, fmt.Errorf("plan workflows error: %w", err)
+		}
+		diffs, err := diffWorkflowSpecs(currentSpec, *spec)
+		if err != nil {
+			return nil, fmt.Errorf("plan workflows error: %w", err)
+		}
+
+		change := models.WorkflowPlanChange{
+			Action:     models.WorkflowPlanActionNoop,
+			Key:        key,
+			WorkflowID: current.ID,
+			Spec:       spec,
+			Current:    current,
+			Diffs:      diffs,
+		}
+		if len(diffs) > 0 {
+			change.Action = models.WorkflowPlanActionUpdate
+		}
+		for _, diff := range diffs {
+			if diff.ForcesReplacement {
+				change.Action = models.WorkflowPlanActionReplace
+			}
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+
+	// Delete managed workflows that are no longer declared, and optionally unmanaged ones
+	var deletes []models.WorkflowPlanChange
+	for i := range workflows {
+		workflow := &workflows[i]
+		if matched[workflow.ID] {
+			continue
+		}
+		key, _ := utils.WorkflowSpecKey(workflow.Documentation)
+		if key == "" && !opts.PruneUnmanaged {
+			continue
+		}
+		if key == "" {
+			key = workflow.Name
+		}
+		deletes = append(deletes, models.WorkflowPlanChange{
+			Action:     models.WorkflowPlanActionDelete,
+			Key:        key,
+			WorkflowID: workflow.ID,
+			Current:    workflow,
+		})
+	}
+	sort.Slice(deletes, func(i, j int) bool { return deletes[i].Key < deletes[j].Key })
+	plan.Changes = append(plan.Changes, deletes...)
+
+	return plan, nil
+}
+
+// Apply applies a plan and returns the created or updated workflows.
+// It stops at the first failing change and returns the workflows applied so far.
+func (r *WorkflowReconciler) Apply(ctx context.Context, plan *models.WorkflowPlan) ([]models.Workflow, error) {
+	workflows := NewWorkflowService(r.client.WithContext(ctx))
+	var applied []models.Workflow
+
+	for _, change := range plan.Changes {
+		switch change.Action {
+		case models.WorkflowPlanActionNoop:
+			continue
+
+		case models.WorkflowPlanActionCreate:
+			workflow, err := createWorkflowFromSpec(workflows, change.Spec)
+			if err != nil {
+				return applied, fmt.Errorf("create workflow %q error: %w", change.Key, err)
+			}
+			applied = append(applied, *workflow)
+
+		case models.WorkflowPlanActionUpdate:
+			workflow, err := updateWorkflowFromSpec(workflows, change.WorkflowID, change.Key, change.Spec)
+			if err != nil {
+				return applied, fmt.Errorf("update workflow %q error: %w", change.Key, err)
+			}
+			applied = append(applied, *workflow)
+
+		case models.WorkflowPlanActionReplace:
+			// The new workflow is created under a temporary name first, so the current one is kept if
+			// that fails, and renamed once the current one is deleted, so no two workflows share a name
+			temporary := *change.Spec
+			temporary.Key = change.Key
+			temporary.Name = change.Spec.Name + " (replacement)"
+			created, err := createWorkflowFromSpec(workflows, &temporary)
+			if err != nil {
+				return applied, fmt.Errorf("replace workflow %q error: %w", change.Key, err)
+			}
+			if _, err := workflows.DeleteWorkflow(change.WorkflowID); err != nil {
+				return applied, fmt.Errorf("replace workflow %q error: created %s but failed to delete %s: %w", change.Key, created.ID, change.WorkflowID, err)
+			}
+			workflow, err := updateWorkflowFromSpec(workflows, created.ID, change.Key, change.Spec)
+			if err != nil {
+				return applied, fmt.Errorf("replace workflow %q error: deleted %s but failed to rename %s: %w", change.Key, change.WorkflowID, created.ID, err)
+			}
+			applied = append(applied, *workflow)
+
+		case models.WorkflowPlanActionDelete:
+			if _, err := workflows.DeleteWorkflow(change.WorkflowID); err != nil {
+				return applied, fmt.Errorf("delete workflow %q error: %w", change.Key, err)
+			}
+
+		default:
+			return applied, fmt.Errorf("unknown plan action: %s", change.Action)
+		}
+	}
+	return applied, nil
+}
+
+// updateWorkflowFromSpec updates the name, description, documentation and schedule of a workflow
+func updateWorkflowFromSpec(workflows *WorkflowService, workflowID, key string, spec *models.WorkflowSpec) (*models.Workflow, error) {
+	schedule, err := utils.WorkflowSpecSchedule(spec.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	workflow, _, err := workflows.UpdateWorkflow(
+		workflowID,
+		spec.Name,
+		spec.Description,
+		utils.WithWorkflowSpecKey(spec.Documentation, key),
+		schedule,
+	)
+	return workflow, err
+}
+
+// createWorkflowFromSpec creates a managed workflow, storing the spec key in its documentation
+func createWorkflowFromSpec(workflows *WorkflowService, spec *models.WorkflowSpec) (*models.Workflow, error) {
+	return createWorkflow(workflows, spec, utils.WithWorkflowSpecKey(spec.Documentation, spec.SpecKey()))
+}
+
+// createWorkflow creates a workflow through the matching Create*Workflow call
+func createWorkflow(workflows *WorkflowService, spec *models.WorkflowSpec, documentation string) (*models.Workflow, error) {
+	schedule, err := utils.WorkflowSpecSchedule(spec.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	var workflow *models.Workflow
+	switch spec.Type {
+	case models.WorkflowableTypeImport:
+		workflow, _, err = workflows.CreateImportWorkflow(
+			spec.Import.Connection,
+			spec.Import.Repository,
+			spec.Import.Branch,
+			spec.Import.Path,
+			spec.Name,
+			spec.Description,
+			documentation,
+			schedule,
+		)
+	case models.WorkflowableTypeExport:
+		workflow, _, err = workflows.CreateExportWorkflow(
+			spec.Export.Connection,
+			spec.Export.Repository,
+			spec.Export.Path,
+			spec.Export.Branch,
+			spec.Export.Recursive,
+			spec.Name,
+			spec.Description,
+			documentation,
+			schedule,
+		)
+	case models.WorkflowableTypeAction:
+		workflow, _, err = workflows.CreateActionWorkflow(
+			spec.Action.Executable,
+			spec.Action.Repository,
+			spec.Action.Branch,
+			spec.Action.Path,
+			spec.Name,
+			spec.Description,
+			documentation,
+			schedule,
+		)
+	case models.WorkflowableTypePipeline:
+		stages, stagesErr := utils.WorkflowSpecStages(spec.Pipeline.Stages)
+		if stagesErr != nil {
+			return nil, stagesErr
+		}
+		workflow, _, err = workflows.CreatePipelineWorkflow(
+			stages,
+			spec.Pipeline.Live,
+			spec.Name,
+			spec.Description,
+			documentation,
+			schedule,
+		)
+	default:
+		return nil, fmt.Errorf("unknown workflow type: %s", spec.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return workflow, nil
+}
+
+// updatableWorkflowFields are the top-level spec fields UpdateWorkflow can change in place
+var updatableWorkflowFields = map[string]bool{
+	"key":           true,
+	"name":          true,
+	"description":   true,
+	"documentation": true,
+	"schedule":      true,
+}
+
+// diffWorkflowSpecs compares two specs field by field
+func diffWorkflowSpecs(current, declared models.WorkflowSpec) ([]models.WorkflowFieldDiff, error) {
+	declared.Key = declared.SpecKey()
+
+	currentFields, err := flattenSpec(current)
+	if err != nil {
+		return nil, err
+	}
+	declaredFields, err := flattenSpec(declared)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]bool)
+	for field := range currentFields {
+		fields[field] = true
+	}
+	for field := range declaredFields {
+		fields[field] = true
+	}
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	var diffs []models.WorkflowFieldDiff
+	for _, field := range names {
+		oldValue := currentFields[field]
+		newValue, declared := declaredFields[field]
+		if oldValue == newValue {
+			continue
+		}
+		root := strings.FieldsFunc(field, func(r rune) bool { return r == '.' || r == '[' })[0]
+		// UpdateWorkflow only sends the schedule fields that are set, so removed ones cannot be cleared in place
+		removedScheduleField := root == "schedule" && !declared
+		diffs = append(diffs, models.WorkflowFieldDiff{
+			Field:             field,
+			Old:               oldValue,
+			New:               newValue,
+			ForcesReplacement: !updatableWorkflowFields[root] || removedScheduleField,
+		})
+	}
+	return diffs, nil
+}
+
+// flattenSpec flattens a spec into dotted field paths and their values
+func flattenSpec(spec models.WorkflowSpec) (map[string]string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var tree interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]string)
+	var walk func(prefix string, value interface{})
+	walk = func(prefix string, value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for key, child := range v {
+				if prefix == "" {
+					walk(key, child)
+				} else {
+					walk(prefix+"."+key, child)
+				}
+			}
+		case []interface{}:
+			for i, child := range v {
+				walk(fmt.Sprintf("%s[%d]", prefix, i), child)
+			}
+		case nil:
+		case string:
+			if v != "" {
+				fields[prefix] = v
+			}
+		case bool:
+			if v {
+				fields[prefix] = "true"
+			}
+		default:
+			fields[prefix] = fmt.Sprint(v)
+		}
+	}
+	walk("", tree)
+	return fields, nil
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+func TestDiffWorkflowSpecsScheduleChanges(t *testing.T) {
+	retries := 3
+	daily := models.WorkflowTriggerSpec{Type: "time", RRule: "FREQ=DAILY"}
+	hourly := models.WorkflowTriggerSpec{Type: "time", RRule: "FREQ=HOURLY"}
+	spec := func(schedule *models.WorkflowScheduleSpec) models.WorkflowSpec {
+		return models.WorkflowSpec{
+			Key:      "nightly",
+			Name:     "Nightly",
+			Type:     models.WorkflowableTypeAction,
+			Action:   &models.ActionSpec{Executable: "exe-1"},
+			Schedule: schedule,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		current     *models.WorkflowScheduleSpec
+		declared    *models.WorkflowScheduleSpec
+		replacement bool
+	}{
+		{
+			name:     "schedule added",
+			declared: &models.WorkflowScheduleSpec{Triggers: []models.WorkflowTriggerSpec{daily}},
+		},
+		{
+			name:     "trigger changed",
+			current:  &models.WorkflowScheduleSpec{Triggers: []models.WorkflowTriggerSpec{daily}},
+			declared: &models.WorkflowScheduleSpec{Triggers: []models.WorkflowTriggerSpec{hourly}},
+		},
+		{
+			name:        "schedule removed",
+			current:     &models.WorkflowScheduleSpec{Triggers: []models.WorkflowTriggerSpec{daily}, MaxRetries: &retries},
+			replacement: true,
+		},
+		{
+			name:        "trigger removed",
+			current:     &models.WorkflowScheduleSpec{Triggers: []models.WorkflowTriggerSpec{daily, hourly}},
+			declared:    &models.WorkflowScheduleSpec{Triggers: []models.WorkflowTriggerSpec{daily}},
+			replacement: true,
+		},
+		{
+			name:        "retries removed",
+			current:     &models.WorkflowScheduleSpec{Triggers: []models.WorkflowTriggerSpec{daily}, MaxRetries: &retries},
+			declared:    &models.WorkflowScheduleSpec{Triggers: []models.WorkflowTriggerSpec{daily}},
+			replacement: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diffs, err := diffWorkflowSpecs(spec(tt.current), spec(tt.declared))
+			if err != nil {
+				t.Fatalf("diffing specs: %v", err)
+			}
+			if len(diffs) == 0 {
+				t.Fatal("expected differences")
+			}
+			replacement := slices.ContainsFunc(diffs, func(diff models.WorkflowFieldDiff) bool { return diff.ForcesReplacement })
+			if replacement != tt.replacement {
+				t.Errorf("got forces replacement %t, expected %t: %+v", replacement, tt.replacement, diffs)
+			}
+		})
+	}
+}
+
+func TestApplyReplace(t *testing.T) {
+	tests := []struct {
+		name     string
+		failCall string
+		// Expected calls, with the workflow name sent by creates and updates
+		expected  []string
+		wantError bool
+	}{
+		{
+			name:     "success",
+			expected: []string{"POST /v1/workflows/actions Nightly (replacement)", "DELETE /v1/workflows/old", "PATCH /v1/workflows/new Nightly"},
+		},
+		{
+			name:      "failed create keeps the current workflow",
+			failCall:  "POST /v1/workflows/actions",
+			expected:  []string{"POST /v1/workflows/actions Nightly (replacement)"},
+			wantError: true,
+		},
+		{
+			name:      "failed delete keeps the temporary name",
+			failCall:  "DELETE /v1/workflows/old",
+			expected:  []string{"POST /v1/workflows/actions Nightly (replacement)", "DELETE /v1/workflows/old"},
+			wantError: true,
+		},
+		{
+			name:      "failed rename",
+			failCall:  "PATCH /v1/workflows/new",
+			expected:  []string{"POST /v1/workflows/actions Nightly (replacement)", "DELETE /v1/workflows/old", "PATCH /v1/workflows/new Nightly"},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+				method := r.Method
+				if override := r.PostForm.Get("_method"); override != "" {
+					method = override
+				}
+				call := method + " " + r.URL.Path
+				if name := r.PostForm.Get("name"); name != "" {
+					calls = append(calls, call+" "+name)
+				} else {
+					calls = append(calls, call)
+				}
+				if call == tt.failCall {
+					http.Error(w, "unavailable", http.StatusServiceUnavailable)
+					return
+				}
+				json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": "new", "name": r.PostForm.Get("name")}})
+			}))
+			defer server.Close()
+
+			plan := &models.WorkflowPlan{Changes: []models.WorkflowPlanChange{{
+				Action:     models.WorkflowPlanActionReplace,
+				Key:        "nightly",
+				WorkflowID: "old",
+				Spec:       &models.WorkflowSpec{Key: "nightly", Name: "Nightly", Type: models.WorkflowableTypeAction, Action: &models.ActionSpec{Executable: "exe-1"}},
+			}}}
+			applied, err := NewWorkflowReconciler(client.NewClient(server.URL, "token", "en")).Apply(context.Background(), plan)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("got error %v, expected error %t", err, tt.wantError)
+			}
+			if !slices.Equal(calls, tt.expected) {
+				t.Errorf("got calls %q, expected %q", calls, tt.expected)
+			}
+			if !tt.wantError && (len(applied) != 1 || applied[0].Name != "Nightly") {
+				t.Errorf("got applied workflows %+v, expected the renamed replacement", applied)
+			}
+		})
+	}
+}
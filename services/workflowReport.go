@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/utils"
+)
+
+// WorkflowReportOptions configures which workflows a run report covers
+type WorkflowReportOptions struct {
+	// IDs of the workflows to report on. Defaults to every workflow of the workspace
+	WorkflowIDs []string
+	// Time after a scheduled occurrence within which a run must start. Defaults to the time until the next occurrence
+	Tolerance time.Duration
+}
+
+// WorkflowReporter reports on the run history of workflows
+type WorkflowReporter struct {
+	client *client.Client
+}
+
+// NewWorkflowReporter creates a new WorkflowReporter
+func NewWorkflowReporter(client *client.Client) *WorkflowReporter {
+	return &WorkflowReporter{
+		client: client,
+	}
+}
+
+// Report collects the runs and log events of workflows and computes their statistics for runs started in [start, end).
+// Use utils.WriteWorkflowRunReportJSON or utils.WriteWorkflowRunReportCSV to export the report.
+func (r *WorkflowReporter) Report(ctx context.Context, start, end time.Time, opts WorkflowReportOptions) (*models.WorkflowRunReport, error) {
+	if !start.Before(end) {
+		return nil, fmt.Errorf("report window start %s is not before its end %s", start, end)
+	}
+
+	apiClient := r.client.WithContext(ctx)
+	workflowService := NewWorkflowService(apiClient)
+	logService := NewLogService(apiClient)
+
+	workflows, _, err := workflowService.FetchWorkflows()
+	if err != nil {
+		return nil, err
+	}
+	if len(opts.WorkflowIDs) > 0 {
+		for _, id := range opts.WorkflowIDs {
+			if !slices.ContainsFunc(workflows, func(w models.Workflow) bool { return w.ID == id }) {
+				return nil, fmt.Errorf("workflow %q not found", id)
+			}
+		}
+		workflows = slices.DeleteFunc(workflows, func(w models.Workflow) bool {
+			return !slices.Contains(opts.WorkflowIDs, w.ID)
+		})
+	}
+
+	report := &models.WorkflowRunReport{
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		WindowStart: start.UTC().Format(time.RFC3339),
+		WindowEnd:   end.UTC().Format(time.RFC3339),
+		Workflows:   make([]models.WorkflowRunStats, 0, len(workflows)),
+	}
+	for _, workflow := range workflows {
+		runs, _, err := workflowService.ListWorkflowRuns(workflow.ID)
+		if err != nil {
+			return nil, err
+		}
+		events, _, err := logService.FetchWorkflowLogEvents(workflow.ID)
+		if err != nil {
+			return nil, err
+		}
+		stats, err := utils.ComputeWorkflowRunStats(workflow, runs, events, start, end, opts.Tolerance)
+		if err != nil {
+			return nil, err
+		}
+		report.Workflows = append(report.Workflows, stats)
+	}
+	return report, nil
+}
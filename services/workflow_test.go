@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/client"
+)
+
+func TestTriggerAndWait(t *testing.T) {
+	tests := []struct {
+		name string
+		// Responses of each endpoint in order, the last one is repeated
+		responses map[string][]string
+		// Expected ID of the returned run, if any
+		expectedRun string
+		// Expected log lines of a *WorkflowRunError, or an empty slice if no error is expected
+		expectedLogs []string
+		expectedErr  bool
+	}{
+		{
+			name: "run returned by the trigger",
+			responses: map[string][]string{
+				"/v1/workflows/w1/runs":    {`[]`},
+				"/v1/workflows/w1/run":     {`{"id": "r2", "status": "pending"}`},
+				"/v1/workflows/w1/runs/r2": {`{"id": "r2", "status": "running"}`, `{"id": "r2", "status": "complete"}`},
+			},
+			expectedRun: "r2",
+		},
+		{
+			name: "run missing from the trigger",
+			responses: map[string][]string{
+				"/v1/workflows/w1/runs": {
+					`[{"id": "r1", "status": "complete", "started_at": "2026-01-02 00:00:00"}]`,
+					`[{"id": "r1", "status": "complete", "started_at": "2026-01-02 00:00:00"}]`,
+					`[{"id": "r1", "status": "complete", "started_at": "2026-01-02 00:00:00"}, {"id": "r2", "status": "pending", "started_at": "2026-01-01 00:00:00"}]`,
+				},
+				"/v1/workflows/w1/run":     {`{}`},
+				"/v1/workflows/w1/runs/r2": {`{"id": "r2", "status": "complete"}`},
+			},
+			expectedRun: "r2",
+		},
+		{
+			name: "failed run",
+			responses: map[string][]string{
+				"/v1/workflows/w1/runs":         {`[]`},
+				"/v1/workflows/w1/run":          {`{"id": "r2", "status": "running"}`},
+				"/v1/workflows/w1/runs/r2":      {`{"id": "r2", "status": "error"}`},
+				"/v1/workflows/w1/runs/r2/logs": {`{"logs": ["starting", "division by zero"]}`},
+			},
+			expectedRun:  "r2",
+			expectedLogs: []string{"starting", "division by zero"},
+			expectedErr:  true,
+		},
+		{
+			name: "paused run",
+			responses: map[string][]string{
+				"/v1/workflows/w1/runs":    {`[]`},
+				"/v1/workflows/w1/run":     {`{"id": "r2", "status": "pending"}`},
+				"/v1/workflows/w1/runs/r2": {`{"id": "r2", "status": "paused"}`},
+			},
+			expectedRun: "r2",
+			expectedErr: true,
+		},
+		{
+			name: "run never created",
+			responses: map[string][]string{
+				"/v1/workflows/w1/runs": {`[{"id": "r1", "status": "complete"}]`},
+				"/v1/workflows/w1/run":  {`{}`},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "trigger failed",
+			responses: map[string][]string{
+				"/v1/workflows/w1/runs": {`[]`},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				responses, ok := tt.responses[r.URL.Path]
+				if !ok {
+					http.Error(w, `{"message": "not found"}`, http.StatusNotFound)
+					return
+				}
+				io.WriteString(w, `{"data": `+responses[0]+`}`)
+				if len(responses) > 1 {
+					tt.responses[r.URL.Path] = responses[1:]
+				}
+			}))
+			defer server.Close()
+
+			workflows := NewWorkflowService(client.NewClient(server.URL, "token", "en"))
+			run, err := workflows.TriggerAndWait(context.Background(), "w1", &TriggerAndWaitOptions{
+				Poll:         &PollOptions{Interval: time.Millisecond},
+				QueueTimeout: 50 * time.Millisecond,
+			})
+
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("got error %v, expected error: %v", err, tt.expectedErr)
+			}
+			runID := ""
+			if run != nil {
+				runID = run.ID
+			}
+			if runID != tt.expectedRun {
+				t.Errorf("got run %q, expected %q", runID, tt.expectedRun)
+			}
+
+			var runErr *WorkflowRunError
+			if errors.As(err, &runErr) != (tt.expectedLogs != nil) {
+				t.Fatalf("got error %v, expected a *WorkflowRunError: %v", err, tt.expectedLogs != nil)
+			}
+			if runErr != nil && !slices.Equal(runErr.Logs, tt.expectedLogs) {
+				t.Errorf("got logs %q, expected %q", runErr.Logs, tt.expectedLogs)
+			}
+		})
+	}
+}
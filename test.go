@@ -36,6 +36,20 @@ func main() {
 		log.Println("Running utility tests...")
 		examples.TestParquetUtils()
 		examples.TestSchemaUtils()
+		examples.TestQueryBuilder()
+		examples.TestRRule()
+		examples.TestWorkflowGraph()
+		examples.TestLineage()
+		examples.TestPipelineValidation()
+		examples.TestWorkflowBundle()
+		examples.TestWorkflowReport()
+		examples.TestActionRunner()
+		examples.TestRowDiff()
+		examples.TestMerge()
+		examples.TestCommitGraph()
+		examples.TestRefExpression()
+		examples.TestBlameRows()
+		examples.TestSnapshotArchive()
 	}
 
 	// API tests
@@ -58,9 +72,11 @@ func main() {
 		examples.TestConnectors(baseURL, apiToken, locale)
 		examples.TestConnections(*connectionID, baseURL, apiToken, locale)
 		examples.TestWorkflows(*connectionID, baseURL, apiToken, locale)
+		examples.TestWorkflowSpec(*connectionID, baseURL, apiToken, locale)
 		examples.TestRepositories(baseURL, apiToken, locale)
 		examples.TestEditorItems(baseURL, apiToken, locale)
 		examples.TestVersioningAndObjects(baseURL, apiToken, locale)
+		examples.TestRepositoryFS(baseURL, apiToken, locale)
 		examples.TestLogs(baseURL, apiToken, locale)
 
 		// Clean up and delete the example objects
@@ -0,0 +1,226 @@
+package utils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ArchiveFormat represents the formats of downloaded repository archives
+type ArchiveFormat string
+
+const (
+	ArchiveFormatZip   ArchiveFormat = "zip"
+	ArchiveFormatTarGz ArchiveFormat = "tar.gz"
+	ArchiveFormatTar   ArchiveFormat = "tar"
+)
+
+// DetectArchiveFormat detects the format of an archive from its first 512 bytes.
+func DetectArchiveFormat(header []byte) (ArchiveFormat, error) {
+	switch {
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")), bytes.HasPrefix(header, []byte("PK\x05\x06")):
+		return ArchiveFormatZip, nil
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}):
+		return ArchiveFormatTarGz, nil
+	case len(header) >= 262 && string(header[257:262]) == "ustar":
+		return ArchiveFormatTar, nil
+	}
+	return "", errors.New("unknown archive format")
+}
+
+// archiveEntry is a file or directory of an archive
+type archiveEntry struct {
+	// Cleaned slash-separated path of the entry, relative to the archive root
+	name    string
+	dir     bool
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// archivePath cleans the path of an archive entry and rejects paths that would escape the archive root,
+// such as absolute paths or paths with `..` elements, so archives cannot write outside their destination
+func archivePath(name string) (string, error) {
+	cleaned := path.Clean(strings.TrimPrefix(name, "./"))
+	if cleaned == "." {
+		return cleaned, nil
+	}
+	if !fs.ValidPath(cleaned) || strings.Contains(cleaned, `\`) || !filepath.IsLocal(filepath.FromSlash(cleaned)) {
+		return "", fmt.Errorf("unsafe archive path %q", name)
+	}
+	return cleaned, nil
+}
+
+// walkArchive calls fn with every entry of an archive and a reader of its content, which is empty for
+// directories. Links and other special entries are rejected. fn must consume the content before returning.
+func walkArchive(r io.ReaderAt, size int64, fn func(entry archiveEntry, content io.Reader) error) (ArchiveFormat, error) {
+	header := make([]byte, 512)
+	n, err := r.ReadAt(header, 0)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read archive: %w", err)
+	}
+	format, err := DetectArchiveFormat(header[:n])
+	if err != nil {
+		return "", err
+	}
+
+	if format == ArchiveFormatZip {
+		zr, err := zip.NewReader(r, size)
+		if err != nil {
+			return format, fmt.Errorf("failed to open zip archive: %w", err)
+		}
+		for _, file := range zr.File {
+			name, err := archivePath(file.Name)
+			if err != nil {
+				return format, err
+			}
+			mode := file.Mode()
+			entry := archiveEntry{name: name, dir: mode.IsDir(), mode: mode.Perm(), modTime: file.Modified}
+			if !mode.IsDir() && !mode.IsRegular() {
+				return format, fmt.Errorf("unsupported archive entry %q of type %s", file.Name, mode.Type())
+			}
+			if entry.dir {
+				if err := fn(entry, bytes.NewReader(nil)); err != nil {
+					return format, err
+				}
+				continue
+			}
+			content, err := file.Open()
+			if err != nil {
+				return format, fmt.Errorf("failed to read %s: %w", file.Name, err)
+			}
+			err = fn(entry, content)
+			content.Close()
+			if err != nil {
+				return format, err
+			}
+		}
+		return format, nil
+	}
+
+	var stream io.Reader = io.NewSectionReader(r, 0, size)
+	if format == ArchiveFormatTarGz {
+		gz, err := gzip.NewReader(stream)
+		if err != nil {
+			return format, fmt.Errorf("failed to open gzip stream: %w", err)
+		}
+		defer gz.Close()
+		stream = gz
+	}
+	tr := tar.NewReader(stream)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return format, fmt.Errorf("failed to read tar archive: %w", err)
+		}
+		switch h.Typeflag {
+		case tar.TypeReg, tar.TypeDir:
+		case tar.TypeXGlobalHeader:
+			continue
+		default:
+			return format, fmt.Errorf("unsupported archive entry %q of type %q", h.Name, h.Typeflag)
+		}
+		name, err := archivePath(h.Name)
+		if err != nil {
+			return format, err
+		}
+		entry := archiveEntry{name: name, dir: h.Typeflag == tar.TypeDir, mode: fs.FileMode(h.Mode).Perm(), modTime: h.ModTime}
+		if err := fn(entry, tr); err != nil {
+			return format, err
+		}
+	}
+	// Read the gzip trailer, so its checksum is verified
+	if _, err := io.Copy(io.Discard, stream); err != nil {
+		return format, fmt.Errorf("failed to read archive: %w", err)
+	}
+	return format, nil
+}
+
+// VerifyArchive reads every entry of a zip, tar.gz or tar archive, verifying its checksums and that
+// none of its paths escape the archive root.
+func VerifyArchive(r io.ReaderAt, size int64) (ArchiveFormat, error) {
+	return walkArchive(r, size, func(entry archiveEntry, content io.Reader) error {
+		if _, err := io.Copy(io.Discard, content); err != nil {
+			return fmt.Errorf("failed to read %s: %w", entry.name, err)
+		}
+		return nil
+	})
+}
+
+// ExtractArchive verifies a zip, tar.gz or tar archive and extracts it into a directory, creating it if
+// needed. Entry paths that would escape the directory, links and special files are rejected before
+// anything is written. It returns the slash-separated paths of the extracted files.
+func ExtractArchive(r io.ReaderAt, size int64, dest string) ([]string, error) {
+	if _, err := VerifyArchive(r, size); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dest, err)
+	}
+
+	files := []string{}
+	_, err := walkArchive(r, size, func(entry archiveEntry, content io.Reader) error {
+		target := filepath.Join(dest, filepath.FromSlash(entry.name))
+		if entry.dir {
+			return os.MkdirAll(target, 0o755)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		mode := entry.mode
+		if mode == 0 {
+			mode = 0o644
+		}
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(file, content); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to extract %s: %w", entry.name, err)
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+		files = append(files, entry.name)
+		return nil
+	})
+	if err != nil {
+		return files, err
+	}
+	return files, nil
+}
+
+// ArchiveFS reads a zip, tar.gz or tar archive into a read-only in-memory file system.
+// Entry paths that would escape the archive root, links and special files are rejected.
+func ArchiveFS(data []byte) (fs.FS, error) {
+	fsys := newMemFS()
+	_, err := walkArchive(bytes.NewReader(data), int64(len(data)), func(entry archiveEntry, content io.Reader) error {
+		if entry.dir {
+			fsys.addDir(entry.name, entry.modTime)
+			return nil
+		}
+		data, err := io.ReadAll(content)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", entry.name, err)
+		}
+		fsys.addFile(entry.name, data, entry.mode, entry.modTime)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fsys, nil
+}
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+var archiveFiles = map[string]string{
+	"cities/norway.csv": "id,name\n1,Oslo\n2,Bergen\n",
+	"cities/sweden.csv": "id,name\n1,Stockholm\n",
+	"README.md":         "# Cities\n",
+}
+
+var archiveNames = []string{"README.md", "cities/norway.csv", "cities/sweden.csv"}
+
+func zipArchive(t *testing.T, entries map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range slices.Sorted(maps.Keys(entries)) {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(entries[name]))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func tarGzArchive(t *testing.T, entries map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gz)
+	for _, name := range slices.Sorted(maps.Keys(entries)) {
+		if err := w.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(entries[name])), ModTime: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(entries[name]))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestArchiveFS(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "zip", data: zipArchive(t, archiveFiles)},
+		{name: "tar.gz", data: tarGzArchive(t, archiveFiles)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys, err := ArchiveFS(tt.data)
+			if err != nil {
+				t.Fatalf("reading archive: %v", err)
+			}
+			var walked []string
+			err = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+				if err == nil && !d.IsDir() {
+					walked = append(walked, p)
+				}
+				return err
+			})
+			if err != nil || !slices.Equal(walked, archiveNames) {
+				t.Errorf("got files %q (%v), expected %q", walked, err, archiveNames)
+			}
+			content, err := fs.ReadFile(fsys, "cities/norway.csv")
+			if err != nil || string(content) != archiveFiles["cities/norway.csv"] {
+				t.Errorf("got content %q (%v), expected %q", content, err, archiveFiles["cities/norway.csv"])
+			}
+			// Directories are implied by the paths of the files in them
+			if info, err := fs.Stat(fsys, "cities"); err != nil || !info.IsDir() {
+				t.Errorf("got %v (%v), expected a directory", info, err)
+			}
+		})
+	}
+}
+
+func TestExtractArchive(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "extract")
+	data := tarGzArchive(t, archiveFiles)
+	extracted, err := ExtractArchive(bytes.NewReader(data), int64(len(data)), dest)
+	if err != nil {
+		t.Fatalf("extracting archive: %v", err)
+	}
+	if !slices.Equal(extracted, archiveNames) {
+		t.Errorf("got files %q, expected %q", extracted, archiveNames)
+	}
+	content, err := os.ReadFile(filepath.Join(dest, "cities", "sweden.csv"))
+	if err != nil || string(content) != archiveFiles["cities/sweden.csv"] {
+		t.Errorf("got content %q (%v), expected %q", content, err, archiveFiles["cities/sweden.csv"])
+	}
+}
+
+func TestExtractArchiveRejectsUnsafeArchives(t *testing.T) {
+	complete := zipArchive(t, archiveFiles)
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "parent path", data: zipArchive(t, map[string]string{"ok.txt": "ok", "../escaped.txt": "escaped"})},
+		{name: "absolute path", data: tarGzArchive(t, map[string]string{"/tmp/escaped.txt": "escaped"})},
+		{name: "corrupt data", data: complete[:len(complete)-30]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			dest := filepath.Join(dir, "extract")
+			if _, err := ExtractArchive(bytes.NewReader(tt.data), int64(len(tt.data)), dest); err == nil {
+				t.Error("expected the archive to be rejected")
+			}
+			// Nothing is extracted, not even the safe entries
+			if _, err := os.Stat(filepath.Join(dest, "ok.txt")); err == nil {
+				t.Error("archive was partially extracted")
+			}
+			if _, err := os.Stat(filepath.Join(dir, "escaped.txt")); err == nil {
+				t.Error("archive entry escaped its destination")
+			}
+		})
+	}
+}
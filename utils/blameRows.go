@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// BlameRows attributes every row of the latest version of a structured object to the change that last
+// modified it. versions holds the object after every change, newest first, followed by the object before
+// the oldest change, which is empty if the oldest change created the object. It returns the key of every
+// row of versions[0] and the index of the change that last added or modified it, or -1 if the row is
+// unchanged since the oldest version. Rows are aligned by the key columns, or by position without key
+// columns or if a key column is missing from any non-empty version.
+func BlameRows(versions []*models.QueryResultTable, keyColumns []string) (keys []string, origins []int, positional bool) {
+	if len(versions) == 0 {
+		return []string{}, []int{}, len(keyColumns) == 0
+	}
+	positional = len(keyColumns) == 0
+	for _, version := range versions {
+		if len(version.Rows) == 0 {
+			continue
+		}
+		index := columnIndexes(version.Columns)
+		for _, column := range keyColumns {
+			if _, ok := index[column]; !ok {
+				positional = true
+			}
+		}
+	}
+
+	keys = []string{}
+	seen := make(map[string]int, len(versions[0].Rows))
+	for _, key := range tableRowKeys(versions[0], keyColumns, positional) {
+		keys = append(keys, uniqueRowKey(seen, key))
+	}
+	origins = make([]int, len(keys))
+	for i := range origins {
+		origins[i] = -1
+	}
+
+	pending := len(keys)
+	for i := 0; i+1 < len(versions) && pending > 0; i++ {
+		newer, older := versions[i], versions[i+1]
+		newerRows := keyedRows(newer, keyColumns, positional)
+		olderRows := keyedRows(older, keyColumns, positional)
+		newerIndex := columnIndexes(newer.Columns)
+		olderIndex := columnIndexes(older.Columns)
+		columns := append(append([]string{}, newer.Columns...), older.Columns...)
+
+		for j, key := range keys {
+			if origins[j] >= 0 {
+				continue
+			}
+			n := newerRows[key]
+			o, ok := olderRows[key]
+			changed := !ok
+			for _, column := range columns {
+				if changed {
+					break
+				}
+				changed = !rowValuesEqual(blameValue(newer, newerIndex, n, column), blameValue(older, olderIndex, o, column))
+			}
+			if changed {
+				origins[j] = i
+				pending--
+			}
+		}
+	}
+	return keys, origins, positional
+}
+
+// blameValue returns the value of a column of a row, or nil if the table has no such column
+func blameValue(table *models.QueryResultTable, index map[string]int, row int, column string) models.JSONValue {
+	if i, ok := index[column]; ok && i < len(table.Rows[row]) {
+		return table.Rows[row][i]
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+func TestBlameRows(t *testing.T) {
+	empty := "id,name,population\n"
+	// Newest first: change 0 updates Oslo, change 1 adds Stavanger, change 2 creates the object
+	history := []string{
+		"id,name,population\n1,Oslo,717000\n2,Bergen,286000\n3,Stavanger,146000\n",
+		"id,name,population\n1,Oslo,709000\n2,Bergen,286000\n3,Stavanger,146000\n",
+		"id,name,population\n1,Oslo,709000\n2,Bergen,286000\n",
+		empty,
+	}
+
+	tests := []struct {
+		name       string
+		versions   []string
+		key        []string
+		origins    []int
+		positional bool
+	}{
+		{name: "by key", versions: history, key: []string{"id"}, origins: []int{0, 2, 1}},
+		// Rows unchanged since the oldest version known are not attributed
+		{name: "truncated history", versions: history[:3], key: []string{"id"}, origins: []int{0, -1, 1}},
+		{
+			name: "by position",
+			versions: []string{
+				history[0],
+				"id,name,population\n2,Bergen,286000\n1,Oslo,709000\n3,Stavanger,146000\n",
+				history[2],
+				empty,
+			},
+			origins:    []int{0, 0, 1},
+			positional: true,
+		},
+		{
+			// Repeated keys are aligned by occurrence
+			name: "duplicate keys",
+			versions: []string{
+				"id,name,population\n1,Oslo,709000\n1,Oslo,717000\n",
+				"id,name,population\n1,Oslo,709000\n",
+				empty,
+			},
+			key:     []string{"id"},
+			origins: []int{1, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var versions []*models.QueryResultTable
+			for _, version := range tt.versions {
+				versions = append(versions, decodeTestTable(t, []byte(version), TableFormatCSV))
+			}
+			keys, origins, positional := BlameRows(versions, tt.key)
+			if len(keys) != len(origins) {
+				t.Errorf("got %d keys for %d origins", len(keys), len(origins))
+			}
+			if positional != tt.positional || !slices.Equal(origins, tt.origins) {
+				t.Errorf("got origins %v (positional %t), expected %v (positional %t)", origins, positional, tt.origins, tt.positional)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// CommitLookup returns the commit with a hash
+type CommitLookup func(hash string) (*models.CommitNode, error)
+
+// ParseCommit parses the timestamp of a commit. The time is left zero if the timestamp is invalid.
+func ParseCommit(commit models.Commit) models.CommitNode {
+	node := models.CommitNode{Commit: commit}
+	if t, err := ParseAPITime(commit.Timestamp); err == nil {
+		node.Time = t
+	}
+	return node
+}
+
+// CommitAncestors returns an iterator over a commit and its ancestors, newest first, following the
+// previous hash of every commit. Commits are looked up as the iteration reaches them.
+// Iteration stops after the first commit, at the first error or if the history has a cycle.
+func CommitAncestors(head string, lookup CommitLookup) iter.Seq2[models.CommitNode, error] {
+	return func(yield func(models.CommitNode, error) bool) {
+		seen := make(map[string]bool)
+		for hash := head; hash != ""; {
+			if seen[hash] {
+				yield(models.CommitNode{}, fmt.Errorf("commit history has a cycle at %s", hash))
+				return
+			}
+			seen[hash] = true
+
+			commit, err := lookup(hash)
+			if err != nil {
+				yield(models.CommitNode{}, err)
+				return
+			}
+			if !yield(*commit, nil) {
+				return
+			}
+			hash = ""
+			if commit.PreviousHash != nil {
+				hash = *commit.PreviousHash
+			}
+		}
+	}
+}
+
+// commitHashes returns the hashes of a commit and all its ancestors
+func commitHashes(head string, lookup CommitLookup) (map[string]bool, error) {
+	hashes := make(map[string]bool)
+	for commit, err := range CommitAncestors(head, lookup) {
+		if err != nil {
+			return nil, err
+		}
+		hashes[commit.Hash] = true
+	}
+	return hashes, nil
+}
+
+// MergeBase returns the latest common ancestor of two commits, or nil if their histories are unrelated.
+func MergeBase(baseHead, compareHead string, lookup CommitLookup) (*models.CommitNode, error) {
+	compareHashes, err := commitHashes(compareHead, lookup)
+	if err != nil {
+		return nil, err
+	}
+	for commit, err := range CommitAncestors(baseHead, lookup) {
+		if err != nil {
+			return nil, err
+		}
+		if compareHashes[commit.Hash] {
+			return &commit, nil
+		}
+	}
+	return nil, nil
+}
+
+// CommitsBetween returns the commits in the history of compareHead that are not in the history of
+// baseHead, newest first, like `git log base..compare`. An empty baseHead returns the whole history.
+func CommitsBetween(baseHead, compareHead string, lookup CommitLookup) ([]models.CommitNode, error) {
+	baseHashes := map[string]bool{}
+	if baseHead != "" {
+		var err error
+		if baseHashes, err = commitHashes(baseHead, lookup); err != nil {
+			return nil, err
+		}
+	}
+	commits := []models.CommitNode{}
+	for commit, err := range CommitAncestors(compareHead, lookup) {
+		if err != nil {
+			return nil, err
+		}
+		if baseHashes[commit.Hash] {
+			break
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// IsAncestor reports whether ancestor is in the history of head, including head itself.
+// A branch at ancestor can be fast-forwarded to head.
+func IsAncestor(ancestor, head string, lookup CommitLookup) (bool, error) {
+	for commit, err := range CommitAncestors(head, lookup) {
+		if err != nil {
+			return false, err
+		}
+		if commit.Hash == ancestor {
+			return true, nil
+		}
+	}
+	return false, nil
+}
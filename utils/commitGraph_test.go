@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// testCommits builds main: a <- b <- c and feature: b <- d <- e
+func testCommits() map[string]*models.CommitNode {
+	commits := make(map[string]*models.CommitNode)
+	for _, c := range []struct{ hash, previous, author, message, timestamp string }{
+		{"a", "", "Ada", "Initial commit", "2025-01-01T10:00:00Z"},
+		{"b", "a", "Ada", "Add cities", "2025-01-02 10:00:00"},
+		{"c", "b", "Grace", "Update population", "2025-01-03T10:00:00Z"},
+		{"d", "b", "Grace", "Add Stavanger", "2025-01-04T10:00:00Z"},
+		{"e", "d", "Linus", "Fix typo in cities", "2025-01-05T10:00:00Z"},
+	} {
+		commit := models.Commit{Hash: c.hash, Author: c.author, Message: c.message, Timestamp: c.timestamp}
+		if c.previous != "" {
+			commit.PreviousHash = &c.previous
+		}
+		node := ParseCommit(commit)
+		commits[c.hash] = &node
+	}
+	return commits
+}
+
+func commitLookup(commits map[string]*models.CommitNode) CommitLookup {
+	return func(hash string) (*models.CommitNode, error) {
+		commit, ok := commits[hash]
+		if !ok {
+			return nil, fmt.Errorf("commit %s not found", hash)
+		}
+		return commit, nil
+	}
+}
+
+func nodeHashes(nodes []models.CommitNode) []string {
+	var hashes []string
+	for _, node := range nodes {
+		hashes = append(hashes, node.Hash)
+	}
+	return hashes
+}
+
+func TestParseCommitTimestamps(t *testing.T) {
+	commits := testCommits()
+	if expected := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC); !commits["b"].Time.Equal(expected) {
+		t.Errorf("got %v, expected %v", commits["b"].Time, expected)
+	}
+}
+
+func TestCommitAncestorsStopsEarly(t *testing.T) {
+	commits := testCommits()
+	lookups := 0
+	lookup := func(hash string) (*models.CommitNode, error) {
+		lookups++
+		return commitLookup(commits)(hash)
+	}
+	for commit, err := range CommitAncestors("e", lookup) {
+		if err != nil || commit.Hash != "e" {
+			t.Errorf("got first commit %s (%v), expected e", commit.Hash, err)
+		}
+		break
+	}
+	if lookups != 1 {
+		t.Errorf("got %d lookups, expected 1", lookups)
+	}
+}
+
+func TestMergeBase(t *testing.T) {
+	base, err := MergeBase("c", "e", commitLookup(testCommits()))
+	if err != nil || base == nil || base.Hash != "b" {
+		t.Errorf("got %v (%v), expected b", base, err)
+	}
+}
+
+func TestCommitsBetween(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		expected []string
+	}{
+		{name: "between branches", from: "c", to: "e", expected: []string{"e", "d"}},
+		{name: "whole history", to: "c", expected: []string{"c", "b", "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commits, err := CommitsBetween(tt.from, tt.to, commitLookup(testCommits()))
+			if err != nil {
+				t.Fatalf("listing commits: %v", err)
+			}
+			if got := nodeHashes(commits); !slices.Equal(got, tt.expected) {
+				t.Errorf("got %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCommitsBetweenDetectsCycles(t *testing.T) {
+	commits := testCommits()
+	previous := "e"
+	commits["a"].PreviousHash = &previous
+	if _, err := CommitsBetween("", "e", commitLookup(commits)); err == nil {
+		t.Error("expected an error for a cyclic history")
+	}
+}
+
+func TestIsAncestor(t *testing.T) {
+	tests := []struct {
+		ancestor, head string
+		expected       bool
+	}{
+		{ancestor: "b", head: "e", expected: true},
+		{ancestor: "e", head: "e", expected: true},
+		{ancestor: "c", head: "e", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ancestor+" of "+tt.head, func(t *testing.T) {
+			ok, err := IsAncestor(tt.ancestor, tt.head, commitLookup(testCommits()))
+			if err != nil {
+				t.Fatalf("checking ancestry: %v", err)
+			}
+			if ok != tt.expected {
+				t.Errorf("got %t, expected %t", ok, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCommitFilterMatches(t *testing.T) {
+	history, err := CommitsBetween("", "e", commitLookup(testCommits()))
+	if err != nil {
+		t.Fatalf("listing commits: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		filter   models.CommitFilter
+		expected []string
+	}{
+		{name: "author", filter: models.CommitFilter{Author: "grace"}, expected: []string{"d"}},
+		{name: "message", filter: models.CommitFilter{Message: regexp.MustCompile(`(?i)cities`)}, expected: []string{"e", "b"}},
+		{
+			name:     "time",
+			filter:   models.CommitFilter{Since: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Until: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)},
+			expected: []string{"d", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var matched []models.CommitNode
+			for _, commit := range history {
+				if tt.filter.Matches(commit) {
+					matched = append(matched, commit)
+				}
+			}
+			if got := nodeHashes(matched); !slices.Equal(got, tt.expected) {
+				t.Errorf("got %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
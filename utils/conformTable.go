@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// rowSchema returns the schema of the rows of a structured object, whose schema describes either a row or an array of rows
+func rowSchema(schema models.JSONSchema) models.JSONSchema {
+	if schema.Type == "array" && schema.Items != nil {
+		return *schema.Items
+	}
+	return schema
+}
+
+// ConformTable converts the values of a table to the types of a structured object's schema, e.g. CSV
+// text to numbers and booleans, and returns a description of every value that does not conform to it.
+// Empty CSV fields of columns that are not strings are read as null. Columns absent from the schema
+// are kept unless the schema disallows additional properties.
+func ConformTable(table *models.QueryResultTable, schema models.JSONSchema) []string {
+	schema = rowSchema(schema)
+	var violations []string
+	index := columnIndexes(table.Columns)
+	for _, column := range schema.Required {
+		if _, ok := index[column]; !ok {
+			violations = append(violations, fmt.Sprintf("missing required column %q", column))
+		}
+	}
+	if additional, ok := schema.AdditionalProperties.(bool); ok && !additional {
+		for _, column := range table.Columns {
+			if _, ok := schema.Properties[column]; !ok {
+				violations = append(violations, fmt.Sprintf("column %q is not in the schema", column))
+			}
+		}
+	}
+
+	for i, row := range table.Rows {
+		for j, column := range table.Columns {
+			property, ok := schema.Properties[column]
+			if !ok || j >= len(row) {
+				continue
+			}
+			value, err := conformValue(row[j], property)
+			if err == nil && value == nil && slices.Contains(schema.Required, column) {
+				err = fmt.Errorf("is required")
+			}
+			if err != nil {
+				violations = append(violations, fmt.Sprintf("row %d: column %q %v", i+1, column, err))
+				continue
+			}
+			row[j] = value
+		}
+	}
+	return violations
+}
+
+// conformValue converts a value to the type of a property, if it can be converted without loss
+func conformValue(value models.JSONValue, property models.JSONSchema) (models.JSONValue, error) {
+	if text, ok := value.(string); ok && text == "" && property.Type != "string" && property.Type != "" {
+		value = nil
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	switch property.Type {
+	case "integer", "number":
+		var number json.Number
+		switch v := value.(type) {
+		case json.Number:
+			number = v
+		case float64:
+			number = json.Number(strconv.FormatFloat(v, 'f', -1, 64))
+		case string:
+			number = json.Number(strings.TrimSpace(v))
+		default:
+			return nil, fmt.Errorf("is not a number: %s", formatRowValue(value))
+		}
+		if property.Type == "integer" {
+			if _, err := number.Int64(); err != nil {
+				return nil, fmt.Errorf("is not an integer: %s", formatRowValue(value))
+			}
+		}
+		f, err := number.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("is not a number: %s", formatRowValue(value))
+		}
+		if property.Minimum != nil && f < *property.Minimum {
+			return nil, fmt.Errorf("is less than %v: %s", *property.Minimum, number)
+		}
+		if property.Maximum != nil && f > *property.Maximum {
+			return nil, fmt.Errorf("is greater than %v: %s", *property.Maximum, number)
+		}
+		value = number
+	case "boolean":
+		switch v := value.(type) {
+		case bool:
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("is not a boolean: %s", formatRowValue(value))
+			}
+			value = b
+		default:
+			return nil, fmt.Errorf("is not a boolean: %s", formatRowValue(value))
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			field, err := formatCSVField(value)
+			if err != nil {
+				return nil, err
+			}
+			value = field
+		}
+	}
+
+	if len(property.Enum) > 0 && !slices.ContainsFunc(property.Enum, func(allowed interface{}) bool { return rowValuesEqual(allowed, value) }) {
+		return nil, fmt.Errorf("is not one of the allowed values: %s", formatRowValue(value))
+	}
+	return value, nil
+}
+
+// StructuredSchemaMap converts the schema of a structured object to the JSON Schema of its rows accepted
+// by ConvertRowsToParquet. Properties that are not required are made nullable. It returns nil if the schema
+// has no properties, so the schema is inferred from the rows instead.
+func StructuredSchemaMap(schema models.JSONSchema) (map[string]interface{}, error) {
+	schema = rowSchema(schema)
+	if len(schema.Properties) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode schema: %w", err)
+	}
+	var jsonSchema map[string]interface{}
+	if err := json.Unmarshal(data, &jsonSchema); err != nil {
+		return nil, fmt.Errorf("failed to decode schema: %w", err)
+	}
+
+	properties, _ := jsonSchema["properties"].(map[string]interface{})
+	for name, property := range properties {
+		field, ok := property.(map[string]interface{})
+		if !ok || slices.Contains(schema.Required, name) {
+			continue
+		}
+		if kind, ok := field["type"].(string); ok && kind != "null" {
+			field["type"] = []interface{}{kind, "null"}
+		}
+	}
+	return jsonSchema, nil
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+func TestConformTable(t *testing.T) {
+	minimum := 0.0
+	schema := models.JSONSchema{
+		Type: "array",
+		Items: &models.JSONSchema{
+			Type: "object",
+			Properties: map[string]models.JSONSchema{
+				"id":         {Type: "integer"},
+				"name":       {Type: "string"},
+				"population": {Type: "integer", Minimum: &minimum},
+				"capital":    {Type: "boolean"},
+			},
+			Required: []string{"id", "name"},
+		},
+	}
+	table := decodeTestTable(t, []byte("id,name,population,capital\n1,Oslo,709000,true\n2,Bergen,,false\nx,Tromsø,-5,maybe\n"), TableFormatCSV)
+
+	// Row 3 has a non-integer id, a negative population and a non-boolean capital
+	violations := ConformTable(table, schema)
+	if len(violations) != 3 {
+		t.Errorf("got violations %q, expected 3", violations)
+	}
+	expected := [][]models.JSONValue{
+		{json.Number("1"), "Oslo", json.Number("709000"), true},
+		{json.Number("2"), "Bergen", nil, false},
+	}
+	if !reflect.DeepEqual(table.Rows[:2], expected) {
+		t.Errorf("got rows %v, expected %v", table.Rows[:2], expected)
+	}
+
+	// Conformed rows can be encoded with the schema of the object
+	parquetSchema, err := StructuredSchemaMap(schema)
+	if err != nil {
+		t.Fatalf("converting schema: %v", err)
+	}
+	table.Rows = table.Rows[:2]
+	if _, err := EncodeTable(table, TableFormatParquet, parquetSchema); err != nil {
+		t.Errorf("encoding Parquet with the object schema: %v", err)
+	}
+}
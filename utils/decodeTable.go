@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// TableFormat represents the file formats structured objects can be decoded from
+type TableFormat string
+
+const (
+	TableFormatJSON    TableFormat = "json"
+	TableFormatNDJSON  TableFormat = "ndjson"
+	TableFormatCSV     TableFormat = "csv"
+	TableFormatParquet TableFormat = "parquet"
+)
+
+// TableFormatOf returns the format of an object from its content type, or else its file extension.
+func TableFormatOf(object models.Object) (TableFormat, bool) {
+	if object.ContentType != nil {
+		contentType := strings.TrimSpace(strings.Split(*object.ContentType, ";")[0])
+		switch contentType {
+		case "application/json":
+			return TableFormatJSON, true
+		case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/jsonlines":
+			return TableFormatNDJSON, true
+		case "text/csv":
+			return TableFormatCSV, true
+		case "application/vnd.apache.parquet", "application/x-parquet":
+			return TableFormatParquet, true
+		}
+	}
+
+	name := object.Name
+	if name == "" {
+		name = path.Base(object.Path)
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json":
+		return TableFormatJSON, true
+	case ".ndjson", ".jsonl":
+		return TableFormatNDJSON, true
+	case ".csv":
+		return TableFormatCSV, true
+	case ".parquet":
+		return TableFormatParquet, true
+	}
+	return "", false
+}
+
+// DecodeTable decodes the content of a structured object into columns and rows.
+// CSV values are kept as strings and JSON numbers as json.Number.
+func DecodeTable(data []byte, format TableFormat) (*models.QueryResultTable, error) {
+	switch format {
+	case TableFormatJSON:
+		return models.TableFromJSON(data)
+	case TableFormatNDJSON:
+		return decodeNDJSONTable(data)
+	case TableFormatCSV:
+		return decodeCSVTable(data)
+	case TableFormatParquet:
+		return decodeParquetTable(data)
+	default:
+		return nil, fmt.Errorf("unsupported table format %q", format)
+	}
+}
+
+// decodeNDJSONTable decodes newline delimited JSON, skipping empty lines
+func decodeNDJSONTable(data []byte) (*models.QueryResultTable, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	first := true
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		buf.Write(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read NDJSON: %w", err)
+	}
+	buf.WriteByte(']')
+	return models.TableFromJSON(buf.Bytes())
+}
+
+// decodeCSVTable decodes CSV with a header line
+func decodeCSVTable(data []byte) (*models.QueryResultTable, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	table := &models.QueryResultTable{Columns: []string{}, Rows: [][]models.JSONValue{}}
+	if len(records) == 0 {
+		return table, nil
+	}
+
+	table.Columns = records[0]
+	for _, record := range records[1:] {
+		row := make([]models.JSONValue, len(table.Columns))
+		for i := range row {
+			if i < len(record) {
+				row[i] = record[i]
+			}
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+// decodeParquetTable decodes Parquet using the schema stored in the file, so optional columns are read as nil
+func decodeParquetTable(data []byte) (*models.QueryResultTable, error) {
+	pr, err := reader.NewParquetReader(buffer.NewBufferFileFromBytes(data), nil, 4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Parquet reader: %w", err)
+	}
+	defer pr.ReadStop()
+
+	// The reader renames columns to Go field names; map them back to the names in the file
+	handler := pr.SchemaHandler
+	table := &models.QueryResultTable{Columns: []string{}, Rows: [][]models.JSONValue{}}
+	index := make(map[string]int)
+	for i, info := range handler.Infos {
+		// Top-level columns are direct children of the root
+		if strings.Count(handler.IndexMap[int32(i)], common.PAR_GO_PATH_DELIMITER) != 1 {
+			continue
+		}
+		index[info.InName] = len(table.Columns)
+		table.Columns = append(table.Columns, info.ExName)
+	}
+
+	rows, err := pr.ReadByNumber(int(pr.GetNumRows()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Parquet rows: %w", err)
+	}
+	for i, row := range rows {
+		record, err := json.Marshal(row)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode row %d: %w", i, err)
+		}
+		var object map[string]models.JSONValue
+		decoder := json.NewDecoder(bytes.NewReader(record))
+		decoder.UseNumber()
+		if err := decoder.Decode(&object); err != nil {
+			return nil, fmt.Errorf("failed to decode row %d: %w", i, err)
+		}
+
+		values := make([]models.JSONValue, len(table.Columns))
+		for key, value := range object {
+			if j, ok := index[key]; ok {
+				values[j] = value
+			}
+		}
+		table.Rows = append(table.Rows, values)
+	}
+	return table, nil
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// EncodeTable encodes columns and rows in the format of a structured object, the reverse of DecodeTable.
+// Parquet is written with the given JSON schema, or a schema inferred from the rows if nil.
+func EncodeTable(table *models.QueryResultTable, format TableFormat, jsonSchema map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case TableFormatJSON:
+		buf.WriteByte('[')
+		for i, row := range table.Rows {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			data, err := marshalOrderedRow(table.Columns, row)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode JSON row %d: %w", i, err)
+			}
+			buf.Write(data)
+		}
+		buf.WriteString("]\n")
+	case TableFormatNDJSON:
+		if err := WriteNDJSON(&buf, table.Columns, table.Rows); err != nil {
+			return nil, err
+		}
+	case TableFormatCSV:
+		if err := WriteCSV(&buf, table.Columns, table.Rows); err != nil {
+			return nil, err
+		}
+	case TableFormatParquet:
+		if jsonSchema == nil {
+			jsonSchema = InferJSONSchema(table.Columns, table.Rows)
+		}
+		return ConvertRowsToParquet(table.Columns, table.Rows, jsonSchema, 4)
+	default:
+		return nil, fmt.Errorf("unsupported table format %q", format)
+	}
+	return buf.Bytes(), nil
+}
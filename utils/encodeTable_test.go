@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestEncodeTableRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		format TableFormat
+	}{
+		{name: "json", format: TableFormatJSON},
+		{name: "ndjson", format: TableFormatNDJSON},
+		{name: "csv", format: TableFormatCSV},
+		{name: "parquet", format: TableFormatParquet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := decodeTestTable(t, []byte("id,name\n1,Oslo\n2,Bergen\n"), TableFormatCSV)
+			data, err := EncodeTable(table, tt.format, nil)
+			if err != nil {
+				t.Fatalf("encoding: %v", err)
+			}
+			decoded := decodeTestTable(t, data, tt.format)
+			diff := DiffTables(table, decoded, []string{"id"})
+			if len(diff.Changes) != 0 || len(diff.AddedColumns) != 0 || len(diff.RemovedColumns) != 0 {
+				t.Errorf("got %d changed rows and columns %q added and %q removed", len(diff.Changes), diff.AddedColumns, diff.RemovedColumns)
+			}
+		})
+	}
+}
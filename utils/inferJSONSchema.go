@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"encoding/json"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// InferJSONSchema infers a flat JSON Schema from tabular rows.
+// Integral numbers become "integer", other numbers "number", and nested objects
+// or arrays "string" so they can be stored as JSON text. Columns with missing or
+// null values are nullable and left out of "required".
+func InferJSONSchema(columns []string, rows [][]models.JSONValue) map[string]interface{} {
+	properties := make(map[string]interface{}, len(columns))
+	var required []interface{}
+
+	for i, column := range columns {
+		kind := ""
+		nullable := len(rows) == 0
+
+		for _, row := range rows {
+			if i >= len(row) || row[i] == nil {
+				nullable = true
+				continue
+			}
+			kind = mergeJSONTypes(kind, jsonTypeOf(row[i]))
+		}
+		if kind == "" {
+			kind = "string"
+		}
+
+		if nullable {
+			properties[column] = map[string]interface{}{"type": []interface{}{kind, "null"}}
+		} else {
+			properties[column] = map[string]interface{}{"type": kind}
+			required = append(required, column)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// jsonTypeOf returns the JSON Schema type of a decoded value.
+func jsonTypeOf(value models.JSONValue) string {
+	switch v := value.(type) {
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case float32:
+		return "number"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "integer"
+	case bool:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+// mergeJSONTypes returns a type that can hold values of both types.
+func mergeJSONTypes(a, b string) string {
+	switch {
+	case a == "" || a == b:
+		return b
+	case (a == "integer" && b == "number") || (a == "number" && b == "integer"):
+		return "number"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,345 @@
+package utils
+
+import (
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+const (
+	openLineageProducer       = "https://github.com/IrminData/irmin-sdk-go"
+	openLineageJobEventSchema = "https://openlineage.io/spec/2-0-2/OpenLineage.json#/$defs/JobEvent"
+)
+
+// BuildLineageGraph builds the data lineage of a set of workflows:
+//   - imports move data from a connection path to a repository path
+//   - exports move data from a repository path to a connection path
+//   - actions write to the repository path they work on
+//   - pipeline stages that write receive the data passed on by the previous stage, and
+//     stages that read pass their data on to the next stage
+//
+// Action nodes belong to the workflow, or pipeline stage, running them, so workflows sharing
+// an executable are not connected through it.
+func BuildLineageGraph(workflows []models.Workflow) *models.LineageGraph {
+	b := &lineageBuilder{
+		graph: &models.LineageGraph{
+			Nodes: []models.LineageNode{},
+			Edges: []models.LineageEdge{},
+		},
+		nodes: make(map[string]bool),
+		edges: make(map[lineageEdgeKey]bool),
+	}
+
+	for i := range workflows {
+		workflow := &workflows[i]
+		switch workflow.Type {
+		case models.WorkflowableTypeImport:
+			if config, ok := workflow.AsImport(); ok {
+				from := b.connectionNode(config.Connection, config.ConnectionPath)
+				to := b.repositoryNode(config.Repository, config.Branch, config.Path)
+				b.addEdge(workflow, from, to, nil)
+			}
+		case models.WorkflowableTypeExport:
+			if config, ok := workflow.AsExport(); ok {
+				from := b.repositoryNode(config.Repository, config.Branch, config.Path)
+				to := b.connectionNode(config.Connection, config.ConnectionPath)
+				b.addEdge(workflow, from, to, nil)
+			}
+		case models.WorkflowableTypeAction:
+			if config, ok := workflow.AsAction(); ok {
+				from := b.actionNode(workflow, nil, config.Executable)
+				if config.Repository != nil {
+					branch, objectPath := "", ""
+					if config.Branch != nil {
+						branch = *config.Branch
+					}
+					if config.Path != nil {
+						objectPath = *config.Path
+					}
+					b.addEdge(workflow, from, b.repositoryNode(*config.Repository, branch, objectPath), nil)
+				}
+			}
+		case models.WorkflowableTypePipeline:
+			if config, ok := workflow.AsPipeline(); ok {
+				b.addPipeline(workflow, config)
+			}
+		}
+	}
+	return b.graph
+}
+
+// lineageEdgeKey identifies an edge for deduplication
+type lineageEdgeKey struct {
+	from, to, workflowID string
+	stage                int
+}
+
+// lineageBuilder deduplicates the nodes and edges of a lineage graph being built
+type lineageBuilder struct {
+	graph *models.LineageGraph
+	nodes map[string]bool
+	edges map[lineageEdgeKey]bool
+}
+
+func (b *lineageBuilder) addPipeline(workflow *models.Workflow, pipeline *models.Pipeline) {
+	var upstream []string
+	for i, stage := range pipeline.Stages {
+		var node string
+		var common models.CommonProperties
+		switch s := stage.(type) {
+		case *models.PipelineStageConnection:
+			common = s.CommonProperties
+			if s.Write {
+				input := b.connectionNode(s.Connection, s.ConnectionWritePath)
+				b.addEdges(workflow, upstream, input, i)
+			}
+			if s.Read {
+				node = b.connectionNode(s.Connection, s.ConnectionReadPath)
+			}
+		case *models.PipelineStageRepository:
+			common = s.CommonProperties
+			node = b.repositoryNode(s.Repository, s.Branch, s.Path)
+			if s.Write {
+				b.addEdges(workflow, upstream, node, i)
+			}
+		case *models.PipelineStageAction:
+			common = s.CommonProperties
+			node = b.actionNode(workflow, &i, s.Executable)
+			if s.Write {
+				b.addEdges(workflow, upstream, node, i)
+			}
+		default:
+			upstream = nil
+			continue
+		}
+
+		// Only stages that read pass data on to the next stage
+		upstream = nil
+		if common.Read && node != "" {
+			upstream = []string{node}
+		}
+	}
+}
+
+func (b *lineageBuilder) addEdges(workflow *models.Workflow, from []string, to string, stage int) {
+	for _, source := range from {
+		b.addEdge(workflow, source, to, &stage)
+	}
+}
+
+func (b *lineageBuilder) addEdge(workflow *models.Workflow, from, to string, stage *int) {
+	key := lineageEdgeKey{from: from, to: to, workflowID: workflow.ID, stage: -1}
+	if stage != nil {
+		key.stage = *stage
+	}
+	if b.edges[key] {
+		return
+	}
+	b.edges[key] = true
+	b.graph.Edges = append(b.graph.Edges, models.LineageEdge{
+		From:         from,
+		To:           to,
+		WorkflowID:   workflow.ID,
+		WorkflowName: workflow.Name,
+		WorkflowType: workflow.Type,
+		Stage:        stage,
+	})
+}
+
+func (b *lineageBuilder) addNode(node models.LineageNode) string {
+	if !b.nodes[node.ID] {
+		b.nodes[node.ID] = true
+		b.graph.Nodes = append(b.graph.Nodes, node)
+	}
+	return node.ID
+}
+
+func (b *lineageBuilder) connectionNode(connection models.Connection, connectionPath string) string {
+	connectionPath = normaliseLineagePath(connectionPath)
+	name := connection.Name
+	if name == "" {
+		name = connection.ID
+	}
+	return b.addNode(models.LineageNode{
+		ID:             "connection:" + connection.ID + ":" + connectionPath,
+		Kind:           models.LineageNodeConnection,
+		Label:          name + ":" + connectionPath,
+		ConnectionID:   connection.ID,
+		ConnectionName: connection.Name,
+		Path:           connectionPath,
+	})
+}
+
+func (b *lineageBuilder) repositoryNode(repository models.Repository, branch, objectPath string) string {
+	branch = branchOrDefault(branch, repository)
+	objectPath = normaliseLineagePath(objectPath)
+	label := repository.Slug + "@" + branch + ":" + objectPath
+	return b.addNode(models.LineageNode{
+		ID:         "repository:" + label,
+		Kind:       models.LineageNodeRepository,
+		Label:      label,
+		Repository: repository.Slug,
+		Branch:     branch,
+		Path:       objectPath,
+	})
+}
+
+func (b *lineageBuilder) actionNode(workflow *models.Workflow, stage *int, executable string) string {
+	owner := workflow.ID
+	if stage != nil {
+		owner += "/" + strconv.Itoa(*stage)
+	}
+	return b.addNode(models.LineageNode{
+		ID:         "action:" + owner + ":" + executable,
+		Kind:       models.LineageNodeAction,
+		Label:      executable,
+		Executable: executable,
+		WorkflowID: workflow.ID,
+		Stage:      stage,
+	})
+}
+
+// normaliseLineagePath cleans a path and makes it absolute
+func normaliseLineagePath(p string) string {
+	return path.Clean("/" + p)
+}
+
+// LineageUpstream returns the part of a lineage graph that data flows through before
+// reaching a repository path. Since directories contain their objects, nodes for the
+// directories containing the path and for paths within it are included as well.
+func LineageUpstream(graph *models.LineageGraph, repository, branch, objectPath string) *models.LineageGraph {
+	return traverseLineage(graph, repository, branch, objectPath, true)
+}
+
+// LineageDownstream returns the part of a lineage graph that data flows through after
+// leaving a repository path. Since directories contain their objects, nodes for the
+// directories containing the path and for paths within it are included as well.
+func LineageDownstream(graph *models.LineageGraph, repository, branch, objectPath string) *models.LineageGraph {
+	return traverseLineage(graph, repository, branch, objectPath, false)
+}
+
+// traverseLineage walks a lineage graph from the nodes related to a repository path
+func traverseLineage(graph *models.LineageGraph, repository, branch, objectPath string, upstream bool) *models.LineageGraph {
+	objectPath = normaliseLineagePath(objectPath)
+	result := &models.LineageGraph{Nodes: []models.LineageNode{}, Edges: []models.LineageEdge{}}
+
+	byID := make(map[string]*models.LineageNode, len(graph.Nodes))
+	for i := range graph.Nodes {
+		byID[graph.Nodes[i].ID] = &graph.Nodes[i]
+	}
+	// related returns the repository nodes whose path contains or is contained in the node's path
+	related := func(repository, branch, objectPath string) []string {
+		var ids []string
+		for _, node := range graph.Nodes {
+			if node.Kind == models.LineageNodeRepository && node.Repository == repository && node.Branch == branch &&
+				(pathContains(node.Path, objectPath) || pathContains(objectPath, node.Path)) {
+				ids = append(ids, node.ID)
+			}
+		}
+		return ids
+	}
+
+	type visit struct {
+		id string
+		// Whether the node was reached through its path, so its own related paths are not followed
+		viaPath bool
+	}
+	visited := make(map[string]bool)
+	var queue []visit
+	enqueue := func(id string, viaPath bool) {
+		if !visited[id] {
+			visited[id] = true
+			queue = append(queue, visit{id: id, viaPath: viaPath})
+		}
+	}
+	for _, id := range related(repository, branch, objectPath) {
+		enqueue(id, true)
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		node := byID[current.id]
+		result.Nodes = append(result.Nodes, *node)
+
+		if !current.viaPath && node.Kind == models.LineageNodeRepository {
+			for _, id := range related(node.Repository, node.Branch, node.Path) {
+				enqueue(id, true)
+			}
+		}
+		for _, edge := range graph.Edges {
+			switch {
+			case upstream && edge.To == current.id:
+				result.Edges = append(result.Edges, edge)
+				enqueue(edge.From, false)
+			case !upstream && edge.From == current.id:
+				result.Edges = append(result.Edges, edge)
+				enqueue(edge.To, false)
+			}
+		}
+	}
+	return result
+}
+
+// pathContains reports whether dir is p or one of its parent directories
+func pathContains(dir, p string) bool {
+	return dir == "/" || dir == p || strings.HasPrefix(p, dir+"/")
+}
+
+// LineageToOpenLineage converts a lineage graph to OpenLineage job events, one per
+// workflow. Connection paths and repository paths are datasets; actions are part of the job.
+func LineageToOpenLineage(graph *models.LineageGraph, namespace string, eventTime time.Time) []models.OpenLineageJobEvent {
+	byID := make(map[string]*models.LineageNode, len(graph.Nodes))
+	for i := range graph.Nodes {
+		byID[graph.Nodes[i].ID] = &graph.Nodes[i]
+	}
+
+	events := []models.OpenLineageJobEvent{}
+	index := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, edge := range graph.Edges {
+		i, ok := index[edge.WorkflowID]
+		if !ok {
+			i = len(events)
+			index[edge.WorkflowID] = i
+			events = append(events, models.OpenLineageJobEvent{
+				EventTime: eventTime.UTC().Format(time.RFC3339),
+				Producer:  openLineageProducer,
+				SchemaURL: openLineageJobEventSchema,
+				Job: models.OpenLineageJob{
+					Namespace: namespace,
+					Name:      edge.WorkflowName,
+				},
+				Inputs:  []models.OpenLineageDataset{},
+				Outputs: []models.OpenLineageDataset{},
+			})
+		}
+
+		if dataset, ok := openLineageDataset(byID[edge.From]); ok && !seen[edge.WorkflowID+"\x00in\x00"+edge.From] {
+			seen[edge.WorkflowID+"\x00in\x00"+edge.From] = true
+			events[i].Inputs = append(events[i].Inputs, dataset)
+		}
+		if dataset, ok := openLineageDataset(byID[edge.To]); ok && !seen[edge.WorkflowID+"\x00out\x00"+edge.To] {
+			seen[edge.WorkflowID+"\x00out\x00"+edge.To] = true
+			events[i].Outputs = append(events[i].Outputs, dataset)
+		}
+	}
+	return events
+}
+
+// openLineageDataset converts a connection or repository node to a dataset
+func openLineageDataset(node *models.LineageNode) (models.OpenLineageDataset, bool) {
+	if node == nil {
+		return models.OpenLineageDataset{}, false
+	}
+	switch node.Kind {
+	case models.LineageNodeConnection:
+		return models.OpenLineageDataset{Namespace: "irmin-connection://" + node.ConnectionID, Name: node.Path}, true
+	case models.LineageNodeRepository:
+		return models.OpenLineageDataset{Namespace: "irmin://" + node.Repository + "@" + node.Branch, Name: node.Path}, true
+	}
+	return models.OpenLineageDataset{}, false
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+func TestLineageQueries(t *testing.T) {
+	graph := BuildLineageGraph(decodeGraphWorkflows(t))
+	tests := []struct {
+		name     string
+		graph    *models.LineageGraph
+		expected []string
+	}{
+		{
+			// An object within the reports directory is fed by the lakes import through the pipeline
+			name:     "upstream of a report",
+			graph:    LineageUpstream(graph, "reports", "main", "/summary.csv"),
+			expected: []string{"action:2/1:/transform.js", "connection:c1:/", "repository:lakes@main:/", "repository:reports@main:/"},
+		},
+		{
+			// Objects imported into lakes end up in reports and leave through the export
+			name:     "downstream of a lake",
+			graph:    LineageDownstream(graph, "lakes", "main", "/Lakes.json"),
+			expected: []string{"action:2/1:/transform.js", "connection:c2:/", "repository:lakes@main:/", "repository:reports@main:/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for _, node := range tt.graph.Nodes {
+				ids = append(ids, node.ID)
+			}
+			slices.Sort(ids)
+			if !slices.Equal(ids, tt.expected) {
+				t.Errorf("got nodes %v, expected %v", ids, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLineageSharedExecutable(t *testing.T) {
+	// Both action workflows and the pipeline run the same executable
+	var workflows []models.Workflow
+	err := json.Unmarshal([]byte(`[
+		{
+			"id": "a", "name": "Clean lakes", "type": "action",
+			"workflowable": {"executable": "/clean.py", "repository": {"slug": "lakes"}, "branch": "main", "path": "/"}
+		},
+		{
+			"id": "b", "name": "Clean rivers", "type": "action",
+			"workflowable": {"executable": "/clean.py", "repository": {"slug": "rivers"}, "branch": "main", "path": "/"}
+		},
+		{
+			"id": "c", "name": "Publish", "type": "pipeline",
+			"workflowable": {"live": false, "stages": [
+				{"type": "repository", "read": true, "write": false, "repository": {"slug": "raw"}, "branch": "main", "path": "/"},
+				{"type": "action", "read": true, "write": true, "executable": "/clean.py"},
+				{"type": "repository", "read": false, "write": true, "repository": {"slug": "reports"}, "branch": "main", "path": "/"}
+			]}
+		}
+	]`), &workflows)
+	if err != nil {
+		t.Fatalf("decoding workflows: %v", err)
+	}
+	graph := BuildLineageGraph(workflows)
+
+	tests := []struct {
+		name     string
+		graph    *models.LineageGraph
+		expected []string
+	}{
+		{
+			// Data read by the pipeline does not flow into the outputs of the action workflows
+			name:     "downstream of the pipeline input",
+			graph:    LineageDownstream(graph, "raw", "main", "/"),
+			expected: []string{"action:c/1:/clean.py", "repository:raw@main:/", "repository:reports@main:/"},
+		},
+		{
+			name:     "upstream of the first action",
+			graph:    LineageUpstream(graph, "lakes", "main", "/"),
+			expected: []string{"action:a:/clean.py", "repository:lakes@main:/"},
+		},
+		{
+			name:     "upstream of the second action",
+			graph:    LineageUpstream(graph, "rivers", "main", "/"),
+			expected: []string{"action:b:/clean.py", "repository:rivers@main:/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for _, node := range tt.graph.Nodes {
+				ids = append(ids, node.ID)
+			}
+			slices.Sort(ids)
+			if !slices.Equal(ids, tt.expected) {
+				t.Errorf("got nodes %v, expected %v", ids, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLineageToOpenLineage(t *testing.T) {
+	graph := BuildLineageGraph(decodeGraphWorkflows(t))
+	events := LineageToOpenLineage(graph, "irmin", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var transform *models.OpenLineageJobEvent
+	for i := range events {
+		if events[i].EventTime != "2026-01-01T00:00:00Z" || events[i].Job.Namespace != "irmin" {
+			t.Errorf("unexpected event time or namespace: %+v", events[i])
+		}
+		if events[i].Job.Name == "Transform" {
+			transform = &events[i]
+		}
+	}
+	if transform == nil {
+		t.Fatalf("no event for the Transform pipeline in %+v", events)
+	}
+	expected := []models.OpenLineageDataset{{Namespace: "irmin://lakes@main", Name: "/"}}
+	if !slices.EqualFunc(transform.Inputs, expected, func(a, b models.OpenLineageDataset) bool { return a.Namespace == b.Namespace && a.Name == b.Name }) {
+		t.Errorf("got inputs %+v, expected %+v", transform.Inputs, expected)
+	}
+	expected = []models.OpenLineageDataset{{Namespace: "irmin://reports@main", Name: "/"}}
+	if !slices.EqualFunc(transform.Outputs, expected, func(a, b models.OpenLineageDataset) bool { return a.Namespace == b.Namespace && a.Name == b.Name }) {
+		t.Errorf("got outputs %+v, expected %+v", transform.Outputs, expected)
+	}
+}
@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"time"
+)
+
+// memFS is a read-only in-memory file system, keyed by slash-separated paths with "." as the root
+type memFS struct {
+	files map[string]*memFile
+}
+
+// memFile is a file or directory of a memFS
+type memFile struct {
+	name    string
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+	// Sorted names of the entries of a directory
+	children []string
+}
+
+func newMemFS() *memFS {
+	return &memFS{files: map[string]*memFile{".": {name: ".", mode: fs.ModeDir | 0o555}}}
+}
+
+// addDir adds a directory and its missing parents
+func (m *memFS) addDir(name string, modTime time.Time) *memFile {
+	if dir, ok := m.files[name]; ok {
+		if !modTime.IsZero() {
+			dir.modTime = modTime
+		}
+		return dir
+	}
+	parent := m.addDir(path.Dir(name), time.Time{})
+	dir := &memFile{name: path.Base(name), mode: fs.ModeDir | 0o555, modTime: modTime}
+	m.files[name] = dir
+	parent.addChild(dir.name)
+	return dir
+}
+
+// addFile adds a file and its missing parent directories, replacing any previous file at the path
+func (m *memFS) addFile(name string, data []byte, mode fs.FileMode, modTime time.Time) {
+	if mode == 0 {
+		mode = 0o444
+	}
+	parent := m.addDir(path.Dir(name), time.Time{})
+	file := &memFile{name: path.Base(name), data: data, mode: mode.Perm(), modTime: modTime}
+	m.files[name] = file
+	parent.addChild(file.name)
+}
+
+func (f *memFile) addChild(name string) {
+	if i, found := slices.BinarySearch(f.children, name); !found {
+		f.children = slices.Insert(f.children, i, name)
+	}
+}
+
+// Open opens a file or directory of the file system
+func (m *memFS) Open(name string) (fs.File, error) {
+	file, err := m.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if file.mode.IsDir() {
+		return &openMemDir{fsys: m, path: name, file: file}, nil
+	}
+	return &openMemFile{file: file, Reader: bytes.NewReader(file.data)}, nil
+}
+
+// ReadFile returns the content of a file, implementing fs.ReadFileFS
+func (m *memFS) ReadFile(name string) ([]byte, error) {
+	file, err := m.lookup("readfile", name)
+	if err != nil {
+		return nil, err
+	}
+	if file.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+	return bytes.Clone(file.data), nil
+}
+
+// Stat describes a file or directory, implementing fs.StatFS
+func (m *memFS) Stat(name string) (fs.FileInfo, error) {
+	file, err := m.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return memFileInfo{file}, nil
+}
+
+// ReadDir reads a directory, implementing fs.ReadDirFS
+func (m *memFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	file, err := m.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !file.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	return m.entries(name, file), nil
+}
+
+func (m *memFS) lookup(op, name string) (*memFile, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	file, ok := m.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return file, nil
+}
+
+func (m *memFS) entries(name string, dir *memFile) []fs.DirEntry {
+	entries := make([]fs.DirEntry, 0, len(dir.children))
+	for _, child := range dir.children {
+		entries = append(entries, fs.FileInfoToDirEntry(memFileInfo{m.files[path.Join(name, child)]}))
+	}
+	return entries
+}
+
+// memFileInfo describes a memFile
+type memFileInfo struct {
+	file *memFile
+}
+
+func (i memFileInfo) Name() string       { return i.file.name }
+func (i memFileInfo) Size() int64        { return int64(len(i.file.data)) }
+func (i memFileInfo) Mode() fs.FileMode  { return i.file.mode }
+func (i memFileInfo) ModTime() time.Time { return i.file.modTime }
+func (i memFileInfo) IsDir() bool        { return i.file.mode.IsDir() }
+func (i memFileInfo) Sys() any           { return nil }
+
+// openMemFile is an open file of a memFS, which can also be read at offsets and seeked
+type openMemFile struct {
+	file *memFile
+	*bytes.Reader
+}
+
+func (f *openMemFile) Stat() (fs.FileInfo, error) { return memFileInfo{f.file}, nil }
+func (f *openMemFile) Close() error               { return nil }
+
+// openMemDir is an open directory of a memFS
+type openMemDir struct {
+	fsys    *memFS
+	path    string
+	file    *memFile
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *openMemDir) Stat() (fs.FileInfo, error) { return memFileInfo{d.file}, nil }
+func (d *openMemDir) Close() error               { return nil }
+
+func (d *openMemDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.path, Err: fs.ErrInvalid}
+}
+
+// ReadDir reads the next n entries of the directory, or all remaining entries if n <= 0
+func (d *openMemDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if d.entries == nil {
+		d.entries = d.fsys.entries(d.path, d.file)
+	}
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(remaining))
+	d.offset += n
+	return remaining[:n], nil
+}
@@ -0,0 +1,175 @@
+package utils
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// MergeTables merges the changes two versions of a structured object made to their common ancestor.
+// Rows are aligned by the key columns, or by position without key columns or if a key column is missing
+// from either version; repeated keys are told apart by their occurrence. Values changed in only one
+// version are taken from it. Values changed differently in both, and rows deleted in one version but
+// modified in the other, are conflicts; the merged table keeps the compare version of those with the
+// TableConflictCompare policy and the base version otherwise. Conflicts are returned whatever the policy.
+// Merged rows are in base order, followed by the rows added in the compare version.
+func MergeTables(ancestor, base, compare *models.QueryResultTable, keyColumns []string, policy models.TableConflictPolicy) (*models.QueryResultTable, []models.TableMergeConflict) {
+	preferCompare := policy == models.TableConflictCompare
+	baseIndex := columnIndexes(base.Columns)
+	compareIndex := columnIndexes(compare.Columns)
+	ancestorIndex := columnIndexes(ancestor.Columns)
+
+	positional := len(keyColumns) == 0
+	for _, column := range keyColumns {
+		_, inBase := baseIndex[column]
+		_, inCompare := compareIndex[column]
+		_, inAncestor := ancestorIndex[column]
+		if !inBase || !inCompare || (!inAncestor && len(ancestor.Rows) > 0) {
+			positional = true
+		}
+	}
+
+	merged := &models.QueryResultTable{Columns: slices.Clone(base.Columns), Rows: [][]models.JSONValue{}}
+	for _, column := range compare.Columns {
+		if _, ok := baseIndex[column]; !ok {
+			merged.Columns = append(merged.Columns, column)
+		}
+	}
+
+	ancestorRows := keyedRows(ancestor, keyColumns, positional)
+	baseRows := keyedRows(base, keyColumns, positional)
+	compareRows := keyedRows(compare, keyColumns, positional)
+
+	var keys []string
+	seen := make(map[string]int, len(base.Rows))
+	for _, key := range tableRowKeys(base, keyColumns, positional) {
+		keys = append(keys, uniqueRowKey(seen, key))
+	}
+	seen = make(map[string]int, len(compare.Rows))
+	for _, key := range tableRowKeys(compare, keyColumns, positional) {
+		key = uniqueRowKey(seen, key)
+		if _, ok := baseRows[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	var conflicts []models.TableMergeConflict
+	value := func(table *models.QueryResultTable, index map[string]int, row int, column string) models.JSONValue {
+		if i, ok := index[column]; ok && i < len(table.Rows[row]) {
+			return table.Rows[row][i]
+		}
+		return nil
+	}
+	unchanged := func(a int, table *models.QueryResultTable, index map[string]int, row int) bool {
+		for _, column := range merged.Columns {
+			if !rowValuesEqual(value(ancestor, ancestorIndex, a, column), value(table, index, row, column)) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, key := range keys {
+		a, inAncestor := ancestorRows[key]
+		b, inBase := baseRows[key]
+		c, inCompare := compareRows[key]
+
+		switch {
+		case inBase && inCompare:
+			row := make([]models.JSONValue, len(merged.Columns))
+			for i, column := range merged.Columns {
+				baseValue := value(base, baseIndex, b, column)
+				compareValue := value(compare, compareIndex, c, column)
+				var ancestorValue models.JSONValue
+				if inAncestor {
+					ancestorValue = value(ancestor, ancestorIndex, a, column)
+				}
+				_, inBaseColumns := baseIndex[column]
+				switch {
+				case rowValuesEqual(baseValue, compareValue):
+					row[i] = baseValue
+				case !inBaseColumns || (inAncestor && rowValuesEqual(ancestorValue, baseValue)):
+					row[i] = compareValue
+				case inAncestor && rowValuesEqual(ancestorValue, compareValue):
+					row[i] = baseValue
+				default:
+					row[i] = baseValue
+					if preferCompare {
+						row[i] = compareValue
+					}
+					conflicts = append(conflicts, models.TableMergeConflict{
+						Key:      key,
+						Column:   column,
+						Ancestor: ancestorValue,
+						Base:     baseValue,
+						Compare:  compareValue,
+					})
+				}
+			}
+			merged.Rows = append(merged.Rows, row)
+
+		case inBase:
+			// Rows deleted in the compare version stay deleted, unless the base version modified them
+			if inAncestor && unchanged(a, base, baseIndex, b) {
+				continue
+			}
+			if inAncestor {
+				conflicts = append(conflicts, models.TableMergeConflict{Key: key, Ancestor: rowObject(ancestor, a), Base: rowObject(base, b)})
+				if preferCompare {
+					continue
+				}
+			}
+			merged.Rows = append(merged.Rows, mergedRow(merged.Columns, base, baseIndex, b))
+
+		case inCompare:
+			if inAncestor && unchanged(a, compare, compareIndex, c) {
+				continue
+			}
+			if inAncestor {
+				conflicts = append(conflicts, models.TableMergeConflict{Key: key, Ancestor: rowObject(ancestor, a), Compare: rowObject(compare, c)})
+				if !preferCompare {
+					continue
+				}
+			}
+			merged.Rows = append(merged.Rows, mergedRow(merged.Columns, compare, compareIndex, c))
+		}
+	}
+	return merged, conflicts
+}
+
+// keyedRows indexes the rows of a table by their unique key
+func keyedRows(table *models.QueryResultTable, keyColumns []string, positional bool) map[string]int {
+	rows := make(map[string]int, len(table.Rows))
+	seen := make(map[string]int, len(table.Rows))
+	for i, key := range tableRowKeys(table, keyColumns, positional) {
+		rows[uniqueRowKey(seen, key)] = i
+	}
+	return rows
+}
+
+// uniqueRowKey tells a repeated key apart from its earlier occurrences by appending the occurrence.
+// seen counts the occurrences of the keys passed so far and holds the keys returned, so a key that
+// happens to look like a numbered repeat of another is told apart as well.
+func uniqueRowKey(seen map[string]int, key string) string {
+	unique := key
+	for n := seen[key] + 1; seen[unique] > 0; n++ {
+		unique = key + " (" + strconv.Itoa(n) + ")"
+	}
+	seen[key]++
+	if unique != key {
+		seen[unique]++
+	}
+	return unique
+}
+
+// mergedRow returns the values of a row for the merged columns
+func mergedRow(columns []string, table *models.QueryResultTable, index map[string]int, row int) []models.JSONValue {
+	values := make([]models.JSONValue, len(columns))
+	for i, column := range columns {
+		if j, ok := index[column]; ok && j < len(table.Rows[row]) {
+			values[i] = table.Rows[row][j]
+		}
+	}
+	return values
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+func TestMergeTables(t *testing.T) {
+	ancestor := "id,name,population\n1,Oslo,709000\n2,Bergen,286000\n3,Trondheim,212000\n"
+	tests := []struct {
+		name          string
+		base, compare string
+		policy        models.TableConflictPolicy
+		expected      string
+		conflicts     int
+	}{
+		{
+			name:     "no conflicts",
+			base:     "id,name,population\n1,Oslo,717000\n2,Bergen,286000\n3,Trondheim,212000\n",
+			compare:  "id,name,population\n1,Oslo,709000\n2,Bergen,291000\n4,Stavanger,146000\n",
+			policy:   models.TableConflictReport,
+			expected: "id,name,population\n1,Oslo,717000\n2,Bergen,291000\n4,Stavanger,146000\n",
+		},
+		{
+			// Oslo was changed differently, Bergen was modified in base but deleted in compare, and
+			// Trondheim was deleted in base but modified in compare
+			name:      "conflicts reported",
+			base:      "id,name,population\n1,Oslo,717000\n2,Bergen,290000\n",
+			compare:   "id,name,population\n1,Oslo,720000\n3,Trondheim,215000\n",
+			policy:    models.TableConflictReport,
+			expected:  "id,name,population\n1,Oslo,717000\n2,Bergen,290000\n",
+			conflicts: 3,
+		},
+		{
+			name:      "compare policy",
+			base:      "id,name,population\n1,Oslo,717000\n2,Bergen,290000\n",
+			compare:   "id,name,population\n1,Oslo,720000\n3,Trondheim,215000\n",
+			policy:    models.TableConflictCompare,
+			expected:  "id,name,population\n1,Oslo,720000\n3,Trondheim,215000\n",
+			conflicts: 3,
+		},
+		{
+			// Repeated keys are aligned by occurrence, so the first Oslo row keeps the change of base
+			name:     "duplicate keys",
+			base:     "id,name,population\n1,Oslo,717000\n1,Oslo,1\n2,Bergen,286000\n3,Trondheim,212000\n",
+			compare:  "id,name,population\n1,Oslo,709000\n1,Oslo,1\n2,Bergen,286000\n3,Trondheim,212000\n",
+			policy:   models.TableConflictReport,
+			expected: "id,name,population\n1,Oslo,717000\n1,Oslo,1\n2,Bergen,286000\n3,Trondheim,212000\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, conflicts := MergeTables(
+				decodeTestTable(t, []byte(ancestor), TableFormatCSV),
+				decodeTestTable(t, []byte(tt.base), TableFormatCSV),
+				decodeTestTable(t, []byte(tt.compare), TableFormatCSV),
+				[]string{"id"}, tt.policy)
+			if len(conflicts) != tt.conflicts {
+				t.Errorf("got %d conflicts, expected %d: %+v", len(conflicts), tt.conflicts, conflicts)
+			}
+			var buf bytes.Buffer
+			if err := WriteCSV(&buf, merged.Columns, merged.Rows); err != nil {
+				t.Fatalf("writing CSV: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("got\n%s\nexpected\n%s", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestUniqueRowKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []string
+		expected []string
+	}{
+		{name: "unique", keys: []string{"a", "b"}, expected: []string{"a", "b"}},
+		{name: "repeated", keys: []string{"a", "b", "a", "a"}, expected: []string{"a", "b", "a (2)", "a (3)"}},
+		{name: "key looks like a repeat", keys: []string{"a (2)", "a", "a", "a"}, expected: []string{"a (2)", "a", "a (3)", "a (4)"}},
+		{name: "repeat of a repeat", keys: []string{"a", "a", "a (2)"}, expected: []string{"a", "a (2)", "a (2) (2)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := make(map[string]int)
+			for i, key := range tt.keys {
+				if got := uniqueRowKey(seen, key); got != tt.expected[i] {
+					t.Errorf("key %d: got %q, expected %q", i, got, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestKeyedRowsManyDuplicates(t *testing.T) {
+	var csv strings.Builder
+	csv.WriteString("id,value\n")
+	for i := 0; i < 50000; i++ {
+		fmt.Fprintf(&csv, "1,%d\n", i)
+	}
+	rows := keyedRows(decodeTestTable(t, []byte(csv.String()), TableFormatCSV), []string{"id"}, false)
+	if len(rows) != 50000 {
+		t.Errorf("got %d keyed rows, expected 50000", len(rows))
+	}
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+func TestValidatePipelineDefinition(t *testing.T) {
+	tests := []struct {
+		name     string
+		stages   []models.PipelineStage
+		live     bool
+		schedule *models.WorkflowSchedule
+		expected []string
+	}{
+		{
+			name: "valid",
+			stages: []models.PipelineStage{
+				&models.PipelineStageRepository{
+					Type:             "repository",
+					Repository:       models.Repository{Slug: "lakes"},
+					Branch:           "main",
+					Path:             "/",
+					CommonProperties: models.CommonProperties{Read: true},
+				},
+				&models.PipelineStageAction{
+					Type:             "action",
+					Executable:       "/transform.js",
+					CommonProperties: models.CommonProperties{Write: true, Read: true},
+				},
+				&models.PipelineStageConnection{
+					Type:                "connection",
+					Connection:          models.Connection{ID: "c1"},
+					ConnectionWritePath: "/reports",
+					CommonProperties:    models.CommonProperties{Write: true},
+				},
+			},
+		},
+		{
+			name:     "empty",
+			expected: []string{"pipeline: a pipeline requires at least one stage"},
+		},
+		{
+			name: "broken",
+			stages: []models.PipelineStage{
+				&models.PipelineStageAction{
+					Type:             "action",
+					CommonProperties: models.CommonProperties{Write: true},
+				},
+				&models.PipelineStageRepository{
+					Type:             "repository",
+					Repository:       models.Repository{Slug: "lakes"},
+					CommonProperties: models.CommonProperties{Write: true, Read: true},
+				},
+				&models.PipelineStageConnection{
+					Type:             "connection",
+					CommonProperties: models.CommonProperties{Write: true},
+				},
+			},
+			live: true,
+			schedule: &models.WorkflowSchedule{
+				Triggers: []models.WorkflowTrigger{
+					models.TimeTrigger{Type: "time", RRule: "FREQ=DAILY"},
+					models.RepositoryTrigger{Type: "repository-event", Event: "post-commit"},
+				},
+			},
+			expected: []string{
+				"stage 1: action stage requires an executable",
+				"stage 1: the first stage has no input to write, as no stage precedes it",
+				"stage 2: stage writes its input, but stage 1 does not read and pass on its result",
+				"stage 3: connection stage requires a connection",
+				"pipeline: live pipelines run continuously and must not have time triggers (trigger 0)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var problems []string
+			for _, problem := range ValidatePipelineDefinition(tt.stages, tt.live, tt.schedule) {
+				problems = append(problems, problem.String())
+			}
+			if !slices.Equal(problems, tt.expected) {
+				t.Errorf("got problems %q, expected %q", problems, tt.expected)
+			}
+		})
+	}
+}
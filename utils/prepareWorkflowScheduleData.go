@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/rrule"
 )
 
 // PrepareWorkflowScheduleData prepares a map of fields for a workflow schedule.
@@ -18,6 +19,10 @@ func PrepareWorkflowScheduleData(schedule models.WorkflowSchedule) (map[string]s
 		// Type assertion to access concrete fields
 		switch t := trigger.(type) {
 		case *models.TimeTrigger:
+			// Validate the recurrence rule before sending it
+			if _, err := rrule.Parse(t.RRule); err != nil {
+				return nil, fmt.Errorf("invalid time trigger at index %d: %w", index, err)
+			}
 			// Write time trigger fields
 			fields[fieldPrefix+"type"] = "time"
 			fields[fieldPrefix+"rrule"] = t.RRule
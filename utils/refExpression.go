@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseRefExpression splits a ref expression into the ref it starts from and the number of generations
+// to go back, e.g. `main~3` into `main` and 3. Like git, `~` and `^` go back one generation, `~N` and `^1`
+// go back N and one generations, `^0` stays, and suffixes can be chained, e.g. `v1.2~2^`.
+// Commits have a single parent, so `^N` with N > 1 is an error.
+func ParseRefExpression(expression string) (string, int, error) {
+	end := strings.IndexAny(expression, "~^")
+	if end < 0 {
+		return expression, 0, nil
+	}
+	name, suffix := expression[:end], expression[end:]
+	if name == "" {
+		return "", 0, fmt.Errorf("ref expression %q has no ref", expression)
+	}
+
+	generations := 0
+	for suffix != "" {
+		operator := suffix[0]
+		suffix = suffix[1:]
+		digits := len(suffix) - len(strings.TrimLeft(suffix, "0123456789"))
+		n := 1
+		if digits > 0 {
+			var err error
+			if n, err = strconv.Atoi(suffix[:digits]); err != nil {
+				return "", 0, fmt.Errorf("invalid ref expression %q: %w", expression, err)
+			}
+			suffix = suffix[digits:]
+		}
+		switch operator {
+		case '~':
+			generations += n
+		case '^':
+			if n > 1 {
+				return "", 0, fmt.Errorf("invalid ref expression %q: commits have no parent %d", expression, n)
+			}
+			generations += n
+		default:
+			return "", 0, fmt.Errorf("invalid ref expression %q", expression)
+		}
+	}
+	return name, generations, nil
+}
+
+// IsCommitHash reports whether a ref looks like a full or abbreviated commit hash of at least 4 hex digits
+func IsCommitHash(ref string) bool {
+	if len(ref) < 4 {
+		return false
+	}
+	for _, r := range ref {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestParseRefExpression(t *testing.T) {
+	tests := []struct {
+		expression  string
+		name        string
+		generations int
+		wantError   bool
+	}{
+		{expression: "main", name: "main"},
+		{expression: "main~3", name: "main", generations: 3},
+		{expression: "v1.2^", name: "v1.2", generations: 1},
+		{expression: "v1.2~2^^", name: "v1.2", generations: 4},
+		{expression: "feature/x^0", name: "feature/x"},
+		{expression: "a1b2c3d~", name: "a1b2c3d", generations: 1},
+		{expression: "main^2", wantError: true},
+		{expression: "~1", wantError: true},
+		{expression: "main~x", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			name, generations, err := ParseRefExpression(tt.expression)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("got error %v, expected error %t", err, tt.wantError)
+			}
+			if name != tt.name || generations != tt.generations {
+				t.Errorf("got %s and %d generations, expected %s and %d", name, generations, tt.name, tt.generations)
+			}
+		})
+	}
+}
+
+func TestIsCommitHash(t *testing.T) {
+	tests := []struct {
+		ref      string
+		expected bool
+	}{
+		{ref: "a1b2c3d", expected: true},
+		{ref: "A1B2", expected: true},
+		{ref: "a1b", expected: false},
+		{ref: "main", expected: false},
+		{ref: "deadbeefcafe0123456789abcdef0123456789ab", expected: true},
+		{ref: "v1.2", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			if got := IsCommitHash(tt.ref); got != tt.expected {
+				t.Errorf("got %t, expected %t", got, tt.expected)
+			}
+		})
+	}
+}
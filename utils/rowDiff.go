@@ -0,0 +1,266 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// DiffTables compares the rows of two versions of a structured object.
+// Rows are aligned by the key columns, with duplicate keys aligned in order of appearance.
+// Without key columns, or if a key column is missing from either version, rows are aligned by position.
+// Rows are modified if a value of a column present in both versions differs.
+func DiffTables(base, compare *models.QueryResultTable, keyColumns []string) models.ObjectRowDiff {
+	diff := models.ObjectRowDiff{
+		KeyColumns: keyColumns,
+		Columns:    slices.Clone(compare.Columns),
+		Changes:    []models.RowChange{},
+	}
+	baseIndex := columnIndexes(base.Columns)
+	compareIndex := columnIndexes(compare.Columns)
+	var shared []string
+	for _, column := range compare.Columns {
+		if _, ok := baseIndex[column]; ok {
+			shared = append(shared, column)
+		} else {
+			diff.AddedColumns = append(diff.AddedColumns, column)
+		}
+	}
+	for _, column := range base.Columns {
+		if _, ok := compareIndex[column]; !ok {
+			diff.RemovedColumns = append(diff.RemovedColumns, column)
+			diff.Columns = append(diff.Columns, column)
+		}
+	}
+
+	diff.Positional = len(keyColumns) == 0
+	for _, column := range keyColumns {
+		_, inBase := baseIndex[column]
+		_, inCompare := compareIndex[column]
+		if !inBase || !inCompare {
+			diff.Positional = true
+		}
+	}
+
+	// Queue the base rows of each key, so duplicate keys are aligned in order
+	baseKeys := tableRowKeys(base, keyColumns, diff.Positional)
+	compareKeys := tableRowKeys(compare, keyColumns, diff.Positional)
+	pending := make(map[string][]int)
+	for i, key := range baseKeys {
+		pending[key] = append(pending[key], i)
+	}
+
+	for i, key := range compareKeys {
+		compareRow := i
+		queue := pending[key]
+		if len(queue) == 0 {
+			diff.Added++
+			diff.Changes = append(diff.Changes, models.RowChange{
+				Type:       models.RowChangeAdded,
+				Key:        key,
+				CompareRow: &compareRow,
+				New:        rowObject(compare, i),
+			})
+			continue
+		}
+		baseRow := queue[0]
+		pending[key] = queue[1:]
+
+		var columns []models.ColumnChange
+		for _, column := range shared {
+			oldValue := base.Rows[baseRow][baseIndex[column]]
+			newValue := compare.Rows[i][compareIndex[column]]
+			if !rowValuesEqual(oldValue, newValue) {
+				columns = append(columns, models.ColumnChange{Column: column, Old: oldValue, New: newValue})
+			}
+		}
+		if len(columns) == 0 {
+			diff.Unchanged++
+			continue
+		}
+		diff.Modified++
+		diff.Changes = append(diff.Changes, models.RowChange{
+			Type:       models.RowChangeModified,
+			Key:        key,
+			BaseRow:    &baseRow,
+			CompareRow: &compareRow,
+			Old:        rowObject(base, baseRow),
+			New:        rowObject(compare, i),
+			Columns:    columns,
+		})
+	}
+
+	for i, key := range baseKeys {
+		if !slices.Contains(pending[key], i) {
+			continue
+		}
+		baseRow := i
+		diff.Removed++
+		diff.Changes = append(diff.Changes, models.RowChange{
+			Type:    models.RowChangeRemoved,
+			Key:     key,
+			BaseRow: &baseRow,
+			Old:     rowObject(base, i),
+		})
+	}
+	return diff
+}
+
+// tableRowKeys returns the key of every row, formatted as `column=value` pairs, or the
+// position of every row, formatted as `#n`, when rows are aligned by position
+func tableRowKeys(table *models.QueryResultTable, keyColumns []string, positional bool) []string {
+	index := columnIndexes(table.Columns)
+	keys := make([]string, len(table.Rows))
+	for i, row := range table.Rows {
+		if positional {
+			keys[i] = "#" + strconv.Itoa(i+1)
+			continue
+		}
+		parts := make([]string, len(keyColumns))
+		for j, column := range keyColumns {
+			var value models.JSONValue
+			if k, ok := index[column]; ok && k < len(row) {
+				value = row[k]
+			}
+			parts[j] = column + "=" + formatRowValue(value)
+		}
+		keys[i] = strings.Join(parts, ", ")
+	}
+	return keys
+}
+
+func columnIndexes(columns []string) map[string]int {
+	index := make(map[string]int, len(columns))
+	for i, column := range columns {
+		index[column] = i
+	}
+	return index
+}
+
+// rowObject returns a row as an object keyed by column
+func rowObject(table *models.QueryResultTable, row int) models.JSONObject {
+	object := make(models.JSONObject, len(table.Columns))
+	for i, column := range table.Columns {
+		if i < len(table.Rows[row]) {
+			object[column] = table.Rows[row][i]
+		} else {
+			object[column] = nil
+		}
+	}
+	return object
+}
+
+// rowValuesEqual compares two values, treating numbers with the same value as equal
+func rowValuesEqual(a, b models.JSONValue) bool {
+	if x, ok := rowNumber(a); ok {
+		if y, ok := rowNumber(b); ok {
+			return x == y
+		}
+	}
+	return formatRowValue(a) == formatRowValue(b)
+}
+
+func rowNumber(value models.JSONValue) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// formatRowValue formats a value as JSON text
+func formatRowValue(value models.JSONValue) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// WriteRowDiffText writes a row diff in a unified diff style. Every change is introduced by an
+// "@@ key type @@" line, followed by the old row prefixed with "-" and the new row prefixed with "+".
+func WriteRowDiffText(w io.Writer, diff *models.RowDiff) error {
+	var buf bytes.Buffer
+	for _, object := range diff.Objects {
+		fmt.Fprintf(&buf, "--- %s:%s\n", diff.BaseRef, object.Path)
+		fmt.Fprintf(&buf, "+++ %s:%s\n", diff.CompareRef, object.Path)
+		if object.Error != "" {
+			fmt.Fprintf(&buf, "! %s\n", object.Error)
+			continue
+		}
+		alignment := "position"
+		if !object.Positional {
+			alignment = strings.Join(object.KeyColumns, ", ")
+		}
+		fmt.Fprintf(&buf, "# %d added, %d removed, %d modified, %d unchanged (aligned by %s)\n",
+			object.Added, object.Removed, object.Modified, object.Unchanged, alignment)
+		if len(object.AddedColumns) > 0 {
+			fmt.Fprintf(&buf, "# added columns: %s\n", strings.Join(object.AddedColumns, ", "))
+		}
+		if len(object.RemovedColumns) > 0 {
+			fmt.Fprintf(&buf, "# removed columns: %s\n", strings.Join(object.RemovedColumns, ", "))
+		}
+
+		for _, change := range object.Changes {
+			header := fmt.Sprintf("@@ %s %s", change.Key, change.Type)
+			if change.Type == models.RowChangeModified {
+				names := make([]string, len(change.Columns))
+				for i, column := range change.Columns {
+					names[i] = column.Column
+				}
+				header += ": " + strings.Join(names, ", ")
+			}
+			buf.WriteString(header + " @@\n")
+
+			for _, line := range []struct {
+				prefix string
+				row    models.JSONObject
+			}{{"-", change.Old}, {"+", change.New}} {
+				if line.row == nil {
+					continue
+				}
+				text, err := orderedRowObject(object.Columns, line.row)
+				if err != nil {
+					return err
+				}
+				buf.WriteString(line.prefix)
+				buf.Write(text)
+				buf.WriteByte('\n')
+			}
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// orderedRowObject encodes the columns of a row object that are present, in column order
+func orderedRowObject(columns []string, row models.JSONObject) ([]byte, error) {
+	var present []string
+	var values []models.JSONValue
+	for _, column := range columns {
+		if value, ok := row[column]; ok {
+			present = append(present, column)
+			values = append(values, value)
+		}
+	}
+	return marshalOrderedRow(present, values)
+}
+
+// WriteRowDiffJSON writes a row diff as indented JSON.
+func WriteRowDiffJSON(w io.Writer, diff *models.RowDiff) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(diff); err != nil {
+		return fmt.Errorf("failed to write row diff: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+func decodeTestTable(t *testing.T, data []byte, format TableFormat) *models.QueryResultTable {
+	t.Helper()
+	table, err := DecodeTable(data, format)
+	if err != nil {
+		t.Fatalf("decoding %s: %v", format, err)
+	}
+	return table
+}
+
+func TestDiffTables(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"id":   map[string]interface{}{"type": "integer"},
+			"name": map[string]interface{}{"type": []interface{}{"string", "null"}},
+		},
+		"required": []interface{}{"id"},
+	}
+	parquet := func(rows [][]models.JSONValue) []byte {
+		data, err := ConvertRowsToParquet([]string{"id", "name"}, rows, schema, 1)
+		if err != nil {
+			t.Fatalf("writing Parquet: %v", err)
+		}
+		return data
+	}
+	csvBase := []byte("id,name,population\n1,Oslo,709000\n2,Bergen,286000\n3,Trondheim,212000\n")
+	csvCompare := []byte("id,name,population\n1,Oslo,717000\n3,Trondheim,212000\n4,Stavanger,146000\n")
+
+	tests := []struct {
+		name                      string
+		base, compare             []byte
+		baseFormat, compareFormat TableFormat
+		key                       []string
+		added, removed, modified  int
+		addedColumns              int
+		// Columns changed in modified rows, in order, if checked
+		changedColumns []string
+	}{
+		{
+			name: "csv by key", base: csvBase, compare: csvCompare,
+			baseFormat: TableFormatCSV, compareFormat: TableFormatCSV, key: []string{"id"},
+			added: 1, removed: 1, modified: 1,
+			changedColumns: []string{"population"},
+		},
+		{
+			name: "csv by position", base: csvBase, compare: csvCompare,
+			baseFormat: TableFormatCSV, compareFormat: TableFormatCSV,
+			modified: 3,
+		},
+		{
+			// Numbers compare by value and blank NDJSON lines are skipped
+			name:           "json against ndjson",
+			base:           []byte(`[{"id": 1, "tags": ["a"], "score": 1.0}, {"id": 2, "tags": [], "score": 2}]`),
+			compare:        []byte("{\"id\": 1, \"tags\": [\"a\", \"b\"], \"score\": 1, \"extra\": true}\n\n{\"id\": 2, \"tags\": [], \"score\": 2}\n"),
+			baseFormat:     TableFormatJSON,
+			compareFormat:  TableFormatNDJSON,
+			key:            []string{"id"},
+			modified:       1,
+			addedColumns:   1,
+			changedColumns: []string{"tags"},
+		},
+		{
+			name:           "parquet with optional columns",
+			base:           parquet([][]models.JSONValue{{json.Number("1"), "Oslo"}, {json.Number("2"), nil}}),
+			compare:        parquet([][]models.JSONValue{{json.Number("1"), "Oslo"}, {json.Number("2"), "Bergen"}}),
+			baseFormat:     TableFormatParquet,
+			compareFormat:  TableFormatParquet,
+			key:            []string{"id"},
+			modified:       1,
+			changedColumns: []string{"name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffTables(decodeTestTable(t, tt.base, tt.baseFormat), decodeTestTable(t, tt.compare, tt.compareFormat), tt.key)
+			if diff.Added != tt.added || diff.Removed != tt.removed || diff.Modified != tt.modified {
+				t.Errorf("got %d added, %d removed and %d modified rows, expected %d, %d and %d",
+					diff.Added, diff.Removed, diff.Modified, tt.added, tt.removed, tt.modified)
+			}
+			if len(diff.AddedColumns) != tt.addedColumns {
+				t.Errorf("got added columns %q, expected %d", diff.AddedColumns, tt.addedColumns)
+			}
+			if tt.changedColumns != nil {
+				var changed []string
+				for _, change := range diff.Changes {
+					for _, column := range change.Columns {
+						changed = append(changed, column.Column)
+					}
+				}
+				if !slices.Equal(changed, tt.changedColumns) {
+					t.Errorf("got changed columns %q, expected %q", changed, tt.changedColumns)
+				}
+			}
+		})
+	}
+}
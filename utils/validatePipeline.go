@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// ValidatePipelineDefinition checks a pipeline definition without contacting the API.
+// A stage that uses its input (Write) must follow a stage that passes its result on (Read),
+// so the first stage cannot use its input. Every stage must reference what it works on,
+// and live pipelines must not also be started by time triggers. All problems are returned at once.
+func ValidatePipelineDefinition(stages []models.PipelineStage, live bool, schedule *models.WorkflowSchedule) []models.PipelineProblem {
+	var problems []models.PipelineProblem
+	add := func(stage int, format string, args ...interface{}) {
+		problems = append(problems, models.PipelineProblem{Stage: stage, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(stages) == 0 {
+		add(-1, "a pipeline requires at least one stage")
+	}
+
+	previousReads := false
+	for i, stage := range stages {
+		var common models.CommonProperties
+		switch s := stage.(type) {
+		case *models.PipelineStageAction:
+			common = s.CommonProperties
+			if s.Executable == "" {
+				add(i, "action stage requires an executable")
+			}
+		case *models.PipelineStageConnection:
+			common = s.CommonProperties
+			if s.Connection.ID == "" {
+				add(i, "connection stage requires a connection")
+			}
+		case *models.PipelineStageRepository:
+			common = s.CommonProperties
+			if s.Repository.Slug == "" {
+				add(i, "repository stage requires a repository")
+			}
+		default:
+			add(i, "unknown stage type %q", stage.GetType())
+			previousReads = false
+			continue
+		}
+
+		if common.Write {
+			switch {
+			case i == 0:
+				add(i, "the first stage has no input to write, as no stage precedes it")
+			case !previousReads:
+				add(i, "stage writes its input, but stage %d does not read and pass on its result", i)
+			}
+		}
+		previousReads = common.Read
+	}
+
+	if live && schedule != nil {
+		for i, trigger := range schedule.Triggers {
+			if trigger.GetType() == "time" {
+				add(-1, "live pipelines run continuously and must not have time triggers (trigger %d)", i)
+			}
+		}
+	}
+	return problems
+}
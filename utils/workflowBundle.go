@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"slices"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// WorkflowSpecReferences lists the connection IDs, repository slugs and executables a workflow spec refers to,
+// including the repositories of its repository event triggers. Each list is sorted and free of duplicates.
+func WorkflowSpecReferences(spec models.WorkflowSpec) (connections, repositories, executables []string) {
+	add := func(list *[]string, value string) {
+		if value != "" && !slices.Contains(*list, value) {
+			*list = append(*list, value)
+		}
+	}
+
+	if spec.Import != nil {
+		add(&connections, spec.Import.Connection)
+		add(&repositories, spec.Import.Repository)
+	}
+	if spec.Export != nil {
+		add(&connections, spec.Export.Connection)
+		add(&repositories, spec.Export.Repository)
+	}
+	if spec.Action != nil {
+		add(&executables, spec.Action.Executable)
+		add(&repositories, spec.Action.Repository)
+	}
+	if spec.Pipeline != nil {
+		for _, stage := range spec.Pipeline.Stages {
+			add(&connections, stage.Connection)
+			add(&repositories, stage.Repository)
+			add(&executables, stage.Executable)
+		}
+	}
+	if spec.Schedule != nil {
+		for _, trigger := range spec.Schedule.Triggers {
+			if trigger.Repository != nil {
+				add(&repositories, *trigger.Repository)
+			}
+		}
+	}
+
+	slices.Sort(connections)
+	slices.Sort(repositories)
+	slices.Sort(executables)
+	return connections, repositories, executables
+}
+
+// RemapWorkflowSpec returns a copy of a workflow spec whose connection IDs, repository slugs and
+// workflow run trigger workflows are replaced according to the mapping. The spec itself is not modified.
+func RemapWorkflowSpec(spec models.WorkflowSpec, mapping models.WorkflowBundleMapping) models.WorkflowSpec {
+	remap := func(m map[string]string, value string) string {
+		if mapped, ok := m[value]; ok {
+			return mapped
+		}
+		return value
+	}
+	remapPointer := func(m map[string]string, value *string) *string {
+		if value == nil {
+			return nil
+		}
+		mapped := remap(m, *value)
+		return &mapped
+	}
+
+	if spec.Import != nil {
+		config := *spec.Import
+		config.Connection = remap(mapping.Connections, config.Connection)
+		config.Repository = remap(mapping.Repositories, config.Repository)
+		spec.Import = &config
+	}
+	if spec.Export != nil {
+		config := *spec.Export
+		config.Connection = remap(mapping.Connections, config.Connection)
+		config.Repository = remap(mapping.Repositories, config.Repository)
+		spec.Export = &config
+	}
+	if spec.Action != nil {
+		config := *spec.Action
+		if config.Repository != "" {
+			config.Repository = remap(mapping.Repositories, config.Repository)
+		}
+		spec.Action = &config
+	}
+	if spec.Pipeline != nil {
+		config := *spec.Pipeline
+		config.Stages = slices.Clone(config.Stages)
+		for i := range config.Stages {
+			stage := &config.Stages[i]
+			if stage.Connection != "" {
+				stage.Connection = remap(mapping.Connections, stage.Connection)
+			}
+			if stage.Repository != "" {
+				stage.Repository = remap(mapping.Repositories, stage.Repository)
+			}
+		}
+		spec.Pipeline = &config
+	}
+	if spec.Schedule != nil {
+		schedule := *spec.Schedule
+		schedule.Triggers = slices.Clone(schedule.Triggers)
+		for i := range schedule.Triggers {
+			trigger := &schedule.Triggers[i]
+			trigger.Repository = remapPointer(mapping.Repositories, trigger.Repository)
+			trigger.Workflow = remapPointer(mapping.Workflows, trigger.Workflow)
+		}
+		spec.Schedule = &schedule
+	}
+	return spec
+}
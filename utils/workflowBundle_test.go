@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+func bundleGraphWorkflows(t *testing.T) models.WorkflowBundle {
+	t.Helper()
+	bundle := models.WorkflowBundle{Version: models.WorkflowBundleVersion}
+	for _, workflow := range decodeGraphWorkflows(t) {
+		spec, err := WorkflowToSpec(workflow)
+		if err != nil {
+			t.Fatalf("converting workflow %s: %v", workflow.ID, err)
+		}
+		bundle.Workflows = append(bundle.Workflows, models.WorkflowBundleWorkflow{ID: workflow.ID, Spec: spec})
+	}
+	return bundle
+}
+
+func TestWorkflowSpecReferences(t *testing.T) {
+	bundle := bundleGraphWorkflows(t)
+	connections, repositories, executables := WorkflowSpecReferences(bundle.Workflows[1].Spec)
+	if len(connections) != 0 {
+		t.Errorf("got connections %q, expected none", connections)
+	}
+	if expected := []string{"lakes", "reports"}; !slices.Equal(repositories, expected) {
+		t.Errorf("got repositories %q, expected %q", repositories, expected)
+	}
+	if expected := []string{"/transform.js"}; !slices.Equal(executables, expected) {
+		t.Errorf("got executables %q, expected %q", executables, expected)
+	}
+}
+
+func TestRemapWorkflowSpec(t *testing.T) {
+	bundle := bundleGraphWorkflows(t)
+	mapping := models.WorkflowBundleMapping{
+		Connections:  map[string]string{"c1": "prod-c1", "c2": "prod-c2"},
+		Repositories: map[string]string{"lakes": "prod-lakes"},
+		Workflows:    map[string]string{"3": "prod-3"},
+	}
+	imported := RemapWorkflowSpec(bundle.Workflows[0].Spec, mapping)
+	pipeline := RemapWorkflowSpec(bundle.Workflows[1].Spec, mapping)
+
+	tests := []struct {
+		name     string
+		got      string
+		expected string
+	}{
+		{name: "import connection", got: imported.Import.Connection, expected: "prod-c1"},
+		{name: "import repository", got: imported.Import.Repository, expected: "prod-lakes"},
+		{name: "run trigger workflow", got: *imported.Schedule.Triggers[0].Workflow, expected: "prod-3"},
+		{name: "original run trigger workflow", got: *bundle.Workflows[0].Spec.Schedule.Triggers[0].Workflow, expected: "3"},
+		{name: "first stage repository", got: pipeline.Pipeline.Stages[0].Repository, expected: "prod-lakes"},
+		{name: "unmapped stage repository", got: pipeline.Pipeline.Stages[2].Repository, expected: "reports"},
+		{name: "repository trigger", got: *pipeline.Schedule.Triggers[0].Repository, expected: "prod-lakes"},
+		{name: "original stage repository", got: bundle.Workflows[1].Spec.Pipeline.Stages[0].Repository, expected: "lakes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Errorf("got %q, expected %q", tt.got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWorkflowBundleRoundTrip(t *testing.T) {
+	bundle := bundleGraphWorkflows(t)
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("encoding bundle: %v", err)
+	}
+	var decoded models.WorkflowBundle
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("decoding bundle: %v", err)
+	}
+	if len(decoded.Workflows) != len(bundle.Workflows) {
+		t.Errorf("got %d workflows, expected %d", len(decoded.Workflows), len(bundle.Workflows))
+	}
+}
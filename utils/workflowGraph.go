@@ -0,0 +1,410 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// BuildWorkflowGraph builds the dependency graph of a set of workflows:
+//   - workflow -> workflow for workflow run triggers
+//   - workflow -> repository branch for imports and pipeline stages that write to a repository
+//   - repository branch -> workflow for repository triggers
+//
+// Triggers without a workflow, repository or ref match every workflow, repository or branch.
+// Cycles are detected over the edges that propagate.
+func BuildWorkflowGraph(workflows []models.Workflow) *models.WorkflowGraph {
+	b := &workflowGraphBuilder{
+		graph: &models.WorkflowGraph{
+			Nodes:  []models.WorkflowGraphNode{},
+			Edges:  []models.WorkflowGraphEdge{},
+			Cycles: []models.WorkflowGraphCycle{},
+		},
+		nodes: make(map[string]bool),
+		edges: make(map[models.WorkflowGraphEdge]bool),
+	}
+
+	for _, workflow := range workflows {
+		b.addNode(models.WorkflowGraphNode{
+			ID:           workflowNodeID(workflow.ID),
+			Kind:         models.WorkflowGraphNodeWorkflow,
+			Label:        workflow.Name,
+			WorkflowID:   workflow.ID,
+			WorkflowType: workflow.Type,
+		})
+	}
+
+	// Writes are added first, so wildcard repository triggers can match every written branch
+	for i := range workflows {
+		for _, target := range workflowWriteTargets(&workflows[i]) {
+			id := b.addRepositoryNode(target[0], target[1])
+			b.addEdge(models.WorkflowGraphEdge{
+				From: workflowNodeID(workflows[i].ID),
+				To:   id,
+				Kind: models.WorkflowGraphEdgeWrites,
+			})
+		}
+	}
+
+	for _, workflow := range workflows {
+		if workflow.Schedule == nil {
+			continue
+		}
+		target := workflowNodeID(workflow.ID)
+		for _, trigger := range workflow.Schedule.Triggers {
+			switch t := trigger.(type) {
+			case *models.WorkflowRunTrigger:
+				b.addRunTrigger(workflows, target, *t)
+			case models.WorkflowRunTrigger:
+				b.addRunTrigger(workflows, target, t)
+			case *models.RepositoryTrigger:
+				b.addRepositoryTrigger(target, *t)
+			case models.RepositoryTrigger:
+				b.addRepositoryTrigger(target, t)
+			}
+		}
+	}
+
+	b.graph.Cycles = FindWorkflowGraphCycles(b.graph)
+	return b.graph
+}
+
+// workflowGraphBuilder deduplicates the nodes and edges of a graph being built
+type workflowGraphBuilder struct {
+	graph *models.WorkflowGraph
+	nodes map[string]bool
+	edges map[models.WorkflowGraphEdge]bool
+}
+
+func (b *workflowGraphBuilder) addNode(node models.WorkflowGraphNode) {
+	if b.nodes[node.ID] {
+		return
+	}
+	b.nodes[node.ID] = true
+	b.graph.Nodes = append(b.graph.Nodes, node)
+}
+
+func (b *workflowGraphBuilder) addEdge(edge models.WorkflowGraphEdge) {
+	if b.edges[edge] {
+		return
+	}
+	b.edges[edge] = true
+	b.graph.Edges = append(b.graph.Edges, edge)
+}
+
+func (b *workflowGraphBuilder) addRepositoryNode(repository, branch string) string {
+	id := repositoryNodeID(repository, branch)
+	label := repository
+	if branch != "" {
+		label += "@" + branch
+	}
+	b.addNode(models.WorkflowGraphNode{
+		ID:         id,
+		Kind:       models.WorkflowGraphNodeRepository,
+		Label:      label,
+		Repository: repository,
+		Branch:     branch,
+	})
+	return id
+}
+
+func (b *workflowGraphBuilder) addRunTrigger(workflows []models.Workflow, target string, trigger models.WorkflowRunTrigger) {
+	var sources []string
+	if trigger.Workflow != nil {
+		source := workflowNodeID(*trigger.Workflow)
+		// Keep references to workflows that are not part of the graph visible
+		b.addNode(models.WorkflowGraphNode{
+			ID:         source,
+			Kind:       models.WorkflowGraphNodeWorkflow,
+			Label:      *trigger.Workflow,
+			WorkflowID: *trigger.Workflow,
+		})
+		sources = append(sources, source)
+	} else {
+		for _, workflow := range workflows {
+			sources = append(sources, workflowNodeID(workflow.ID))
+		}
+	}
+
+	for _, source := range sources {
+		b.addEdge(models.WorkflowGraphEdge{
+			From:  source,
+			To:    target,
+			Kind:  models.WorkflowGraphEdgeRunEvent,
+			Event: string(trigger.Event),
+		})
+	}
+}
+
+func (b *workflowGraphBuilder) addRepositoryTrigger(target string, trigger models.RepositoryTrigger) {
+	ref := ""
+	if trigger.Ref != nil {
+		ref = strings.TrimPrefix(*trigger.Ref, "refs/heads/")
+	}
+
+	// A trigger on a specific branch is shown even if no workflow writes to it
+	if trigger.Repository != nil && ref != "" {
+		b.addRepositoryNode(*trigger.Repository, ref)
+	}
+
+	matched := false
+	for _, node := range b.graph.Nodes {
+		if node.Kind != models.WorkflowGraphNodeRepository {
+			continue
+		}
+		if trigger.Repository != nil && node.Repository != *trigger.Repository {
+			continue
+		}
+		// Nodes with an unknown branch may be any branch
+		if ref != "" && node.Branch != "" && node.Branch != ref {
+			continue
+		}
+		matched = true
+		b.addEdge(models.WorkflowGraphEdge{
+			From:  node.ID,
+			To:    target,
+			Kind:  models.WorkflowGraphEdgeRepositoryEvent,
+			Event: string(trigger.Event),
+		})
+	}
+
+	if !matched && trigger.Repository != nil {
+		b.addEdge(models.WorkflowGraphEdge{
+			From:  b.addRepositoryNode(*trigger.Repository, ref),
+			To:    target,
+			Kind:  models.WorkflowGraphEdgeRepositoryEvent,
+			Event: string(trigger.Event),
+		})
+	}
+}
+
+// workflowWriteTargets returns the repository and branch pairs a workflow writes to
+func workflowWriteTargets(workflow *models.Workflow) [][2]string {
+	var targets [][2]string
+	if config, ok := workflow.AsImport(); ok {
+		targets = append(targets, [2]string{config.Repository.Slug, branchOrDefault(config.Branch, config.Repository)})
+	}
+	if config, ok := workflow.AsPipeline(); ok {
+		for _, stage := range config.Stages {
+			repositoryStage, ok := stage.(*models.PipelineStageRepository)
+			if ok && repositoryStage.Write {
+				targets = append(targets, [2]string{repositoryStage.Repository.Slug, branchOrDefault(repositoryStage.Branch, repositoryStage.Repository)})
+			}
+		}
+	}
+	return targets
+}
+
+func branchOrDefault(branch string, repository models.Repository) string {
+	if branch != "" {
+		return branch
+	}
+	return repository.DefaultBranch
+}
+
+func workflowNodeID(workflowID string) string {
+	return "workflow:" + workflowID
+}
+
+func repositoryNodeID(repository, branch string) string {
+	if branch == "" {
+		return "repository:" + repository
+	}
+	return "repository:" + repository + "@" + branch
+}
+
+// FindWorkflowGraphCycles returns the loops of a graph over the edges that propagate.
+// Each strongly connected component with more than one node, or with a node that
+// triggers itself, is reported once as its shortest loop through its first node in the graph.
+func FindWorkflowGraphCycles(graph *models.WorkflowGraph) []models.WorkflowGraphCycle {
+	adjacency := make(map[string][]string)
+	for _, edge := range graph.Edges {
+		if edge.Propagates() {
+			adjacency[edge.From] = append(adjacency[edge.From], edge.To)
+		}
+	}
+
+	// Tarjan's strongly connected components algorithm
+	index := 0
+	indices := make(map[string]int)
+	lowlinks := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var connect func(node string)
+	connect = func(node string) {
+		indices[node] = index
+		lowlinks[node] = index
+		index++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, next := range adjacency[node] {
+			if _, visited := indices[next]; !visited {
+				connect(next)
+				lowlinks[node] = min(lowlinks[node], lowlinks[next])
+			} else if onStack[next] {
+				lowlinks[node] = min(lowlinks[node], indices[next])
+			}
+		}
+
+		if lowlinks[node] == indices[node] {
+			var component []string
+			for {
+				last := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[last] = false
+				component = append(component, last)
+				if last == node {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+	for _, node := range graph.Nodes {
+		if _, visited := indices[node.ID]; !visited {
+			connect(node.ID)
+		}
+	}
+
+	// Loops start at the member that comes first in the graph, which is a workflow if there is one
+	position := make(map[string]int, len(graph.Nodes))
+	for i, node := range graph.Nodes {
+		position[node.ID] = i
+	}
+
+	cycles := []models.WorkflowGraphCycle{}
+	for _, component := range components {
+		sort.Slice(component, func(i, j int) bool { return position[component[i]] < position[component[j]] })
+		members := make(map[string]bool, len(component))
+		for _, node := range component {
+			members[node] = true
+		}
+		if path := shortestLoop(component[0], adjacency, members); path != nil {
+			cycles = append(cycles, models.WorkflowGraphCycle{Nodes: path})
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool { return position[cycles[i].Nodes[0]] < position[cycles[j].Nodes[0]] })
+	return cycles
+}
+
+// shortestLoop returns the shortest path from start back to itself within members, or nil
+func shortestLoop(start string, adjacency map[string][]string, members map[string]bool) []string {
+	previous := map[string]string{}
+	queue := []string{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range adjacency[node] {
+			if !members[next] {
+				continue
+			}
+			if next == start {
+				path := []string{node}
+				for path[0] != start {
+					path = append([]string{previous[path[0]]}, path...)
+				}
+				return path
+			}
+			if _, seen := previous[next]; !seen {
+				previous[next] = node
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+// WorkflowGraphToDOT renders a graph in Graphviz DOT format. Edges that are part of a
+// cycle are red, and edges that do not propagate are dashed.
+func WorkflowGraphToDOT(graph *models.WorkflowGraph) string {
+	var b strings.Builder
+	cycleEdges := workflowGraphCycleEdges(graph)
+
+	b.WriteString("digraph workflows {\n  rankdir=LR;\n")
+	for _, node := range graph.Nodes {
+		shape := "box"
+		if node.Kind == models.WorkflowGraphNodeRepository {
+			shape = "cylinder"
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s];\n", dotQuote(node.ID), dotQuote(node.Label), shape)
+	}
+	for _, edge := range graph.Edges {
+		attributes := []string{"label=" + dotQuote(workflowGraphEdgeLabel(edge))}
+		if edge.Propagates() && cycleEdges[[2]string{edge.From, edge.To}] {
+			attributes = append(attributes, "color=red")
+		}
+		if !edge.Propagates() {
+			attributes = append(attributes, "style=dashed")
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", dotQuote(edge.From), dotQuote(edge.To), strings.Join(attributes, ", "))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// WorkflowGraphToMermaid renders a graph as a Mermaid flowchart. Edges that are part of
+// a cycle are red, and edges that do not propagate are dotted.
+func WorkflowGraphToMermaid(graph *models.WorkflowGraph) string {
+	var b strings.Builder
+	cycleEdges := workflowGraphCycleEdges(graph)
+
+	// Node IDs may contain characters Mermaid does not accept, so nodes are numbered
+	ids := make(map[string]string, len(graph.Nodes))
+	b.WriteString("flowchart LR\n")
+	for i, node := range graph.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+		label := mermaidQuote(node.Label)
+		if node.Kind == models.WorkflowGraphNodeRepository {
+			fmt.Fprintf(&b, "  %s[(%s)]\n", ids[node.ID], label)
+		} else {
+			fmt.Fprintf(&b, "  %s[%s]\n", ids[node.ID], label)
+		}
+	}
+
+	var redLinks []string
+	for i, edge := range graph.Edges {
+		arrow := "-->"
+		if !edge.Propagates() {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "  %s %s|%s| %s\n", ids[edge.From], arrow, mermaidQuote(workflowGraphEdgeLabel(edge)), ids[edge.To])
+		if edge.Propagates() && cycleEdges[[2]string{edge.From, edge.To}] {
+			redLinks = append(redLinks, fmt.Sprint(i))
+		}
+	}
+	if len(redLinks) > 0 {
+		fmt.Fprintf(&b, "  linkStyle %s stroke:red\n", strings.Join(redLinks, ","))
+	}
+	return b.String()
+}
+
+// workflowGraphCycleEdges returns the edges that are part of a cycle
+func workflowGraphCycleEdges(graph *models.WorkflowGraph) map[[2]string]bool {
+	edges := make(map[[2]string]bool)
+	for _, cycle := range graph.Cycles {
+		for i, node := range cycle.Nodes {
+			edges[[2]string{node, cycle.Nodes[(i+1)%len(cycle.Nodes)]}] = true
+		}
+	}
+	return edges
+}
+
+func workflowGraphEdgeLabel(edge models.WorkflowGraphEdge) string {
+	if edge.Event != "" {
+		return edge.Event
+	}
+	return string(edge.Kind)
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s) + `"`
+}
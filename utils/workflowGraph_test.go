@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// graphWorkflows form a loop: the import writes to lakes@main, which triggers the pipeline,
+// which writes to reports@main, which triggers the export, whose run triggers the import.
+const graphWorkflows = `[
+	{
+		"id": "1", "name": "Import lakes", "type": "import",
+		"workflowable": {"connection": {"id": "c1"}, "repository": {"slug": "lakes"}, "branch": "main", "path": "/"},
+		"schedule": {"triggers": [{"type": "workflow-run-event", "event": "post-workflow-run", "workflow": "3"}]}
+	},
+	{
+		"id": "2", "name": "Transform", "type": "pipeline",
+		"workflowable": {"live": false, "stages": [
+			{"type": "repository", "read": true, "write": false, "repository": {"slug": "lakes"}, "branch": "main", "path": "/"},
+			{"type": "action", "read": true, "write": true, "executable": "/transform.js"},
+			{"type": "repository", "read": false, "write": true, "repository": {"slug": "reports"}, "branch": "main", "path": "/"}
+		]},
+		"schedule": {"triggers": [{"type": "repository-event", "event": "post-commit", "repository": "lakes", "ref": "main"}]}
+	},
+	{
+		"id": "3", "name": "Export reports", "type": "export",
+		"workflowable": {"connection": {"id": "c2"}, "repository": {"slug": "reports"}, "branch": "main", "path": "/", "recursive": true},
+		"schedule": {"triggers": [{"type": "repository-event", "event": "post-commit", "repository": "reports"}]}
+	},
+	{
+		"id": "4", "name": "Audit branches", "type": "action",
+		"workflowable": {"executable": "/audit.js"},
+		"schedule": {"triggers": [{"type": "repository-event", "event": "post-create-branch"}]}
+	}
+]`
+
+func decodeGraphWorkflows(t *testing.T) []models.Workflow {
+	t.Helper()
+	var workflows []models.Workflow
+	if err := json.Unmarshal([]byte(graphWorkflows), &workflows); err != nil {
+		t.Fatalf("decoding workflows: %v", err)
+	}
+	return workflows
+}
+
+func TestBuildWorkflowGraphCycles(t *testing.T) {
+	tests := []struct {
+		name     string
+		without  string
+		nodes    int
+		edges    int
+		expected []string
+	}{
+		{
+			name:     "loop through repositories and a run trigger",
+			nodes:    6,
+			edges:    7,
+			expected: []string{"workflow:1 -> repository:lakes@main -> workflow:2 -> repository:reports@main -> workflow:3"},
+		},
+		{
+			// The run trigger of the import still references the removed export
+			name:    "loop broken by removing the export",
+			without: "3",
+			nodes:   6,
+			edges:   6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var workflows []models.Workflow
+			for _, workflow := range decodeGraphWorkflows(t) {
+				if workflow.ID != tt.without {
+					workflows = append(workflows, workflow)
+				}
+			}
+			graph := BuildWorkflowGraph(workflows)
+			if len(graph.Nodes) != tt.nodes || len(graph.Edges) != tt.edges {
+				t.Errorf("got %d nodes and %d edges, expected %d and %d", len(graph.Nodes), len(graph.Edges), tt.nodes, tt.edges)
+			}
+			var cycles []string
+			for _, cycle := range graph.Cycles {
+				cycles = append(cycles, strings.Join(cycle.Nodes, " -> "))
+			}
+			if strings.Join(cycles, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("got cycles %q, expected %q", cycles, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWorkflowGraphExportHighlightsCycles(t *testing.T) {
+	graph := BuildWorkflowGraph(decodeGraphWorkflows(t))
+	tests := []struct {
+		name     string
+		output   string
+		expected []string
+	}{
+		{
+			name:   "DOT",
+			output: WorkflowGraphToDOT(graph),
+			expected: []string{
+				`"workflow:3" -> "workflow:1" [label="post-workflow-run", color=red];`,
+				`"repository:lakes@main" -> "workflow:4" [label="post-create-branch", style=dashed];`,
+			},
+		},
+		{
+			name:   "Mermaid",
+			output: WorkflowGraphToMermaid(graph),
+			expected: []string{
+				`n4[("lakes@main")]`,
+				`n2 -->|"post-workflow-run"| n0`,
+				`linkStyle 0,1,2,3,4 stroke:red`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, line := range tt.expected {
+				if !strings.Contains(tt.output, line) {
+					t.Errorf("output is missing %s:\n%s", line, tt.output)
+				}
+			}
+		})
+	}
+}
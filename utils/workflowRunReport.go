@@ -0,0 +1,271 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/rrule"
+)
+
+// apiTimeLayouts are the layouts timestamps returned by the API are parsed with
+var apiTimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05"}
+
+// ParseAPITime parses a timestamp returned by the API. Timestamps without a zone are in UTC.
+func ParseAPITime(value string) (time.Time, error) {
+	for _, layout := range apiTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+}
+
+// ComputeWorkflowRunStats computes the statistics of the runs of a workflow started in [start, end).
+//   - A run directly following a failed run is counted as a retry, up to MaxRetries retries per failure.
+//   - A scheduled window is the time from an occurrence of a time trigger to the next one, shortened to
+//     tolerance if that is positive. It is missed if no run started in it; windows in the future are ignored.
+//   - Rules without DTSTART are anchored at the creation of the workflow.
+func ComputeWorkflowRunStats(
+	workflow models.Workflow,
+	runs []models.WorkflowRun,
+	events []models.LogEvent,
+	start, end time.Time,
+	tolerance time.Duration,
+) (models.WorkflowRunStats, error) {
+	stats := models.WorkflowRunStats{
+		WorkflowID:    workflow.ID,
+		WorkflowName:  workflow.Name,
+		MissedWindows: []string{},
+	}
+	if workflow.Schedule != nil {
+		stats.MaxRetries = workflow.Schedule.MaxRetries
+		stats.MaxRuntime = workflow.Schedule.MaxRuntime
+	}
+	now := time.Now()
+
+	type timedRun struct {
+		status   models.WorkflowStatus
+		started  time.Time
+		finished time.Time
+	}
+	all := make([]timedRun, 0, len(runs))
+	for _, run := range runs {
+		started, err := ParseAPITime(run.StartedAt)
+		if err != nil {
+			return stats, fmt.Errorf("run %s: %w", run.ID, err)
+		}
+		r := timedRun{status: run.Status, started: started}
+		if run.FinishedAt != nil && *run.FinishedAt != "" {
+			if r.finished, err = ParseAPITime(*run.FinishedAt); err != nil {
+				return stats, fmt.Errorf("run %s: %w", run.ID, err)
+			}
+		}
+		all = append(all, r)
+	}
+	slices.SortFunc(all, func(a, b timedRun) int { return a.started.Compare(b.started) })
+
+	var durations []float64
+	retries := 0
+	previousFailed := false
+	for _, run := range all {
+		inWindow := !run.started.Before(start) && run.started.Before(end)
+
+		// Retries are tracked over all runs, so failures just before the window are accounted for
+		retry := false
+		if previousFailed && (stats.MaxRetries == nil || retries < *stats.MaxRetries) {
+			retry = true
+			retries++
+		} else {
+			retries = 0
+		}
+		finished := run.status == models.WorkflowStatusComplete || run.status == models.WorkflowStatusError
+		if finished {
+			previousFailed = run.status == models.WorkflowStatusError
+		}
+		if !inWindow {
+			continue
+		}
+
+		stats.Runs++
+		if retry {
+			stats.RetriesConsumed++
+			if run.status == models.WorkflowStatusError && stats.MaxRetries != nil && retries == *stats.MaxRetries {
+				stats.RetriesExhausted++
+			}
+		}
+
+		var duration time.Duration
+		switch {
+		case !finished:
+			stats.Unfinished++
+			duration = now.Sub(run.started)
+		case run.status == models.WorkflowStatusComplete:
+			stats.Succeeded++
+		default:
+			stats.Failed++
+		}
+		if finished && !run.finished.IsZero() {
+			duration = run.finished.Sub(run.started)
+			durations = append(durations, duration.Seconds())
+		}
+		if stats.MaxRuntime != nil && duration > time.Duration(*stats.MaxRuntime)*time.Second {
+			stats.RuntimeExceeded++
+		}
+	}
+	if finished := stats.Succeeded + stats.Failed; finished > 0 {
+		stats.SuccessRate = float64(stats.Succeeded) / float64(finished)
+	}
+	slices.Sort(durations)
+	stats.DurationP50 = percentile(durations, 0.50)
+	stats.DurationP95 = percentile(durations, 0.95)
+
+	occurrences, err := scheduledOccurrences(workflow, start, end)
+	if err != nil {
+		return stats, err
+	}
+	cutoff := end
+	if now.Before(cutoff) {
+		cutoff = now
+	}
+	for i, occurrence := range occurrences {
+		if !occurrence.Before(cutoff) {
+			break
+		}
+		var deadline time.Time
+		if i+1 < len(occurrences) {
+			deadline = occurrences[i+1]
+		}
+		if tolerance > 0 && (deadline.IsZero() || occurrence.Add(tolerance).Before(deadline)) {
+			deadline = occurrence.Add(tolerance)
+		}
+		// Windows still open cannot be missed yet
+		if deadline.IsZero() || deadline.After(now) {
+			continue
+		}
+
+		stats.ScheduledWindows++
+		met := slices.ContainsFunc(all, func(run timedRun) bool {
+			return !run.started.Before(occurrence) && run.started.Before(deadline)
+		})
+		if !met {
+			stats.MissedWindows = append(stats.MissedWindows, occurrence.UTC().Format(time.RFC3339))
+		}
+	}
+
+	for _, event := range events {
+		timestamp, err := ParseAPITime(event.Timestamp)
+		if err != nil || timestamp.Before(start) || !timestamp.Before(end) {
+			continue
+		}
+		switch event.Type {
+		case models.LogEventTypeError:
+			stats.ErrorEvents++
+		case models.LogEventTypeWarning:
+			stats.WarningEvents++
+		}
+	}
+	return stats, nil
+}
+
+// scheduledOccurrences returns the occurrences of the time triggers of a workflow in [start, end),
+// followed by the first occurrence at or after end, sorted and without duplicates
+func scheduledOccurrences(workflow models.Workflow, start, end time.Time) ([]time.Time, error) {
+	if workflow.Schedule == nil {
+		return nil, nil
+	}
+	created, err := ParseAPITime(workflow.CreatedAt)
+	if err != nil {
+		created = start
+	}
+
+	var occurrences []time.Time
+	for i, trigger := range workflow.Schedule.Triggers {
+		var rule string
+		switch t := trigger.(type) {
+		case *models.TimeTrigger:
+			rule = t.RRule
+		case models.TimeTrigger:
+			rule = t.RRule
+		default:
+			continue
+		}
+		r, err := rrule.Parse(rule)
+		if err != nil {
+			return nil, fmt.Errorf("workflow %s trigger %d: %w", workflow.ID, i, err)
+		}
+		if r.DTStart.IsZero() {
+			r.DTStart = created
+		}
+		occurrences = append(occurrences, r.Between(start, end)...)
+		occurrences = append(occurrences, r.After(end, 1)...)
+	}
+	slices.SortFunc(occurrences, func(a, b time.Time) int { return a.Compare(b) })
+	return slices.CompactFunc(occurrences, time.Time.Equal), nil
+}
+
+// percentile returns the nearest-rank percentile of sorted values, or 0 if there are none
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+// workflowRunReportColumns are the columns of a run report in CSV format
+var workflowRunReportColumns = []string{
+	"workflow_id", "workflow_name", "runs", "succeeded", "failed", "unfinished", "success_rate",
+	"duration_p50_seconds", "duration_p95_seconds", "max_retries", "retries_consumed", "retries_exhausted",
+	"max_runtime", "runtime_exceeded", "scheduled_windows", "missed_windows", "error_events", "warning_events",
+}
+
+// WriteWorkflowRunReportCSV writes a run report as CSV, one line per workflow.
+// Missed windows are counted; their start times are only included in the JSON format.
+func WriteWorkflowRunReportCSV(w io.Writer, report *models.WorkflowRunReport) error {
+	optional := func(value *int) models.JSONValue {
+		if value == nil {
+			return nil
+		}
+		return float64(*value)
+	}
+
+	rows := make([][]models.JSONValue, 0, len(report.Workflows))
+	for _, stats := range report.Workflows {
+		rows = append(rows, []models.JSONValue{
+			stats.WorkflowID,
+			stats.WorkflowName,
+			float64(stats.Runs),
+			float64(stats.Succeeded),
+			float64(stats.Failed),
+			float64(stats.Unfinished),
+			stats.SuccessRate,
+			stats.DurationP50,
+			stats.DurationP95,
+			optional(stats.MaxRetries),
+			float64(stats.RetriesConsumed),
+			float64(stats.RetriesExhausted),
+			optional(stats.MaxRuntime),
+			float64(stats.RuntimeExceeded),
+			float64(stats.ScheduledWindows),
+			float64(len(stats.MissedWindows)),
+			float64(stats.ErrorEvents),
+			float64(stats.WarningEvents),
+		})
+	}
+	return WriteCSV(w, workflowRunReportColumns, rows)
+}
+
+// WriteWorkflowRunReportJSON writes a run report as indented JSON.
+func WriteWorkflowRunReportJSON(w io.Writer, report *models.WorkflowRunReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
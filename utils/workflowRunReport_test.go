@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// reportWorkflow runs daily at 06:00 and may retry a failed run once
+const reportWorkflow = `{
+	"id": "1", "name": "Daily import", "type": "import", "created_at": "2024-01-01T00:00:00Z",
+	"workflowable": {"connection": {"id": "c1"}, "repository": {"slug": "lakes"}, "branch": "main", "path": "/"},
+	"schedule": {
+		"triggers": [{"type": "time", "rrule": "FREQ=DAILY;BYHOUR=6;BYMINUTE=0;BYSECOND=0"}],
+		"max_retries": 1,
+		"max_runtime": 600
+	}
+}`
+
+// reportRuns succeed on the 1st and 4th, fail and fail again on retry on the 2nd and miss the 3rd
+const reportRuns = `[
+	{"id": "r1", "workflow_id": "1", "status": "complete", "started_at": "2024-03-01T06:00:05Z", "finished_at": "2024-03-01T06:05:05Z"},
+	{"id": "r2", "workflow_id": "1", "status": "error", "started_at": "2024-03-02T06:00:03Z", "finished_at": "2024-03-02T06:01:03Z"},
+	{"id": "r3", "workflow_id": "1", "status": "error", "started_at": "2024-03-02T06:02:00Z", "finished_at": "2024-03-02T06:14:00Z"},
+	{"id": "r4", "workflow_id": "1", "status": "complete", "started_at": "2024-03-04T06:00:10Z", "finished_at": "2024-03-04T06:02:10Z"}
+]`
+
+// reportEvents holds one error within the report window and one warning before it
+const reportEvents = `[
+	{"id": "e1", "type": "ERROR", "timestamp": "2024-03-02T06:01:03Z", "description": "Import failed"},
+	{"id": "e2", "type": "WARNING", "timestamp": "2024-02-01T06:00:00Z", "description": "Slow connection"}
+]`
+
+func computeReportStats(t *testing.T) models.WorkflowRunStats {
+	t.Helper()
+	var workflow models.Workflow
+	var runs []models.WorkflowRun
+	var events []models.LogEvent
+	for _, decode := range []struct {
+		data   string
+		target any
+	}{
+		{reportWorkflow, &workflow},
+		{reportRuns, &runs},
+		{reportEvents, &events},
+	} {
+		if err := json.Unmarshal([]byte(decode.data), decode.target); err != nil {
+			t.Fatalf("decoding report data: %v", err)
+		}
+	}
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	stats, err := ComputeWorkflowRunStats(workflow, runs, events, start, end, 0)
+	if err != nil {
+		t.Fatalf("computing statistics: %v", err)
+	}
+	return stats
+}
+
+func TestComputeWorkflowRunStats(t *testing.T) {
+	stats := computeReportStats(t)
+	tests := []struct {
+		name     string
+		got      any
+		expected any
+	}{
+		{name: "runs", got: stats.Runs, expected: 4},
+		{name: "success rate", got: stats.SuccessRate, expected: 0.5},
+		{name: "p50", got: stats.DurationP50, expected: 120.0},
+		{name: "p95", got: stats.DurationP95, expected: 720.0},
+		{name: "retries consumed", got: stats.RetriesConsumed, expected: 1},
+		{name: "retries exhausted", got: stats.RetriesExhausted, expected: 1},
+		{name: "runtime exceeded", got: stats.RuntimeExceeded, expected: 1},
+		{name: "scheduled windows", got: stats.ScheduledWindows, expected: 4},
+		{name: "error events", got: stats.ErrorEvents, expected: 1},
+		{name: "warning events", got: stats.WarningEvents, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Errorf("got %v, expected %v", tt.got, tt.expected)
+			}
+		})
+	}
+
+	if expected := []string{"2024-03-03T06:00:00Z"}; !slices.Equal(stats.MissedWindows, expected) {
+		t.Errorf("got missed windows %q, expected %q", stats.MissedWindows, expected)
+	}
+}
+
+func TestWriteWorkflowRunReport(t *testing.T) {
+	report := &models.WorkflowRunReport{
+		GeneratedAt: "2024-03-05T00:00:00Z",
+		WindowStart: "2024-03-01T00:00:00Z",
+		WindowEnd:   "2024-03-05T00:00:00Z",
+		Workflows:   []models.WorkflowRunStats{computeReportStats(t)},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteWorkflowRunReportCSV(&buf, report); err != nil {
+		t.Fatalf("writing CSV: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d CSV records, expected a header and one workflow", len(records))
+	}
+	if records[1][0] != "1" {
+		t.Errorf("got workflow ID %q, expected %q", records[1][0], "1")
+	}
+
+	buf.Reset()
+	if err := WriteWorkflowRunReportJSON(&buf, report); err != nil {
+		t.Fatalf("writing JSON: %v", err)
+	}
+	var decoded models.WorkflowRunReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("decoding JSON: %v", err)
+	}
+	if len(decoded.Workflows) != 1 || decoded.Workflows[0].Runs != 4 {
+		t.Errorf("got workflows %+v, expected the single workflow with 4 runs", decoded.Workflows)
+	}
+}
@@ -0,0 +1,322 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+	"github.com/IrminData/irmin-sdk-go/rrule"
+
+	"gopkg.in/yaml.v3"
+)
+
+// workflowSpecKeyPattern matches the key marker stored in the documentation of managed workflows.
+var workflowSpecKeyPattern = regexp.MustCompile(`\n*<!-- irmin-workflow-key: (.*?) -->\s*$`)
+
+// ParseWorkflowSpec parses a workflow spec file in YAML or JSON and validates it.
+func ParseWorkflowSpec(data []byte) (*models.WorkflowSpecFile, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var file models.WorkflowSpecFile
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow spec: %w", err)
+	}
+	if err := ValidateWorkflowSpec(&file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// ValidateWorkflowSpec checks that every workflow spec is complete and that keys are unique.
+// All problems are reported at once.
+func ValidateWorkflowSpec(file *models.WorkflowSpecFile) error {
+	var errs []error
+	keys := make(map[string]int)
+
+	for i, spec := range file.Workflows {
+		prefix := fmt.Sprintf("workflows[%d]", i)
+		if spec.Name == "" {
+			errs = append(errs, fmt.Errorf("%s: name is required", prefix))
+		}
+		if strings.Contains(spec.SpecKey(), "-->") {
+			errs = append(errs, fmt.Errorf("%s: key must not contain \"-->\"", prefix))
+		}
+		if previous, ok := keys[spec.SpecKey()]; ok {
+			errs = append(errs, fmt.Errorf("%s: duplicate key %q, already used by workflows[%d]", prefix, spec.SpecKey(), previous))
+		} else {
+			keys[spec.SpecKey()] = i
+		}
+
+		sections := []struct {
+			kind models.WorkflowableType
+			set  bool
+		}{
+			{models.WorkflowableTypeImport, spec.Import != nil},
+			{models.WorkflowableTypeExport, spec.Export != nil},
+			{models.WorkflowableTypeAction, spec.Action != nil},
+			{models.WorkflowableTypePipeline, spec.Pipeline != nil},
+		}
+		known := false
+		for _, section := range sections {
+			if section.kind == spec.Type {
+				known = true
+				if !section.set {
+					errs = append(errs, fmt.Errorf("%s: %s configuration is required", prefix, section.kind))
+				}
+			} else if section.set {
+				errs = append(errs, fmt.Errorf("%s: %s configuration set on a %s workflow", prefix, section.kind, spec.Type))
+			}
+		}
+		if !known {
+			errs = append(errs, fmt.Errorf("%s: unknown type %q", prefix, spec.Type))
+		}
+
+		if spec.Import != nil && (spec.Import.Connection == "" || spec.Import.Repository == "") {
+			errs = append(errs, fmt.Errorf("%s: import requires a connection and a repository", prefix))
+		}
+		if spec.Export != nil && (spec.Export.Connection == "" || spec.Export.Repository == "") {
+			errs = append(errs, fmt.Errorf("%s: export requires a connection and a repository", prefix))
+		}
+		if spec.Action != nil && spec.Action.Executable == "" {
+			errs = append(errs, fmt.Errorf("%s: action requires an executable", prefix))
+		}
+		if spec.Pipeline != nil && len(spec.Pipeline.Stages) == 0 {
+			errs = append(errs, fmt.Errorf("%s: pipeline requires at least one stage", prefix))
+		}
+		if spec.Pipeline != nil {
+			if _, err := WorkflowSpecStages(spec.Pipeline.Stages); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", prefix, err))
+			}
+		}
+		if _, err := WorkflowSpecSchedule(spec.Schedule); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", prefix, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WorkflowSpecStages converts pipeline stage specs to pipeline stages.
+func WorkflowSpecStages(specs []models.PipelineStageSpec) ([]models.PipelineStage, error) {
+	stages := make([]models.PipelineStage, 0, len(specs))
+	for i, spec := range specs {
+		common := models.CommonProperties{
+			Description: spec.Description,
+			Write:       spec.Write,
+			Read:        spec.Read,
+		}
+		switch spec.Type {
+		case "action":
+			stages = append(stages, &models.PipelineStageAction{
+				CommonProperties: common,
+				Type:             spec.Type,
+				Executable:       spec.Executable,
+			})
+		case "connection":
+			stages = append(stages, &models.PipelineStageConnection{
+				CommonProperties:    common,
+				Type:                spec.Type,
+				Connection:          models.Connection{ID: spec.Connection},
+				ConnectionWritePath: spec.ConnectionWritePath,
+				ConnectionReadPath:  spec.ConnectionReadPath,
+			})
+		case "repository":
+			stages = append(stages, &models.PipelineStageRepository{
+				CommonProperties: common,
+				Type:             spec.Type,
+				Repository:       models.Repository{Slug: spec.Repository},
+				Branch:           spec.Branch,
+				Path:             spec.Path,
+			})
+		default:
+			return nil, fmt.Errorf("unknown pipeline stage type %q at index %d", spec.Type, i)
+		}
+	}
+	return stages, nil
+}
+
+// WorkflowSpecSchedule converts a schedule spec to a workflow schedule. A nil spec returns nil.
+func WorkflowSpecSchedule(spec *models.WorkflowScheduleSpec) (*models.WorkflowSchedule, error) {
+	if spec == nil {
+		return nil, nil
+	}
+
+	schedule := &models.WorkflowSchedule{
+		Triggers:    make([]models.WorkflowTrigger, 0, len(spec.Triggers)),
+		MaxRetries:  spec.MaxRetries,
+		MaxRuntime:  spec.MaxRuntime,
+		MinInterval: spec.MinInterval,
+	}
+	for i, trigger := range spec.Triggers {
+		switch trigger.Type {
+		case "time":
+			if _, err := rrule.Parse(trigger.RRule); err != nil {
+				return nil, fmt.Errorf("invalid time trigger at index %d: %w", i, err)
+			}
+			schedule.Triggers = append(schedule.Triggers, &models.TimeTrigger{
+				Type:  trigger.Type,
+				RRule: trigger.RRule,
+			})
+		case "repository-event":
+			schedule.Triggers = append(schedule.Triggers, &models.RepositoryTrigger{
+				Type:       trigger.Type,
+				Event:      models.RepositoryEvent(trigger.Event),
+				Repository: trigger.Repository,
+				Ref:        trigger.Ref,
+			})
+		case "workflow-run-event":
+			schedule.Triggers = append(schedule.Triggers, &models.WorkflowRunTrigger{
+				Type:     trigger.Type,
+				Event:    models.WorkflowRunEvent(trigger.Event),
+				Workflow: trigger.Workflow,
+			})
+		default:
+			return nil, fmt.Errorf("unknown trigger type %q at index %d", trigger.Type, i)
+		}
+	}
+	return schedule, nil
+}
+
+// WorkflowToSpec converts an existing workflow to a spec, so it can be compared with a declared spec.
+func WorkflowToSpec(workflow models.Workflow) (models.WorkflowSpec, error) {
+	key, documentation := WorkflowSpecKey(workflow.Documentation)
+	spec := models.WorkflowSpec{
+		Key:           key,
+		Name:          workflow.Name,
+		Description:   workflow.Description,
+		Documentation: documentation,
+		Type:          workflow.Type,
+	}
+
+	switch workflow.Type {
+	case models.WorkflowableTypeImport:
+		config, ok := workflow.AsImport()
+		if !ok {
+			return spec, fmt.Errorf("workflow %s has no import configuration", workflow.ID)
+		}
+		spec.Import = &models.ImportSpec{
+			Connection: config.Connection.ID,
+			Repository: config.Repository.Slug,
+			Branch:     config.Branch,
+			Path:       config.Path,
+		}
+	case models.WorkflowableTypeExport:
+		config, ok := workflow.AsExport()
+		if !ok {
+			return spec, fmt.Errorf("workflow %s has no export configuration", workflow.ID)
+		}
+		spec.Export = &models.ExportSpec{
+			Connection: config.Connection.ID,
+			Repository: config.Repository.Slug,
+			Branch:     config.Branch,
+			Path:       config.Path,
+			Recursive:  config.Recursive,
+		}
+	case models.WorkflowableTypeAction:
+		config, ok := workflow.AsAction()
+		if !ok {
+			return spec, fmt.Errorf("workflow %s has no action configuration", workflow.ID)
+		}
+		spec.Action = &models.ActionSpec{Executable: config.Executable}
+		if config.Repository != nil {
+			spec.Action.Repository = config.Repository.Slug
+		}
+		if config.Branch != nil {
+			spec.Action.Branch = *config.Branch
+		}
+		if config.Path != nil {
+			spec.Action.Path = *config.Path
+		}
+	case models.WorkflowableTypePipeline:
+		config, ok := workflow.AsPipeline()
+		if !ok {
+			return spec, fmt.Errorf("workflow %s has no pipeline configuration", workflow.ID)
+		}
+		spec.Pipeline = &models.PipelineSpec{Live: config.Live, Stages: []models.PipelineStageSpec{}}
+		for _, stage := range config.Stages {
+			spec.Pipeline.Stages = append(spec.Pipeline.Stages, pipelineStageToSpec(stage))
+		}
+	default:
+		return spec, fmt.Errorf("workflow %s has unknown type %q", workflow.ID, workflow.Type)
+	}
+
+	if workflow.Schedule != nil {
+		spec.Schedule = scheduleToSpec(*workflow.Schedule)
+	}
+	return spec, nil
+}
+
+// pipelineStageToSpec converts a pipeline stage to a stage spec.
+func pipelineStageToSpec(stage models.PipelineStage) models.PipelineStageSpec {
+	spec := models.PipelineStageSpec{Type: stage.GetType()}
+	switch s := stage.(type) {
+	case *models.PipelineStageAction:
+		spec.Description, spec.Write, spec.Read = s.Description, s.Write, s.Read
+		spec.Executable = s.Executable
+	case *models.PipelineStageConnection:
+		spec.Description, spec.Write, spec.Read = s.Description, s.Write, s.Read
+		spec.Connection = s.Connection.ID
+		spec.ConnectionWritePath = s.ConnectionWritePath
+		spec.ConnectionReadPath = s.ConnectionReadPath
+	case *models.PipelineStageRepository:
+		spec.Description, spec.Write, spec.Read = s.Description, s.Write, s.Read
+		spec.Repository = s.Repository.Slug
+		spec.Branch = s.Branch
+		spec.Path = s.Path
+	}
+	return spec
+}
+
+// scheduleToSpec converts a workflow schedule to a schedule spec. An empty schedule returns nil.
+func scheduleToSpec(schedule models.WorkflowSchedule) *models.WorkflowScheduleSpec {
+	if len(schedule.Triggers) == 0 && schedule.MaxRetries == nil && schedule.MaxRuntime == nil && schedule.MinInterval == nil {
+		return nil
+	}
+
+	spec := &models.WorkflowScheduleSpec{
+		MaxRetries:  schedule.MaxRetries,
+		MaxRuntime:  schedule.MaxRuntime,
+		MinInterval: schedule.MinInterval,
+	}
+	for _, trigger := range schedule.Triggers {
+		triggerSpec := models.WorkflowTriggerSpec{Type: trigger.GetType()}
+		switch t := trigger.(type) {
+		case *models.TimeTrigger:
+			triggerSpec.RRule = t.RRule
+		case *models.RepositoryTrigger:
+			triggerSpec.Event = string(t.Event)
+			triggerSpec.Repository = t.Repository
+			triggerSpec.Ref = t.Ref
+		case *models.WorkflowRunTrigger:
+			triggerSpec.Event = string(t.Event)
+			triggerSpec.Workflow = t.Workflow
+		}
+		spec.Triggers = append(spec.Triggers, triggerSpec)
+	}
+	return spec
+}
+
+// WorkflowSpecKey extracts the spec key stored in the documentation of a managed workflow.
+// It returns an empty key if the workflow is not managed, along with the documentation without the marker.
+func WorkflowSpecKey(documentation string) (string, string) {
+	match := workflowSpecKeyPattern.FindStringSubmatchIndex(documentation)
+	if match == nil {
+		return "", documentation
+	}
+	return documentation[match[2]:match[3]], documentation[:match[0]]
+}
+
+// WithWorkflowSpecKey appends the spec key marker to the documentation of a workflow.
+// The marker is an HTML comment, so it is not shown when the markdown is rendered.
+func WithWorkflowSpecKey(documentation, key string) string {
+	_, documentation = WorkflowSpecKey(documentation)
+	marker := fmt.Sprintf("<!-- irmin-workflow-key: %s -->", key)
+	if documentation == "" {
+		return marker
+	}
+	return documentation + "\n\n" + marker
+}
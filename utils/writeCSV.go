@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// WriteCSV writes tabular rows as CSV with a header line.
+// Null values are written as empty fields and nested values as JSON text.
+func WriteCSV(w io.Writer, columns []string, rows [][]models.JSONValue) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	record := make([]string, len(columns))
+	for i, row := range rows {
+		for j := range columns {
+			var value models.JSONValue
+			if j < len(row) {
+				value = row[j]
+			}
+			field, err := formatCSVField(value)
+			if err != nil {
+				return fmt.Errorf("failed to format CSV row %d: %w", i, err)
+			}
+			record[j] = field
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV row %d: %w", i, err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatCSVField formats a single value as a CSV field.
+func formatCSVField(value models.JSONValue) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+)
+
+// WriteNDJSON writes tabular rows as newline-delimited JSON objects,
+// keeping the keys of every object in column order.
+func WriteNDJSON(w io.Writer, columns []string, rows [][]models.JSONValue) error {
+	for i, row := range rows {
+		line, err := marshalOrderedRow(columns, row)
+		if err != nil {
+			return fmt.Errorf("failed to encode NDJSON row %d: %w", i, err)
+		}
+		line = append(line, '\n')
+		if _, err := w.Write(line); err != nil {
+			return fmt.Errorf("failed to write NDJSON row %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// marshalOrderedRow encodes a row as a JSON object with keys in column order.
+func marshalOrderedRow(columns []string, row []models.JSONValue) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, column := range columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+
+		var value models.JSONValue
+		if i < len(row) {
+			value = row[i]
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -2,8 +2,11 @@ package utils
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 
+	"github.com/IrminData/irmin-sdk-go/models"
+
 	"github.com/xitongsys/parquet-go-source/writerfile"
 	"github.com/xitongsys/parquet-go/writer"
 )
@@ -35,3 +38,50 @@ func ConvertJSONToParquet(jsonData []string, schema string, parallelism int) ([]
 
 	return buffer.Bytes(), nil
 }
+
+// ConvertRowsToParquet converts tabular rows to Parquet using a JSON Schema.
+// Nested values in columns the schema declares as strings are stored as JSON text.
+func ConvertRowsToParquet(columns []string, rows [][]models.JSONValue, jsonSchema map[string]interface{}, parallelism int) ([]byte, error) {
+	parquetSchema, err := json.Marshal(JSONSchemaToParquet(jsonSchema, "root"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode Parquet schema: %w", err)
+	}
+
+	// Find the columns that must be written as strings
+	properties, _ := extractMap(jsonSchema, "properties")
+	stringColumns := make([]bool, len(columns))
+	for i, column := range columns {
+		property, _ := extractMap(properties, column)
+		for _, t := range getTypeList(property["type"]) {
+			if t == "string" {
+				stringColumns[i] = true
+			}
+		}
+	}
+
+	records := make([]string, 0, len(rows))
+	for i, row := range rows {
+		values := make([]models.JSONValue, len(columns))
+		for j := range columns {
+			if j >= len(row) {
+				continue
+			}
+			values[j] = row[j]
+			if _, isString := row[j].(string); stringColumns[j] && row[j] != nil && !isString {
+				text, err := formatCSVField(row[j])
+				if err != nil {
+					return nil, fmt.Errorf("failed to encode row %d: %w", i, err)
+				}
+				values[j] = text
+			}
+		}
+
+		record, err := marshalOrderedRow(columns, values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode row %d: %w", i, err)
+		}
+		records = append(records, string(record))
+	}
+
+	return ConvertJSONToParquet(records, string(parquetSchema), parallelism)
+}
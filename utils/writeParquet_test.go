@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/IrminData/irmin-sdk-go/models"
+
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// readParquetJSON reads every row of a Parquet file and returns them as JSON with sorted keys
+func readParquetJSON(t *testing.T, data []byte) string {
+	t.Helper()
+	pr, err := reader.NewParquetReader(buffer.NewBufferFileFromBytes(data), nil, 1)
+	if err != nil {
+		t.Fatalf("opening Parquet data: %v", err)
+	}
+	defer pr.ReadStop()
+
+	rows, err := pr.ReadByNumber(int(pr.GetNumRows()))
+	if err != nil {
+		t.Fatalf("reading Parquet rows: %v", err)
+	}
+	// Round trip through maps, as the field order follows the schema properties
+	data, err = json.Marshal(rows)
+	if err != nil {
+		t.Fatalf("encoding Parquet rows: %v", err)
+	}
+	var records []map[string]interface{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		t.Fatalf("decoding Parquet rows: %v", err)
+	}
+	data, err = json.Marshal(records)
+	if err != nil {
+		t.Fatalf("encoding Parquet rows: %v", err)
+	}
+	return string(data)
+}
+
+func TestConvertRowsToParquet(t *testing.T) {
+	columns := []string{"id", "name", "score", "tags"}
+	rows := [][]models.JSONValue{
+		{json.Number("1"), "Oslo", json.Number("1.5"), []interface{}{"capital", "port"}},
+		{json.Number("2"), nil, json.Number("3"), map[string]interface{}{"size": "small"}},
+		{json.Number("3"), "Bergen"},
+	}
+
+	tests := []struct {
+		name     string
+		schema   map[string]interface{}
+		expected string
+	}{
+		{
+			name:     "inferred schema",
+			schema:   InferJSONSchema(columns, rows),
+			expected: `[{"Id":1,"Name":"Oslo","Score":1.5,"Tags":"[\"capital\",\"port\"]"},{"Id":2,"Name":null,"Score":3,"Tags":"{\"size\":\"small\"}"},{"Id":3,"Name":"Bergen","Score":null,"Tags":null}]`,
+		},
+		{
+			name: "given schema",
+			schema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id":    map[string]interface{}{"type": "integer"},
+					"name":  map[string]interface{}{"type": []interface{}{"string", "null"}},
+					"score": map[string]interface{}{"type": []interface{}{"string", "null"}},
+					"tags":  map[string]interface{}{"type": []interface{}{"string", "null"}},
+				},
+				"required": []interface{}{"id"},
+			},
+			expected: `[{"Id":1,"Name":"Oslo","Score":"1.5","Tags":"[\"capital\",\"port\"]"},{"Id":2,"Name":null,"Score":"3","Tags":"{\"size\":\"small\"}"},{"Id":3,"Name":"Bergen","Score":null,"Tags":null}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := ConvertRowsToParquet(columns, rows, tt.schema, 1)
+			if err != nil {
+				t.Fatalf("converting rows: %v", err)
+			}
+			if got := readParquetJSON(t, data); got != tt.expected {
+				t.Errorf("got %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}